@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+func setupGRPCRouteController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
+	err := gatewayv1.Install(mgr.GetScheme())
+	if err != nil {
+		return errors.Wrapf(err, "failed to register gateway API scheme")
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &gatewayv1.GRPCRoute{}, controller.GatewayIndexField, controller.IndexParentGateways)
+	if err != nil {
+		return errors.Wrapf(err, "failed to index grpcroute parent gateways")
+	}
+
+	reconciler := controller.NewGRPCRouteReconciler(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetEventRecorder("grpcroute-monitor-controller"), svc, options)
+
+	// See setupHTTPRouteController.
+	b := builder.
+		ControllerManagedBy(mgr).
+		Named("grpcroute-monitor-controller").
+		For(&gatewayv1.GRPCRoute{}, builder.WithPredicates(controller.IgnoreStateAnnotationChanges())).
+		Watches(
+			&gatewayv1.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(reconciler.MapGatewayToGRPCRoutes),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToGRPCRoutes)
+	}
+
+	err = b.Complete(reconciler)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
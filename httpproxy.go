@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/contour"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func setupHTTPProxyController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
+	reconciler := controller.NewHTTPProxyReconciler(mgr.GetClient(), mgr.GetEventRecorder("httpproxy-monitor-controller"), svc, options)
+
+	b := builder.
+		ControllerManagedBy(mgr).
+		Named("httpproxy-monitor-controller").
+		For(contour.New(), builder.WithPredicates(controller.IgnoreStateAnnotationChanges()))
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToHTTPProxies)
+	}
+
+	return b.Complete(reconciler)
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
@@ -8,7 +10,9 @@ import (
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 func setupHTTPRouteController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
@@ -17,13 +21,31 @@ func setupHTTPRouteController(mgr manager.Manager, svc monitor.Service, options
 		return errors.Wrapf(err, "failed to register gateway API scheme")
 	}
 
-	reconciler := controller.NewHTTPRouteReconciler(mgr.GetClient(), svc, options)
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &gatewayv1.HTTPRoute{}, controller.GatewayIndexField, controller.IndexParentGateways)
+	if err != nil {
+		return errors.Wrapf(err, "failed to index httproute parent gateways")
+	}
+
+	reconciler := controller.NewHTTPRouteReconciler(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetEventRecorder("httproute-monitor-controller"), svc, options)
 
-	err = builder.
+	// Routes are re-reconciled whenever the spec of their parent Gateways
+	// changes, as the Gateway listeners determine the monitored scheme and
+	// port.
+	b := builder.
 		ControllerManagedBy(mgr).
 		Named("httproute-monitor-controller").
-		For(&gatewayv1.HTTPRoute{}).
-		Complete(reconciler)
+		For(&gatewayv1.HTTPRoute{}, builder.WithPredicates(controller.IgnoreStateAnnotationChanges())).
+		Watches(
+			&gatewayv1.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(reconciler.MapGatewayToHTTPRoutes),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToHTTPRoutes)
+	}
+
+	err = b.Complete(reconciler)
 	if err != nil {
 		return err
 	}
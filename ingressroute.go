@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/traefik"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func setupIngressRouteController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
+	reconciler := controller.NewIngressRouteReconciler(mgr.GetClient(), mgr.GetEventRecorder("ingressroute-monitor-controller"), svc, options)
+
+	b := builder.
+		ControllerManagedBy(mgr).
+		Named("ingressroute-monitor-controller").
+		For(traefik.New(), builder.WithPredicates(controller.IgnoreStateAnnotationChanges()))
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToIngressRoutes)
+	}
+
+	return b.Complete(reconciler)
+}
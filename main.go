@@ -5,20 +5,29 @@ import (
 	"fmt"
 	"os"
 
-	"dario.cat/mergo"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/blackbox"
+	_ "github.com/bonial-oss/ingress-monitor-controller/pkg/provider/builtin"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	runtime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	restconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var (
@@ -78,29 +87,52 @@ func Run(options *config.Options) error {
 			return errors.Wrapf(err, "failed to load provider config from file")
 		}
 
-		err = mergo.Merge(&options.ProviderConfig, providerConfig, mergo.WithOverride)
-		if err != nil {
-			return errors.Wrapf(err, "failed to merge provider configs")
-		}
+		options.ProviderConfig = providerConfig
 	}
 
-	mgr, err := manager.New(restconfig.GetConfigOrDie(), manager.Options{})
+	mgr, err := manager.New(restconfig.GetConfigOrDie(), manager.Options{
+		Cache:                  newCacheOptions(options),
+		Client:                 newClientOptions(),
+		HealthProbeBindAddress: options.HealthProbeBindAddress,
+	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to create controller manager")
 	}
 
-	svc, err := monitor.NewService(options)
+	svc, err := monitor.NewService(options, mgr.GetClient())
 	if err != nil {
 		return errors.Wrapf(err, "failed to initialize monitor service")
 	}
 
-	reconciler := controller.NewIngressReconciler(mgr.GetClient(), svc, options)
+	err = mgr.AddHealthzCheck("ping", healthz.Ping)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add health check")
+	}
+
+	err = mgr.AddReadyzCheck("providers", svc.CheckProviders)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add provider readiness check")
+	}
+
+	if options.EnableMonitorPolicy || options.EnableMonitorResource {
+		err = v1alpha1.AddToScheme(mgr.GetScheme())
+		if err != nil {
+			return errors.Wrapf(err, "failed to register ingress-monitor API scheme")
+		}
+	}
+
+	reconciler := controller.NewIngressReconciler(mgr.GetClient(), mgr.GetEventRecorder("ingress-monitor-controller"), svc, options)
 
-	err = builder.
+	b := builder.
 		ControllerManagedBy(mgr).
 		Named("ingress-monitor-controller").
-		For(&networkingv1.Ingress{}).
-		Complete(reconciler)
+		For(&networkingv1.Ingress{}, builder.WithPredicates(controller.IgnoreStateAnnotationChanges()))
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToIngresses)
+	}
+
+	err = b.Complete(reconciler)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create ingress controller")
 	}
@@ -112,6 +144,62 @@ func Run(options *config.Options) error {
 		}
 	}
 
+	if options.EnableGRPCRoute {
+		err = setupGRPCRouteController(mgr, svc, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create grpcroute controller")
+		}
+	}
+
+	if options.EnableTLSRoute {
+		err = setupTLSRouteController(mgr, svc, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create tlsroute controller")
+		}
+	}
+
+	if options.EnableService {
+		err = setupServiceController(mgr, svc, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create service controller")
+		}
+	}
+
+	if options.EnableOpenShiftRoute {
+		err = setupRouteController(mgr, svc, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create openshift route controller")
+		}
+	}
+
+	if options.EnableTraefik {
+		err = setupIngressRouteController(mgr, svc, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create traefik ingressroute controller")
+		}
+	}
+
+	if options.EnableContour {
+		err = setupHTTPProxyController(mgr, svc, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create contour httpproxy controller")
+		}
+	}
+
+	if options.EnableMonitorResource {
+		err = setupMonitorController(mgr, svc, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create monitor controller")
+		}
+	}
+
+	if options.GCInterval > 0 {
+		err = mgr.Add(controller.NewGarbageCollector(mgr.GetClient(), svc, options))
+		if err != nil {
+			return errors.Wrapf(err, "failed to add garbage collector")
+		}
+	}
+
 	err = mgr.Start(signals.SetupSignalHandler())
 	if err != nil {
 		return errors.Wrapf(err, "unable to run manager")
@@ -119,3 +207,55 @@ func Run(options *config.Options) error {
 
 	return nil
 }
+
+// watchPolicies configures b to enqueue the objects returned by mapFn
+// whenever the spec of a MonitorPolicy or ClusterMonitorPolicy changes.
+func watchPolicies(b *builder.TypedBuilder[reconcile.Request], mapFn handler.MapFunc) *builder.TypedBuilder[reconcile.Request] {
+	return b.
+		Watches(
+			&v1alpha1.MonitorPolicy{},
+			handler.EnqueueRequestsFromMapFunc(mapFn),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&v1alpha1.ClusterMonitorPolicy{},
+			handler.EnqueueRequestsFromMapFunc(mapFn),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+}
+
+// newCacheOptions creates the cache options for the controller manager. If
+// namespaces to watch are configured, the cache is restricted to these
+// namespaces, which allows running the controller with namespaced RBAC.
+func newCacheOptions(options *config.Options) cache.Options {
+	namespaces := options.WatchNamespaces()
+	if len(namespaces) == 0 {
+		return cache.Options{}
+	}
+
+	log.Info("restricting controller to namespaces", "namespaces", namespaces)
+
+	defaultNamespaces := make(map[string]cache.Config, len(namespaces))
+	for _, namespace := range namespaces {
+		defaultNamespaces[namespace] = cache.Config{}
+	}
+
+	return cache.Options{
+		DefaultNamespaces: defaultNamespaces,
+	}
+}
+
+// newClientOptions creates the client options for the controller manager.
+// Unstructured objects (e.g. OpenShift Routes) are read from the cache as
+// well. ConfigMaps are always read directly from the API server to avoid
+// caching all ConfigMaps in the cluster. The same applies to the Probes of
+// the blackbox provider, which live in the configured probe namespace that
+// may not be watched by the cache.
+func newClientOptions() client.Options {
+	return client.Options{
+		Cache: &client.CacheOptions{
+			DisableFor:   []client.Object{&corev1.ConfigMap{}, blackbox.NewProbe()},
+			Unstructured: true,
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+func setupMonitorController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
+	reconciler := controller.NewMonitorReconciler(mgr.GetClient(), mgr.GetEventRecorder("monitor-controller"), svc, options)
+
+	// Only spec changes are relevant. Without the predicate, every status
+	// update would trigger another reconciliation.
+	return builder.
+		ControllerManagedBy(mgr).
+		Named("monitor-controller").
+		For(&v1alpha1.Monitor{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(reconciler)
+}
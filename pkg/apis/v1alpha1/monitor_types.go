@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MonitorConditionSynced is the type of the condition which reports whether
+// the monitor of a Monitor resource is in sync with its spec.
+const MonitorConditionSynced = "Synced"
+
+// MonitorSpec defines the desired state of a monitor for an endpoint that is
+// not backed by an Ingress or HTTPRoute.
+type MonitorSpec struct {
+	// URL is the URL to monitor. It must be an absolute http or https URL.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Site24x7 contains settings for Site24x7 monitors.
+	// +optional
+	Site24x7 *Site24x7Settings `json:"site24x7,omitempty"`
+
+	// Annotations contains settings for arbitrary provider annotations, e.g.
+	// for providers other than Site24x7. Dedicated fields take precedence.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// MonitorStatus defines the observed state of a Monitor.
+type MonitorStatus struct {
+	// ID is the provider specific ID of the monitor.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name is the name of the monitor at the provider.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last
+	// reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the time at which the monitor was last synced
+	// successfully.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Conditions contains the Synced condition of the monitor.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=mon
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+// +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.id`
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Monitor is a monitor for an endpoint that is not backed by an Ingress or
+// HTTPRoute, e.g. a LoadBalancer Service or an external URL.
+type Monitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MonitorSpec   `json:"spec,omitempty"`
+	Status MonitorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MonitorList contains a list of Monitor.
+type MonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Monitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Monitor{}, &MonitorList{})
+}
@@ -0,0 +1,108 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MonitorPolicySpec defines monitor defaults for all resources a policy
+// applies to. The defaults are merged in the order global provider defaults
+// < ClusterMonitorPolicy < MonitorPolicy < resource annotations, so every
+// value can still be overridden per resource.
+type MonitorPolicySpec struct {
+	// IngressClassNames restricts the policy to Ingresses of the given
+	// classes. If empty, the policy applies to all resources in its scope.
+	// +optional
+	IngressClassNames []string `json:"ingressClassNames,omitempty"`
+
+	// PathOverride is the default for the
+	// ingress-monitor.bonial.com/path-override annotation.
+	// +optional
+	PathOverride string `json:"pathOverride,omitempty"`
+
+	// Site24x7 contains defaults for Site24x7 monitors.
+	// +optional
+	Site24x7 *Site24x7Settings `json:"site24x7,omitempty"`
+
+	// Annotations contains defaults for arbitrary annotations. It can be used
+	// to set defaults for annotations that have no dedicated field, e.g. for
+	// other providers. Dedicated fields take precedence.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Site24x7Settings contains settings for Site24x7 monitors. Each field
+// corresponds to the site24x7.ingress-monitor.bonial.com annotation of the
+// same name.
+type Site24x7Settings struct {
+	// CheckFrequency is the check interval in minutes.
+	// +optional
+	CheckFrequency string `json:"checkFrequency,omitempty"`
+
+	// LocationProfileID is the ID of the location profile.
+	// +optional
+	LocationProfileID string `json:"locationProfileID,omitempty"`
+
+	// NotificationProfileID is the ID of the notification profile.
+	// +optional
+	NotificationProfileID string `json:"notificationProfileID,omitempty"`
+
+	// ThresholdProfileID is the ID of the threshold profile.
+	// +optional
+	ThresholdProfileID string `json:"thresholdProfileID,omitempty"`
+
+	// MonitorGroupIDs are the IDs of the monitor groups.
+	// +optional
+	MonitorGroupIDs []string `json:"monitorGroupIDs,omitempty"`
+
+	// UserGroupIDs are the IDs of the user groups to alert.
+	// +optional
+	UserGroupIDs []string `json:"userGroupIDs,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=mpol
+
+// MonitorPolicy defines monitor defaults for the monitored resources in its
+// namespace.
+type MonitorPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MonitorPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MonitorPolicyList contains a list of MonitorPolicy.
+type MonitorPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MonitorPolicy `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=cmpol
+
+// ClusterMonitorPolicy defines monitor defaults for the monitored resources
+// in all namespaces.
+type ClusterMonitorPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MonitorPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterMonitorPolicyList contains a list of ClusterMonitorPolicy.
+type ClusterMonitorPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterMonitorPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MonitorPolicy{}, &MonitorPolicyList{}, &ClusterMonitorPolicy{}, &ClusterMonitorPolicyList{})
+}
@@ -26,6 +26,81 @@ const (
 	// AnnotationPathOverride configures a custom path that should be monitored
 	// (e.g. "/health").
 	AnnotationPathOverride = "ingress-monitor.bonial.com/path-override"
+
+	// AnnotationMultiHost controls whether one monitor per distinct host is
+	// created instead of only monitoring the first host. Overrides the
+	// --multi-host flag if set.
+	AnnotationMultiHost = "ingress-monitor.bonial.com/multi-host"
+
+	// AnnotationMultiPath controls whether one monitor per rule path is
+	// created in addition to one monitor per host. Only has an effect if
+	// multi host monitoring is enabled.
+	AnnotationMultiPath = "ingress-monitor.bonial.com/multi-path"
+
+	// AnnotationPort configures the port that is monitored for a Service.
+	// Defaults to the first port of the Service (Service only).
+	AnnotationPort = "ingress-monitor.bonial.com/port"
+
+	// AnnotationScheme configures the scheme ("http" or "https") that is
+	// monitored for a Service. Defaults to "https" if the monitored port is
+	// 443 and "http" otherwise (Service only).
+	AnnotationScheme = "ingress-monitor.bonial.com/scheme"
+
+	// AnnotationProviders selects the providers that manage the monitors of
+	// a resource as a comma separated list, e.g. "site24x7,uptimekuma". All
+	// selected providers have to be enabled via --providers. Defaults to the
+	// providers configured via --provider.
+	AnnotationProviders = "ingress-monitor.bonial.com/providers"
+
+	// AnnotationMonitoredHosts is managed by the controller and must not be
+	// edited manually. It records the hosts (and paths) that monitors were
+	// created for in multi host mode, so that monitors of hosts that were
+	// removed from the resource can be cleaned up.
+	AnnotationMonitoredHosts = "ingress-monitor.bonial.com/monitored-hosts"
+)
+
+// State Annotations. These are managed by the controller and must not be
+// edited manually. They expose the state of the monitors of a resource, so
+// that it is visible via `kubectl describe`. If a resource has multiple
+// monitors, the values are comma-separated.
+const (
+	// AnnotationMonitorID records the provider IDs of the monitors.
+	AnnotationMonitorID = "ingress-monitor.bonial.com/monitor-id"
+
+	// AnnotationMonitorName records the names of the monitors.
+	AnnotationMonitorName = "ingress-monitor.bonial.com/monitor-name"
+
+	// AnnotationLastSync records the time of the last successful monitor
+	// sync that changed a monitor or the monitor state in RFC3339 format.
+	// Syncs of monitors that are already up to date do not update it.
+	AnnotationLastSync = "ingress-monitor.bonial.com/last-sync"
+
+	// AnnotationLastError records the error of the last failed monitor sync,
+	// e.g. a validation error or an error returned by the monitor provider.
+	// It is removed after the next successful sync.
+	AnnotationLastError = "ingress-monitor.bonial.com/last-error"
+)
+
+// AnnotationPrefix is the prefix of the global and state annotations. They
+// are validated for all providers and unknown annotations are rejected.
+const AnnotationPrefix = "ingress-monitor.bonial.com/"
+
+// StateAnnotations are all annotations that are managed by the controller.
+// They record the state of the monitors and do not configure them.
+var StateAnnotations = []string{
+	AnnotationMonitoredHosts,
+	AnnotationMonitorID,
+	AnnotationMonitorName,
+	AnnotationLastSync,
+	AnnotationLastError,
+}
+
+// Annotation prefixes of the providers. Annotations with the prefix of a
+// provider are validated by it and unknown annotations are rejected.
+const (
+	AnnotationPrefixSite24x7   = "site24x7.ingress-monitor.bonial.com/"
+	AnnotationPrefixUptimeKuma = "uptimekuma.ingress-monitor.bonial.com/"
+	AnnotationPrefixBlackbox   = "blackbox.ingress-monitor.bonial.com/"
 )
 
 // Site24x7 Provider Annotations.
@@ -103,6 +178,59 @@ const (
 	AnnotationSite24x7UserGroupIDs = "site24x7.ingress-monitor.bonial.com/user-group-ids"
 )
 
+// Uptime Kuma Provider Annotations.
+const (
+	// AnnotationUptimeKumaAcceptedStatusCodes overrides the HTTP status codes
+	// that are considered successful. Expects a comma separated list of status
+	// codes or status code ranges, e.g. "200-299,301".
+	AnnotationUptimeKumaAcceptedStatusCodes = "uptimekuma.ingress-monitor.bonial.com/accepted-status-codes"
+
+	// AnnotationUptimeKumaIgnoreTLS configures whether TLS/SSL errors should
+	// be ignored. If "true", TLS errors are ignored.
+	AnnotationUptimeKumaIgnoreTLS = "uptimekuma.ingress-monitor.bonial.com/ignore-tls"
+
+	// AnnotationUptimeKumaInterval overrides the check interval in seconds.
+	AnnotationUptimeKumaInterval = "uptimekuma.ingress-monitor.bonial.com/interval"
+
+	// AnnotationUptimeKumaMaxRedirects overrides the maximum number of
+	// redirects to follow.
+	AnnotationUptimeKumaMaxRedirects = "uptimekuma.ingress-monitor.bonial.com/max-redirects"
+
+	// AnnotationUptimeKumaMaxRetries overrides the number of retries before
+	// the monitor is marked as down.
+	AnnotationUptimeKumaMaxRetries = "uptimekuma.ingress-monitor.bonial.com/max-retries"
+
+	// AnnotationUptimeKumaMethod overrides the HTTP method to use for the
+	// check, e.g. "HEAD".
+	AnnotationUptimeKumaMethod = "uptimekuma.ingress-monitor.bonial.com/method"
+
+	// AnnotationUptimeKumaNotificationIDs overrides the notifications that
+	// should be triggered if the monitor goes down. Expects a comma separated
+	// list of notification IDs.
+	AnnotationUptimeKumaNotificationIDs = "uptimekuma.ingress-monitor.bonial.com/notification-ids"
+
+	// AnnotationUptimeKumaRetryInterval overrides the interval in seconds
+	// between retries.
+	AnnotationUptimeKumaRetryInterval = "uptimekuma.ingress-monitor.bonial.com/retry-interval"
+)
+
+// Blackbox Provider Annotations.
+const (
+	// AnnotationBlackboxInterval overrides the scrape interval of the probe,
+	// e.g. "30s".
+	AnnotationBlackboxInterval = "blackbox.ingress-monitor.bonial.com/interval"
+
+	// AnnotationBlackboxLabels configures additional labels for the probe
+	// target. Expects a comma separated list of key=value pairs, e.g.
+	// "team=platform,severity=critical". The labels are merged with the
+	// default labels from the provider config.
+	AnnotationBlackboxLabels = "blackbox.ingress-monitor.bonial.com/labels"
+
+	// AnnotationBlackboxModule overrides the blackbox_exporter module used
+	// for probing, e.g. "http_post_2xx".
+	AnnotationBlackboxModule = "blackbox.ingress-monitor.bonial.com/module"
+)
+
 // Annotations is a container for ingress annotations with added functionality
 // for parsing and defaulting annotation values.
 type Annotations map[string]string
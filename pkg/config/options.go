@@ -1,6 +1,8 @@
 package config
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -14,38 +16,127 @@ const (
 
 	// DefaultNameTemplate is the default template used for naming monitors.
 	DefaultNameTemplate = "{{.Namespace}}-{{.IngressName}}"
+
+	// DefaultMultiHostNameTemplate is the default template used for naming
+	// monitors if one monitor per host is created.
+	DefaultMultiHostNameTemplate = "{{.Namespace}}-{{.IngressName}}-{{.Host}}{{.Path}}"
+
+	// DefaultProviderRateLimit is the default number of calls per second
+	// that are allowed to each provider.
+	DefaultProviderRateLimit = 10
+
+	// DefaultProviderRateLimitBurst is the default number of calls that are
+	// allowed to each provider in a single burst.
+	DefaultProviderRateLimitBurst = 20
+
+	// DefaultProviderMaxRetries is the default number of retries for provider
+	// calls that failed with a retryable error.
+	DefaultProviderMaxRetries = 3
+
+	// DefaultProviderRetryBackoff is the default delay before the first
+	// retry of a provider call. It is doubled for each further retry.
+	DefaultProviderRetryBackoff = time.Second
+
+	// DefaultProviderBreakerFailureThreshold is the default number of
+	// consecutive failed provider calls after which the circuit breaker of
+	// the provider opens.
+	DefaultProviderBreakerFailureThreshold = 5
+
+	// DefaultProviderBreakerCooldown is the default duration for which the
+	// circuit breaker of a provider stays open before calls are attempted
+	// again.
+	DefaultProviderBreakerCooldown = time.Minute
+
+	// DefaultHealthProbeBindAddress is the default address the health probe
+	// endpoints bind to.
+	DefaultHealthProbeBindAddress = ":8081"
 )
 
 // Options holds the options that can be configured via cli flags.
 type Options struct {
-	ProviderConfigFile string
-	Namespace          string
-	ProviderName       string
-	NameTemplate       string
-	NoDelete           bool
-	CreationDelay      time.Duration
-	EnableHTTPRoute    bool
-	ProviderConfig     ProviderConfig
+	ProviderConfigFile              string
+	Namespace                       string
+	ProviderName                    string
+	Providers                       string
+	NameTemplate                    string
+	MultiHostNameTemplate           string
+	MultiHost                       bool
+	NoDelete                        bool
+	UseFinalizer                    bool
+	CreationDelay                   time.Duration
+	EnableHTTPRoute                 bool
+	EnableGRPCRoute                 bool
+	EnableTLSRoute                  bool
+	DryRun                          bool
+	EnableMonitorPolicy             bool
+	EnableMonitorResource           bool
+	EnableService                   bool
+	EnableOpenShiftRoute            bool
+	EnableTraefik                   bool
+	EnableContour                   bool
+	GCInterval                      time.Duration
+	GCDryRun                        bool
+	GCNamePrefix                    string
+	GCUniqueNamePrefix              bool
+	ProviderConfig                  ProviderConfig
+	ProviderRateLimit               float64
+	ProviderRateLimitBurst          int
+	ProviderMaxRetries              int
+	ProviderRetryBackoff            time.Duration
+	ProviderBreakerFailureThreshold int
+	ProviderBreakerCooldown         time.Duration
+	HealthProbeBindAddress          string
 }
 
 // NewDefaultOptions creates a new *Options value with defaults set.
 func NewDefaultOptions() *Options {
 	return &Options{
-		ProviderName:   DefaultProvider,
-		NameTemplate:   DefaultNameTemplate,
-		ProviderConfig: NewDefaultProviderConfig(),
+		ProviderName:                    DefaultProvider,
+		NameTemplate:                    DefaultNameTemplate,
+		MultiHostNameTemplate:           DefaultMultiHostNameTemplate,
+		ProviderRateLimit:               DefaultProviderRateLimit,
+		ProviderRateLimitBurst:          DefaultProviderRateLimitBurst,
+		ProviderMaxRetries:              DefaultProviderMaxRetries,
+		ProviderRetryBackoff:            DefaultProviderRetryBackoff,
+		ProviderBreakerFailureThreshold: DefaultProviderBreakerFailureThreshold,
+		ProviderBreakerCooldown:         DefaultProviderBreakerCooldown,
+		HealthProbeBindAddress:          DefaultHealthProbeBindAddress,
 	}
 }
 
 // AddFlags adds cli flags for configurable options to the command.
 func (o *Options) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.NoDelete, "no-delete", o.NoDelete, "If set, monitors will not be deleted if the ingress is deleted.")
+	cmd.Flags().BoolVar(&o.UseFinalizer, "use-finalizer", o.UseFinalizer, "If set, a finalizer is added to monitored resources, so that their monitors are deleted even if the controller is not running while a resource is deleted.")
 	cmd.Flags().DurationVar(&o.CreationDelay, "creation-delay", o.CreationDelay, "Duration to wait after an ingress is created before creating the monitor for it.")
 	cmd.Flags().StringVar(&o.NameTemplate, "name-template", o.NameTemplate, "The template to use for the monitor name. Valid fields are: .IngressName, .Namespace.")
-	cmd.Flags().StringVar(&o.Namespace, "namespace", o.Namespace, "Namespace to watch. If empty, all namespaces are watched.")
+	cmd.Flags().StringVar(&o.MultiHostNameTemplate, "multi-host-name-template", o.MultiHostNameTemplate, "The template to use for the monitor name if one monitor per host is created. Valid fields are: .IngressName, .Namespace, .Host, .Path.")
+	cmd.Flags().BoolVar(&o.MultiHost, "multi-host", o.MultiHost, "If set, one monitor per distinct host is created instead of only monitoring the first host. Can be overridden per resource via annotation.")
+	cmd.Flags().StringVar(&o.Namespace, "namespace", o.Namespace, "Namespace to watch. Accepts a comma separated list of namespaces. If empty, all namespaces are watched.")
 	cmd.Flags().StringVar(&o.ProviderConfigFile, "provider-config", o.ProviderConfigFile, "Location of the config file for the monitor providers.")
 	cmd.Flags().BoolVar(&o.EnableHTTPRoute, "enable-httproute", o.EnableHTTPRoute, "Enable watching Gateway API HTTPRoute resources for monitor creation.")
-	cmd.Flags().StringVar(&o.ProviderName, "provider", o.ProviderName, "The provider to use for creating monitors.")
+	cmd.Flags().BoolVar(&o.EnableGRPCRoute, "enable-grpcroute", o.EnableGRPCRoute, "Enable watching Gateway API GRPCRoute resources for gRPC health check monitor creation.")
+	cmd.Flags().BoolVar(&o.EnableTLSRoute, "enable-tlsroute", o.EnableTLSRoute, "Enable watching Gateway API TLSRoute resources for TLS monitor creation. Requires the experimental TLSRoute CRD to be installed.")
+	cmd.Flags().StringVar(&o.ProviderName, "provider", o.ProviderName, "The default provider to use for creating monitors. Accepts a comma separated list of providers to mirror monitors into multiple providers. Can be overridden per resource via annotation.")
+	cmd.Flags().StringVar(&o.Providers, "providers", o.Providers, "Comma separated list of enabled providers that resources may select via annotation. Must include all default providers. If empty, only the default providers are enabled.")
+	cmd.Flags().BoolVar(&o.EnableMonitorPolicy, "enable-monitor-policy", o.EnableMonitorPolicy, "Enable MonitorPolicy and ClusterMonitorPolicy resources for per-namespace and per-class monitor defaults. Requires the CRDs to be installed.")
+	cmd.Flags().BoolVar(&o.EnableService, "enable-service", o.EnableService, "Enable watching Services of type LoadBalancer for monitor creation.")
+	cmd.Flags().BoolVar(&o.EnableOpenShiftRoute, "enable-openshift-route", o.EnableOpenShiftRoute, "Enable watching OpenShift Route resources for monitor creation.")
+	cmd.Flags().BoolVar(&o.EnableTraefik, "enable-traefik-ingressroute", o.EnableTraefik, "Enable watching Traefik IngressRoute resources for monitor creation.")
+	cmd.Flags().BoolVar(&o.EnableContour, "enable-contour-httpproxy", o.EnableContour, "Enable watching Contour HTTPProxy resources for monitor creation.")
+	cmd.Flags().BoolVar(&o.EnableMonitorResource, "enable-monitor-resource", o.EnableMonitorResource, "Enable watching Monitor resources for monitor creation. Requires the CRDs to be installed.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "If set, monitor creations, updates and deletions are only logged instead of being sent to the provider.")
+	cmd.Flags().DurationVar(&o.GCInterval, "gc-interval", o.GCInterval, "Interval in which orphaned monitors are garbage collected. Garbage collection is disabled if 0s.")
+	cmd.Flags().BoolVar(&o.GCDryRun, "gc-dry-run", o.GCDryRun, "If set, orphaned monitors are only reported and not deleted by the garbage collection.")
+	cmd.Flags().Float64Var(&o.ProviderRateLimit, "provider-rate-limit", o.ProviderRateLimit, "Maximum number of calls per second to each provider. Rate limiting is disabled if 0.")
+	cmd.Flags().IntVar(&o.ProviderRateLimitBurst, "provider-rate-limit-burst", o.ProviderRateLimitBurst, "Maximum number of calls to each provider in a single burst.")
+	cmd.Flags().IntVar(&o.ProviderMaxRetries, "provider-max-retries", o.ProviderMaxRetries, "Maximum number of retries for provider calls that failed with a retryable error, e.g. a network error or a 429 or 5xx response. Retries are disabled if 0.")
+	cmd.Flags().DurationVar(&o.ProviderRetryBackoff, "provider-retry-backoff", o.ProviderRetryBackoff, "Delay before the first retry of a failed provider call. The delay is doubled for each further retry.")
+	cmd.Flags().IntVar(&o.ProviderBreakerFailureThreshold, "provider-breaker-failure-threshold", o.ProviderBreakerFailureThreshold, "Number of consecutive failed provider calls after which calls to the provider are short-circuited. The circuit breaker is disabled if 0.")
+	cmd.Flags().DurationVar(&o.ProviderBreakerCooldown, "provider-breaker-cooldown", o.ProviderBreakerCooldown, "Duration for which calls to an unhealthy provider are short-circuited before they are attempted again.")
+	cmd.Flags().StringVar(&o.HealthProbeBindAddress, "health-probe-bind-address", o.HealthProbeBindAddress, "The address the health probe endpoints bind to. Disabled if empty.")
+	cmd.Flags().StringVar(&o.GCNamePrefix, "gc-name-prefix", o.GCNamePrefix, "Name prefix of the monitors owned by the controller. Only monitors with this prefix are considered for garbage collection. Required if garbage collection is enabled. Both name templates must start with it.")
+	cmd.Flags().BoolVar(&o.GCUniqueNamePrefix, "gc-unique-name-prefix", o.GCUniqueNamePrefix, "Confirms that no other controller instance uses --gc-name-prefix. Required for garbage collection if --namespace is set, as monitors of resources in other namespaces are considered orphaned.")
 }
 
 // Validate validates options.
@@ -58,9 +149,121 @@ func (o *Options) Validate() error {
 		return errors.Errorf("--name-template must not be empty")
 	}
 
-	if o.ProviderName == "" {
+	if o.MultiHostNameTemplate == "" {
+		return errors.Errorf("--multi-host-name-template must not be empty")
+	}
+
+	if len(o.DefaultProviders()) == 0 {
 		return errors.Errorf("--provider must not be empty")
 	}
 
+	enabled := o.EnabledProviders()
+
+	for _, name := range o.DefaultProviders() {
+		if !slices.Contains(enabled, name) {
+			return errors.Errorf("--providers must include default provider %q", name)
+		}
+	}
+
+	if o.ProviderRateLimit < 0 {
+		return errors.Errorf("--provider-rate-limit has to be greater than or equal to 0")
+	}
+
+	if o.ProviderRateLimit > 0 && o.ProviderRateLimitBurst < 1 {
+		return errors.Errorf("--provider-rate-limit-burst has to be greater than 0 if rate limiting is enabled")
+	}
+
+	if o.ProviderMaxRetries < 0 {
+		return errors.Errorf("--provider-max-retries has to be greater than or equal to 0")
+	}
+
+	if o.ProviderMaxRetries > 0 && o.ProviderRetryBackoff <= 0 {
+		return errors.Errorf("--provider-retry-backoff has to be greater than 0s if retries are enabled")
+	}
+
+	if o.ProviderBreakerFailureThreshold < 0 {
+		return errors.Errorf("--provider-breaker-failure-threshold has to be greater than or equal to 0")
+	}
+
+	if o.ProviderBreakerFailureThreshold > 0 && o.ProviderBreakerCooldown <= 0 {
+		return errors.Errorf("--provider-breaker-cooldown has to be greater than 0s if the circuit breaker is enabled")
+	}
+
+	if o.GCInterval < 0 {
+		return errors.Errorf("--gc-interval has to be greater than or equal to 0s")
+	}
+
+	if o.GCInterval > 0 {
+		err := o.validateGC()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateGC validates the garbage collection options. Monitors are only
+// collected if their name starts with the prefix, so the name templates have
+// to start with it. Controllers that only watch some namespaces consider the
+// monitors of all other namespaces orphaned, so their prefix must not be
+// shared with other controllers.
+func (o *Options) validateGC() error {
+	if o.GCNamePrefix == "" {
+		return errors.Errorf("--gc-name-prefix must not be empty if garbage collection is enabled")
+	}
+
+	if !strings.HasPrefix(o.NameTemplate, o.GCNamePrefix) {
+		return errors.Errorf("--name-template must start with --gc-name-prefix %q if garbage collection is enabled", o.GCNamePrefix)
+	}
+
+	if !strings.HasPrefix(o.MultiHostNameTemplate, o.GCNamePrefix) {
+		return errors.Errorf("--multi-host-name-template must start with --gc-name-prefix %q if garbage collection is enabled", o.GCNamePrefix)
+	}
+
+	if len(o.WatchNamespaces()) > 0 && !o.GCUniqueNamePrefix {
+		return errors.Errorf("garbage collection with --namespace deletes the monitors of all other namespaces that start with --gc-name-prefix, set --gc-unique-name-prefix to confirm that the prefix is unique to this controller")
+	}
+
 	return nil
 }
+
+// WatchNamespaces returns the namespaces that should be watched by the
+// controller. The namespace option may contain a comma separated list of
+// namespaces. Returns nil if all namespaces should be watched.
+func (o *Options) WatchNamespaces() []string {
+	return splitList(o.Namespace)
+}
+
+// DefaultProviders returns the names of the providers that are used for
+// resources which do not select providers via annotation. The provider option
+// may contain a comma separated list of providers.
+func (o *Options) DefaultProviders() []string {
+	return splitList(o.ProviderName)
+}
+
+// EnabledProviders returns the names of all providers that monitors can be
+// managed by. If the providers option is empty, only the default providers
+// are enabled.
+func (o *Options) EnabledProviders() []string {
+	providers := splitList(o.Providers)
+	if len(providers) == 0 {
+		return o.DefaultProviders()
+	}
+
+	return providers
+}
+
+// splitList splits a comma separated list and drops empty elements.
+func splitList(list string) []string {
+	var elems []string
+
+	for _, elem := range strings.Split(list, ",") {
+		elem = strings.TrimSpace(elem)
+		if elem != "" {
+			elems = append(elems, elem)
+		}
+	}
+
+	return elems
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,6 +28,34 @@ func TestOptions_Validate(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name: "multiple default providers",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.ProviderName = "site24x7,uptimekuma"
+				return o
+			}(),
+			valid: true,
+		},
+		{
+			name: "enabled providers must include default providers",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.ProviderName = "site24x7,uptimekuma"
+				o.Providers = "site24x7,blackbox"
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "enabled providers with default provider",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.Providers = "site24x7,blackbox"
+				return o
+			}(),
+			valid: true,
+		},
 		{
 			name: "name template must not be empty",
 			options: func() *Options {
@@ -35,6 +65,144 @@ func TestOptions_Validate(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name: "multi host name template must not be empty",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.MultiHostNameTemplate = ""
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "provider rate limit must not be negative",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.ProviderRateLimit = -1
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "provider rate limit burst must be positive if rate limiting is enabled",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.ProviderRateLimitBurst = 0
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "provider retry backoff must be positive if retries are enabled",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.ProviderRetryBackoff = 0
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "provider breaker cooldown must be positive if the breaker is enabled",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.ProviderBreakerCooldown = 0
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "provider middleware can be disabled",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.ProviderRateLimit = 0
+				o.ProviderRateLimitBurst = 0
+				o.ProviderMaxRetries = 0
+				o.ProviderRetryBackoff = 0
+				o.ProviderBreakerFailureThreshold = 0
+				o.ProviderBreakerCooldown = 0
+				return o
+			}(),
+			valid: true,
+		},
+		{
+			name: "gc interval must not be negative",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.GCInterval = -time.Minute
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "gc name prefix must not be empty if gc is enabled",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.GCInterval = time.Minute
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "gc with name prefix",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.GCInterval = time.Minute
+				o.GCNamePrefix = "cluster-a-"
+				o.NameTemplate = "cluster-a-{{.Namespace}}-{{.IngressName}}"
+				o.MultiHostNameTemplate = "cluster-a-{{.Namespace}}-{{.IngressName}}-{{.Host}}{{.Path}}"
+				return o
+			}(),
+			valid: true,
+		},
+		{
+			name: "name template must start with gc name prefix",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.GCInterval = time.Minute
+				o.GCNamePrefix = "cluster-a-"
+				o.MultiHostNameTemplate = "cluster-a-{{.Namespace}}-{{.IngressName}}-{{.Host}}{{.Path}}"
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "multi host name template must start with gc name prefix",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.GCInterval = time.Minute
+				o.GCNamePrefix = "cluster-a-"
+				o.NameTemplate = "cluster-a-{{.Namespace}}-{{.IngressName}}"
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "gc with namespace requires unique name prefix",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.GCInterval = time.Minute
+				o.GCNamePrefix = "team-a-"
+				o.NameTemplate = "team-a-{{.Namespace}}-{{.IngressName}}"
+				o.MultiHostNameTemplate = "team-a-{{.Namespace}}-{{.IngressName}}-{{.Host}}{{.Path}}"
+				o.Namespace = "team-a"
+				return o
+			}(),
+			valid: false,
+		},
+		{
+			name: "gc with namespace and confirmed unique name prefix",
+			options: func() *Options {
+				o := NewDefaultOptions()
+				o.GCInterval = time.Minute
+				o.GCNamePrefix = "team-a-"
+				o.NameTemplate = "team-a-{{.Namespace}}-{{.IngressName}}"
+				o.MultiHostNameTemplate = "team-a-{{.Namespace}}-{{.IngressName}}-{{.Host}}{{.Path}}"
+				o.Namespace = "team-a"
+				o.GCUniqueNamePrefix = true
+				return o
+			}(),
+			valid: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -48,3 +216,64 @@ func TestOptions_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestOptions_WatchNamespaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		expected  []string
+	}{
+		{
+			name:      "empty namespace watches all namespaces",
+			namespace: "",
+			expected:  nil,
+		},
+		{
+			name:      "single namespace",
+			namespace: "kube-system",
+			expected:  []string{"kube-system"},
+		},
+		{
+			name:      "comma separated list of namespaces",
+			namespace: "kube-system, default,,team-a ",
+			expected:  []string{"kube-system", "default", "team-a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := &Options{Namespace: test.namespace}
+
+			assert.Equal(t, test.expected, options.WatchNamespaces())
+		})
+	}
+}
+
+func TestOptions_EnabledProviders(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  string
+		providers string
+		expected  []string
+	}{
+		{
+			name:     "defaults to default providers",
+			provider: "site24x7, uptimekuma",
+			expected: []string{"site24x7", "uptimekuma"},
+		},
+		{
+			name:      "comma separated list of providers",
+			provider:  "site24x7",
+			providers: "site24x7,blackbox,",
+			expected:  []string{"site24x7", "blackbox"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := &Options{ProviderName: test.provider, Providers: test.providers}
+
+			assert.Equal(t, test.expected, options.EnabledProviders())
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
+	"time"
 
 	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 )
 
@@ -12,16 +15,37 @@ const (
 	// ProviderSite24x7 uses Site24x7 for managing ingress monitors.
 	ProviderSite24x7 = "site24x7"
 
+	// ProviderUptimeKuma uses Uptime Kuma for managing ingress monitors.
+	ProviderUptimeKuma = "uptimekuma"
+
+	// ProviderWebhook sends all monitor operations as JSON HTTP requests to
+	// a configurable endpoint.
+	ProviderWebhook = "webhook"
+
+	// ProviderBlackbox creates Prometheus Operator Probe resources or file_sd
+	// targets for the Prometheus blackbox_exporter.
+	ProviderBlackbox = "blackbox"
+
 	// ProviderNull does nothing but log create/update/delete monitor events.
 	// This is intended for testing purposes only.
 	ProviderNull = "null"
 )
 
-// ProviderConfig contains the configuration for all supported monitor
-// providers.
-type ProviderConfig struct {
-	Site24x7 Site24x7Config `json:"site24x7"`
-}
+const (
+	// BlackboxModeProbe makes the blackbox provider create a Prometheus
+	// Operator Probe resource for each monitor.
+	BlackboxModeProbe = "probe"
+
+	// BlackboxModeFileSD makes the blackbox provider add an entry for each
+	// monitor to a ConfigMap which can be used with Prometheus' file_sd.
+	BlackboxModeFileSD = "file-sd"
+)
+
+// ProviderConfig contains the raw configuration sections of the monitor
+// providers keyed by provider name. The sections are opaque to the
+// controller and decoded by the factory of the respective provider, so that
+// providers can define their own configuration types.
+type ProviderConfig map[string]json.RawMessage
 
 // Site24x7Config is the configuration for the Site24x7 website monitor
 // provider.
@@ -44,6 +68,12 @@ type Site24x7Config struct {
 	// defaults can be overridden explicitly for each monitor via ingress
 	// annotations (see annotations.go for all available annotations).
 	MonitorDefaults Site24x7MonitorDefaults `json:"monitorDefaults"`
+
+	// MonitorCacheTTL configures how long the list of monitors fetched from
+	// the Site24x7 API is cached. Monitors that the controller creates,
+	// updates or deletes are patched into the cache, it is only invalidated
+	// if such a write fails. Caching is disabled if zero.
+	MonitorCacheTTL metav1.Duration `json:"monitorCacheTTL"`
 }
 
 // Site24x7MonitorDefaults define the monitor defaults that are used for each
@@ -140,32 +170,237 @@ type Site24x7MonitorDefaults struct {
 	UserGroupIDs []string `json:"userGroupIDs"`
 }
 
-// NewDefaultProviderConfig creates a new default provider config.
-func NewDefaultProviderConfig() ProviderConfig {
-	return ProviderConfig{
-		Site24x7: Site24x7Config{
-			ClientID:     os.Getenv("SITE24X7_CLIENT_ID"),
-			ClientSecret: os.Getenv("SITE24X7_CLIENT_SECRET"),
-			RefreshToken: os.Getenv("SITE24X7_REFRESH_TOKEN"),
-			MonitorDefaults: Site24x7MonitorDefaults{
-				AutoLocationProfile:     true,
-				AutoNotificationProfile: true,
-				AutoThresholdProfile:    true,
-				AutoMonitorGroup:        true,
-				AutoUserGroup:           true,
-				CheckFrequency:          "1",
-				HTTPMethod:              "G",
-				Timeout:                 10,
-				UseNameServer:           true,
-				CustomHeaders:           []site24x7api.Header{},
-				Actions:                 []site24x7api.ActionRef{},
-			},
+// UptimeKumaConfig is the configuration for the Uptime Kuma monitor provider.
+// Uptime Kuma itself only exposes a socket.io API, so the provider talks to
+// the REST API of uptime-kuma-web-api
+// (https://github.com/MedAziz11/Uptime-Kuma-Web-API) which has to be deployed
+// alongside Uptime Kuma.
+type UptimeKumaConfig struct {
+	// URL is the base URL of the Uptime Kuma REST API, e.g.
+	// http://uptime-kuma-api.monitoring.svc:8000.
+	URL string `json:"url"`
+
+	// Username is used to obtain an access token from the REST API. If not
+	// specified, the value will be read from the UPTIME_KUMA_USERNAME
+	// environment variable.
+	Username string `json:"username"`
+
+	// Password is used to obtain an access token from the REST API. If not
+	// specified, the value will be read from the UPTIME_KUMA_PASSWORD
+	// environment variable.
+	Password string `json:"password"`
+
+	// SourceRanges are the CIDR blocks that Uptime Kuma performs its checks
+	// from. Since Uptime Kuma is self-hosted, these cannot be discovered via
+	// the API and have to be configured explicitly.
+	SourceRanges []string `json:"sourceRanges"`
+
+	// MonitorDefaults contain defaults that apply to all monitors. The
+	// defaults can be overridden explicitly for each monitor via ingress
+	// annotations (see annotations.go for all available annotations).
+	MonitorDefaults UptimeKumaMonitorDefaults `json:"monitorDefaults"`
+}
+
+// UptimeKumaMonitorDefaults define the monitor defaults that are used for
+// each Uptime Kuma monitor if not overridden explicitly via ingress
+// annotations.
+type UptimeKumaMonitorDefaults struct {
+	// AcceptedStatusCodes configures the HTTP status codes that are
+	// considered successful, e.g. "200-299".
+	AcceptedStatusCodes []string `json:"acceptedStatusCodes"`
+
+	// IgnoreTLS configures whether TLS/SSL errors should be ignored.
+	IgnoreTLS bool `json:"ignoreTLS"`
+
+	// Interval configures the default check interval in seconds.
+	Interval int `json:"interval"`
+
+	// MaxRedirects configures the maximum number of redirects to follow.
+	MaxRedirects int `json:"maxRedirects"`
+
+	// MaxRetries configures the number of retries before the monitor is
+	// marked as down.
+	MaxRetries int `json:"maxRetries"`
+
+	// Method sets the default HTTP method to use for all checks.
+	Method string `json:"method"`
+
+	// NotificationIDs configures the IDs of the notifications that should be
+	// triggered if a monitor goes down.
+	NotificationIDs []int `json:"notificationIDs"`
+
+	// RetryInterval configures the interval in seconds between retries.
+	RetryInterval int `json:"retryInterval"`
+}
+
+// WebhookConfig is the configuration for the generic webhook provider. The
+// provider sends all monitor operations as JSON HTTP requests to the
+// configured URL. The request bodies contain the complete monitor including
+// the annotations of the source resource. The following requests are sent:
+//
+//	POST   {url}/monitors               creates a monitor
+//	GET    {url}/monitors               lists all monitors as JSON array
+//	GET    {url}/monitors/{name}        retrieves a monitor, 404 if absent
+//	PUT    {url}/monitors/{name}        updates a monitor if it differs
+//	DELETE {url}/monitors/{name}        deletes a monitor, 404 if absent
+//	POST   {url}/source-ranges          returns a JSON array of CIDR blocks
+type WebhookConfig struct {
+	// URL is the base URL of the webhook API.
+	URL string `json:"url"`
+
+	// BearerToken is sent in the Authorization header of each request if
+	// set. If not specified, the value will be read from the
+	// WEBHOOK_BEARER_TOKEN environment variable.
+	BearerToken string `json:"bearerToken"`
+
+	// Headers are additional HTTP headers that are sent with each request,
+	// e.g. for authentication.
+	Headers map[string]string `json:"headers"`
+
+	// Timeout configures the timeout for each request.
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// BlackboxConfig is the configuration for the Prometheus blackbox_exporter
+// provider. Instead of calling an external API, the provider writes
+// Kubernetes objects which are picked up by Prometheus.
+type BlackboxConfig struct {
+	// Mode configures which kind of objects are generated. Must be one of
+	// "probe" (default) or "file-sd". In "probe" mode, a Prometheus Operator
+	// Probe resource is created in Namespace. In "file-sd" mode, each monitor
+	// is added as an entry to the ConfigMap configured in FileSD.
+	Mode string `json:"mode"`
+
+	// ProberURL is the address of the blackbox_exporter, e.g.
+	// blackbox-exporter.monitoring.svc:9115. Only used in "probe" mode.
+	ProberURL string `json:"proberURL"`
+
+	// JobName is the job name assigned to the scraped metrics. Only used in
+	// "probe" mode. If empty, the Prometheus Operator default is used.
+	JobName string `json:"jobName"`
+
+	// Namespace is the namespace in which the Probe resources are created
+	// and looked up. Only used in "probe" mode.
+	Namespace string `json:"namespace"`
+
+	// FileSD configures the ConfigMap that holds the file_sd targets. Only
+	// used in "file-sd" mode.
+	FileSD BlackboxFileSDConfig `json:"fileSD"`
+
+	// SourceRanges are the CIDR blocks that the blackbox_exporter performs
+	// its checks from. As these cannot be discovered, they have to be
+	// configured explicitly.
+	SourceRanges []string `json:"sourceRanges"`
+
+	// MonitorDefaults contain defaults that apply to all monitors. The
+	// defaults can be overridden explicitly for each monitor via ingress
+	// annotations (see annotations.go for all available annotations).
+	MonitorDefaults BlackboxMonitorDefaults `json:"monitorDefaults"`
+}
+
+// BlackboxFileSDConfig configures the ConfigMap that is used to store the
+// file_sd targets. The ConfigMap has to be mounted into Prometheus and
+// referenced in a file_sd_configs section of a scrape config.
+type BlackboxFileSDConfig struct {
+	// Namespace is the namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ConfigMap.
+	Name string `json:"name"`
+}
+
+// BlackboxMonitorDefaults define the monitor defaults that are used for each
+// blackbox monitor if not overridden explicitly via ingress annotations.
+type BlackboxMonitorDefaults struct {
+	// Module is the blackbox_exporter module used for probing, e.g.
+	// "http_2xx".
+	Module string `json:"module"`
+
+	// GRPCModule is the blackbox_exporter module used for probing gRPC
+	// endpoints, e.g. of GRPCRoutes. The module must use the grpc prober.
+	GRPCModule string `json:"grpcModule"`
+
+	// TLSModule is the blackbox_exporter module used for probing TLS
+	// endpoints, e.g. of TLSRoutes. The module must use the tcp prober with
+	// TLS enabled.
+	TLSModule string `json:"tlsModule"`
+
+	// Interval configures the scrape interval, e.g. "30s". If empty, the
+	// Prometheus default is used.
+	Interval string `json:"interval"`
+
+	// Labels are additional labels that are attached to the probe targets.
+	Labels map[string]string `json:"labels"`
+}
+
+// NewDefaultSite24x7Config creates a new default Site24x7 provider config.
+// The credentials are read from the environment.
+func NewDefaultSite24x7Config() Site24x7Config {
+	return Site24x7Config{
+		ClientID:        os.Getenv("SITE24X7_CLIENT_ID"),
+		ClientSecret:    os.Getenv("SITE24X7_CLIENT_SECRET"),
+		RefreshToken:    os.Getenv("SITE24X7_REFRESH_TOKEN"),
+		MonitorCacheTTL: metav1.Duration{Duration: 5 * time.Minute},
+		MonitorDefaults: Site24x7MonitorDefaults{
+			AutoLocationProfile:     true,
+			AutoNotificationProfile: true,
+			AutoThresholdProfile:    true,
+			AutoMonitorGroup:        true,
+			AutoUserGroup:           true,
+			CheckFrequency:          "1",
+			HTTPMethod:              "G",
+			Timeout:                 10,
+			UseNameServer:           true,
+			CustomHeaders:           []site24x7api.Header{},
+			Actions:                 []site24x7api.ActionRef{},
+		},
+	}
+}
+
+// NewDefaultUptimeKumaConfig creates a new default Uptime Kuma provider
+// config. The credentials are read from the environment.
+func NewDefaultUptimeKumaConfig() UptimeKumaConfig {
+	return UptimeKumaConfig{
+		Username: os.Getenv("UPTIME_KUMA_USERNAME"),
+		Password: os.Getenv("UPTIME_KUMA_PASSWORD"),
+		MonitorDefaults: UptimeKumaMonitorDefaults{
+			AcceptedStatusCodes: []string{"200-299"},
+			Interval:            60,
+			MaxRedirects:        10,
+			Method:              "GET",
+			RetryInterval:       60,
+		},
+	}
+}
+
+// NewDefaultWebhookConfig creates a new default webhook provider config. The
+// bearer token is read from the environment.
+func NewDefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		BearerToken: os.Getenv("WEBHOOK_BEARER_TOKEN"),
+		Timeout:     metav1.Duration{Duration: 30 * time.Second},
+	}
+}
+
+// NewDefaultBlackboxConfig creates a new default blackbox provider config.
+func NewDefaultBlackboxConfig() BlackboxConfig {
+	return BlackboxConfig{
+		Mode: BlackboxModeProbe,
+		FileSD: BlackboxFileSDConfig{
+			Name: "blackbox-exporter-targets",
+		},
+		MonitorDefaults: BlackboxMonitorDefaults{
+			Module:     "http_2xx",
+			GRPCModule: "grpc",
+			TLSModule:  "tls_connect",
 		},
 	}
 }
 
-// ReadProviderConfig reads the provider configuration from given file.
-func ReadProviderConfig(filename string) (*ProviderConfig, error) {
+// ReadProviderConfig reads the provider configuration from given file. The
+// file may be YAML or JSON. Each top level key is the name of a provider and
+// its value is kept as raw JSON for the provider to decode.
+func ReadProviderConfig(filename string) (ProviderConfig, error) {
 	buf, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, err
@@ -178,5 +413,5 @@ func ReadProviderConfig(filename string) (*ProviderConfig, error) {
 		return nil, err
 	}
 
-	return &config, nil
+	return config, nil
 }
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadProviderConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "providers.yaml")
+
+	err := os.WriteFile(filename, []byte(`
+site24x7:
+  clientID: the-client-id
+  monitorDefaults:
+    checkFrequency: "5"
+custom:
+  endpoint: http://example.com
+`), 0o600)
+	require.NoError(t, err)
+
+	c, err := ReadProviderConfig(filename)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"clientID":"the-client-id","monitorDefaults":{"checkFrequency":"5"}}`, string(c[ProviderSite24x7]))
+	assert.JSONEq(t, `{"endpoint":"http://example.com"}`, string(c["custom"]))
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AnnotationError describes an annotation with an invalid value.
+type AnnotationError struct {
+	// Name is the name of the annotation.
+	Name string
+
+	// Value is the invalid value of the annotation.
+	Value string
+
+	// Reason describes why the value is invalid.
+	Reason string
+}
+
+// Error implements error.
+func (e *AnnotationError) Error() string {
+	return fmt.Sprintf("annotation %q: %s", e.Name, e.Reason)
+}
+
+// AnnotationErrors is a list of annotation violations. It is returned as a
+// whole, so that all problems of a resource can be fixed at once.
+type AnnotationErrors []*AnnotationError
+
+// Error implements error.
+func (e AnnotationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// knownAnnotations are all annotations with the AnnotationPrefix.
+var knownAnnotations = []string{
+	AnnotationEnabled,
+	AnnotationForceHTTPS,
+	AnnotationForceHTTP,
+	AnnotationPathOverride,
+	AnnotationMultiHost,
+	AnnotationMultiPath,
+	AnnotationPort,
+	AnnotationScheme,
+	AnnotationProviders,
+	AnnotationMonitoredHosts,
+	AnnotationMonitorID,
+	AnnotationMonitorName,
+	AnnotationLastSync,
+	AnnotationLastError,
+}
+
+// ValidateAnnotations validates the global annotations, which apply to all
+// providers. Provider specific annotations are validated by the providers.
+// Returns AnnotationErrors if any annotation is invalid.
+func ValidateAnnotations(annotations Annotations) error {
+	v := NewAnnotationValidator(annotations)
+
+	v.Bool(AnnotationEnabled)
+	v.Bool(AnnotationForceHTTPS)
+	v.Bool(AnnotationForceHTTP)
+	v.Bool(AnnotationMultiHost)
+	v.Bool(AnnotationMultiPath)
+	v.Int(AnnotationPort, 1, 65535)
+	v.OneOf(AnnotationScheme, "http", "https")
+	v.UnknownKeys(AnnotationPrefix, knownAnnotations...)
+
+	return v.Err()
+}
+
+// JoinAnnotationErrors joins the AnnotationErrors contained in errs into a
+// single AnnotationErrors. Nil errors are skipped and the first error that
+// does not contain AnnotationErrors is returned as is. Returns nil if all
+// errs are nil.
+func JoinAnnotationErrors(errs ...error) error {
+	var joined AnnotationErrors
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		var annotationErrs AnnotationErrors
+		if !errors.As(err, &annotationErrs) {
+			return err
+		}
+
+		joined = append(joined, annotationErrs...)
+	}
+
+	if len(joined) == 0 {
+		return nil
+	}
+
+	return joined
+}
+
+// AnnotationValidator validates annotation values and collects all
+// violations. Annotations that are not present are always valid, as the
+// defaults of the provider config apply.
+type AnnotationValidator struct {
+	annotations Annotations
+	errs        AnnotationErrors
+}
+
+// NewAnnotationValidator creates a new *AnnotationValidator for
+// annotations.
+func NewAnnotationValidator(annotations Annotations) *AnnotationValidator {
+	return &AnnotationValidator{annotations: annotations}
+}
+
+// Errorf records a violation for the annotation name.
+func (v *AnnotationValidator) Errorf(name, format string, args ...interface{}) {
+	v.errs = append(v.errs, &AnnotationError{
+		Name:   name,
+		Value:  v.annotations[name],
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+// Bool validates that the annotation name is a bool.
+func (v *AnnotationValidator) Bool(name string) {
+	if val, ok := v.annotations[name]; ok {
+		if _, err := strconv.ParseBool(val); err != nil {
+			v.Errorf(name, "invalid bool %q", val)
+		}
+	}
+}
+
+// Int validates that the annotation name is an integer in the range
+// minValue-maxValue. Use math.MaxInt as maxValue for ranges without an
+// upper bound.
+func (v *AnnotationValidator) Int(name string, minValue, maxValue int) {
+	val, ok := v.annotations[name]
+	if !ok {
+		return
+	}
+
+	i, err := strconv.Atoi(val)
+	switch {
+	case err != nil:
+		v.Errorf(name, "invalid integer %q", val)
+	case maxValue == math.MaxInt && i < minValue:
+		v.Errorf(name, "%d is less than %d", i, minValue)
+	case i < minValue || i > maxValue:
+		v.Errorf(name, "%d is not in range %d-%d", i, minValue, maxValue)
+	}
+}
+
+// OneOf validates that the annotation name has one of the allowed values.
+func (v *AnnotationValidator) OneOf(name string, allowed ...string) {
+	if val, ok := v.annotations[name]; ok && !slices.Contains(allowed, val) {
+		v.Errorf(name, "%q is not one of %s", val, strings.Join(allowed, ", "))
+	}
+}
+
+// JSON validates that the annotation name contains JSON which can be
+// decoded into p without unknown fields. P must be a pointer. Returns true
+// if the annotation is present and was decoded, so that the caller can
+// validate the decoded value further.
+func (v *AnnotationValidator) JSON(name string, p interface{}) bool {
+	val, ok := v.annotations[name]
+	if !ok {
+		return false
+	}
+
+	dec := json.NewDecoder(bytes.NewBufferString(val))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(p); err != nil {
+		v.Errorf(name, "invalid json: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// UnknownKeys records a violation for each annotation with the prefix
+// whose name is not in known, e.g. because of a typo.
+func (v *AnnotationValidator) UnknownKeys(prefix string, known ...string) {
+	var unknown []string
+
+	for name := range v.annotations {
+		if strings.HasPrefix(name, prefix) && !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		v.Errorf(name, "unknown annotation")
+	}
+}
+
+// Err returns the collected violations as AnnotationErrors. Returns nil if
+// all annotations are valid.
+func (v *AnnotationValidator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationValidator(t *testing.T) {
+	type header struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name        string
+		annotations Annotations
+		expected    string
+	}{
+		{
+			name:        "missing annotations are valid",
+			annotations: Annotations{},
+		},
+		{
+			name: "valid annotations",
+			annotations: Annotations{
+				"foo.example.com/bool":    "true",
+				"foo.example.com/int":     "10",
+				"foo.example.com/min":     "100",
+				"foo.example.com/method":  "GET",
+				"foo.example.com/headers": `[{"name":"X-Foo"}]`,
+				"bar.example.com/other":   "ignored",
+			},
+		},
+		{
+			name:        "invalid bool",
+			annotations: Annotations{"foo.example.com/bool": "yes please"},
+			expected:    `annotation "foo.example.com/bool": invalid bool "yes please"`,
+		},
+		{
+			name:        "invalid int",
+			annotations: Annotations{"foo.example.com/int": "abc"},
+			expected:    `annotation "foo.example.com/int": invalid integer "abc"`,
+		},
+		{
+			name:        "int out of range",
+			annotations: Annotations{"foo.example.com/int": "46"},
+			expected:    `annotation "foo.example.com/int": 46 is not in range 1-45`,
+		},
+		{
+			name:        "int below minimum",
+			annotations: Annotations{"foo.example.com/min": "-1"},
+			expected:    `annotation "foo.example.com/min": -1 is less than 0`,
+		},
+		{
+			name:        "value not allowed",
+			annotations: Annotations{"foo.example.com/method": "FETCH"},
+			expected:    `annotation "foo.example.com/method": "FETCH" is not one of GET, HEAD`,
+		},
+		{
+			name:        "unknown json field",
+			annotations: Annotations{"foo.example.com/headers": `[{"key":"X-Foo"}]`},
+			expected:    `annotation "foo.example.com/headers": invalid json: json: unknown field "key"`,
+		},
+		{
+			name: "all violations are returned",
+			annotations: Annotations{
+				"foo.example.com/timout": "10",
+				"foo.example.com/int":    "0",
+				"foo.example.com/bool":   "1.0",
+				"foo.example.com/zzz":    "",
+			},
+			expected: `annotation "foo.example.com/timout": unknown annotation; ` +
+				`annotation "foo.example.com/zzz": unknown annotation; ` +
+				`annotation "foo.example.com/bool": invalid bool "1.0"; ` +
+				`annotation "foo.example.com/int": 0 is not in range 1-45`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewAnnotationValidator(test.annotations)
+
+			v.UnknownKeys("foo.example.com/", "foo.example.com/bool", "foo.example.com/int", "foo.example.com/min", "foo.example.com/method", "foo.example.com/headers")
+			v.Bool("foo.example.com/bool")
+			v.Int("foo.example.com/int", 1, 45)
+			v.Int("foo.example.com/min", 0, math.MaxInt)
+			v.OneOf("foo.example.com/method", "GET", "HEAD")
+
+			var headers []header
+			v.JSON("foo.example.com/headers", &headers)
+
+			err := v.Err()
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, test.expected, err.Error())
+
+			var annotationErrs AnnotationErrors
+			require.ErrorAs(t, err, &annotationErrs)
+			assert.NotEmpty(t, annotationErrs)
+		})
+	}
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations Annotations
+		expected    string
+	}{
+		{
+			name: "valid annotations",
+			annotations: Annotations{
+				AnnotationEnabled:                "true",
+				AnnotationMultiHost:              "false",
+				AnnotationPort:                   "8080",
+				AnnotationScheme:                 "https",
+				AnnotationLastSync:               "2026-01-01T00:00:00Z",
+				"example.com/other":              "ignored",
+				AnnotationPrefixBlackbox + "foo": "ignored",
+			},
+		},
+		{
+			name: "all violations are returned",
+			annotations: Annotations{
+				AnnotationForceHTTPS:                "yes",
+				AnnotationPort:                      "http",
+				AnnotationScheme:                    "ftp",
+				"ingress-monitor.bonial.com/enable": "true",
+			},
+			expected: `annotation "ingress-monitor.bonial.com/force-https": invalid bool "yes"; ` +
+				`annotation "ingress-monitor.bonial.com/port": invalid integer "http"; ` +
+				`annotation "ingress-monitor.bonial.com/scheme": "ftp" is not one of http, https; ` +
+				`annotation "ingress-monitor.bonial.com/enable": unknown annotation`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAnnotations(test.annotations)
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, test.expected, err.Error())
+		})
+	}
+}
+
+func TestJoinAnnotationErrors(t *testing.T) {
+	assert.NoError(t, JoinAnnotationErrors(nil, nil))
+
+	first := AnnotationErrors{{Name: "foo", Reason: "invalid"}}
+	second := AnnotationErrors{{Name: "bar", Reason: "unknown annotation"}}
+
+	err := JoinAnnotationErrors(first, nil, second)
+	assert.Equal(t, AnnotationErrors{first[0], second[0]}, err)
+
+	other := errors.New("other")
+	assert.Equal(t, other, JoinAnnotationErrors(first, other))
+}
@@ -0,0 +1,142 @@
+// Package contour builds monitor sources from Contour HTTPProxies.
+// HTTPProxies are handled as unstructured objects to avoid a dependency on
+// the Contour API types.
+package contour
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Kind is the kind of Contour HTTPProxies.
+const Kind = "HTTPProxy"
+
+// GVK is the GroupVersionKind of Contour HTTPProxies.
+var GVK = schema.GroupVersionKind{
+	Group:   "projectcontour.io",
+	Version: "v1",
+	Kind:    Kind,
+}
+
+// New creates an empty unstructured Contour HTTPProxy.
+func New() *unstructured.Unstructured {
+	proxy := &unstructured.Unstructured{}
+	proxy.SetGroupVersionKind(GVK)
+	return proxy
+}
+
+// NewList creates an empty unstructured list of Contour HTTPProxies.
+func NewList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GVK.GroupVersion().WithKind(Kind + "List"))
+	return list
+}
+
+// Validate checks if a Contour HTTPProxy fulfills all criteria for
+// monitoring and returns an error on any violation. Only root HTTPProxies
+// with a spec.virtualhost.fqdn can be monitored and the fqdn must not contain
+// wildcards.
+func Validate(proxy *unstructured.Unstructured) error {
+	fqdn, _, _ := unstructured.NestedString(proxy.Object, "spec", "virtualhost", "fqdn")
+	if fqdn == "" {
+		return errors.New("httpproxy does not have a virtualhost fqdn")
+	}
+
+	if strings.Contains(fqdn, "*") {
+		return errors.Errorf("httpproxy fqdn %q contains wildcards", fqdn)
+	}
+
+	return nil
+}
+
+// BuildMonitorURL builds the URL that should be monitored for the Contour
+// HTTPProxy. HTTPS is used if the virtualhost has a tls section, HTTP
+// otherwise. The force-https and force-http annotations take precedence. The
+// path defaults to the first prefix condition of the first route.
+func BuildMonitorURL(proxy *unstructured.Unstructured) (string, error) {
+	fqdn, _, _ := unstructured.NestedString(proxy.Object, "spec", "virtualhost", "fqdn")
+
+	u, err := url.Parse(fmt.Sprintf("%s://%s", scheme(proxy), fqdn))
+	if err != nil {
+		return "", err
+	}
+
+	if path, found := proxy.GetAnnotations()[config.AnnotationPathOverride]; found {
+		u.Path = path
+	} else if path := matchedPrefix(proxy); path != "/" {
+		u.Path = path
+	}
+
+	return u.String(), nil
+}
+
+// matchedPrefix returns the first prefix condition of the first route of
+// the HTTPProxy. Returns an empty string if there is none.
+func matchedPrefix(proxy *unstructured.Unstructured) string {
+	routes, _, _ := unstructured.NestedSlice(proxy.Object, "spec", "routes")
+	if len(routes) == 0 {
+		return ""
+	}
+
+	route, ok := routes[0].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(route, "conditions")
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if prefix, ok := condition["prefix"].(string); ok {
+			return prefix
+		}
+	}
+
+	return ""
+}
+
+func scheme(proxy *unstructured.Unstructured) string {
+	annotations := config.Annotations(proxy.GetAnnotations())
+
+	if annotations.BoolValue(config.AnnotationForceHTTPS) {
+		return "https"
+	}
+
+	if annotations.BoolValue(config.AnnotationForceHTTP) {
+		return "http"
+	}
+
+	if _, found, _ := unstructured.NestedMap(proxy.Object, "spec", "virtualhost", "tls"); found {
+		return "https"
+	}
+
+	return "http"
+}
+
+// NewMonitorSource creates a MonitorSource from a Contour HTTPProxy. The
+// proxy must have been validated before calling this function.
+func NewMonitorSource(proxy *unstructured.Unstructured) (models.MonitorSource, error) {
+	monitorURL, err := BuildMonitorURL(proxy)
+	if err != nil {
+		return models.MonitorSource{}, err
+	}
+
+	return models.MonitorSource{
+		Kind:        Kind,
+		Name:        proxy.GetName(),
+		Namespace:   proxy.GetNamespace(),
+		Annotations: proxy.GetAnnotations(),
+		URL:         monitorURL,
+	}, nil
+}
@@ -0,0 +1,147 @@
+package contour
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newHTTPProxy(annotations map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+	proxy := New()
+	proxy.SetName("foo")
+	proxy.SetNamespace("default")
+	proxy.SetAnnotations(annotations)
+	proxy.Object["spec"] = spec
+	return proxy
+}
+
+func virtualhost(fqdn string) map[string]interface{} {
+	return map[string]interface{}{"fqdn": fqdn}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		proxy    *unstructured.Unstructured
+		expected error
+	}{
+		{
+			name:  "valid httpproxy",
+			proxy: newHTTPProxy(nil, map[string]interface{}{"virtualhost": virtualhost("foo.example.com")}),
+		},
+		{
+			name:     "non-root httpproxy without virtualhost",
+			proxy:    newHTTPProxy(nil, map[string]interface{}{"routes": []interface{}{}}),
+			expected: errors.New("httpproxy does not have a virtualhost fqdn"),
+		},
+		{
+			name:     "wildcard fqdns are not supported",
+			proxy:    newHTTPProxy(nil, map[string]interface{}{"virtualhost": virtualhost("*.example.com")}),
+			expected: errors.New(`httpproxy fqdn "*.example.com" contains wildcards`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.proxy)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildMonitorURL(t *testing.T) {
+	tlsVirtualhost := map[string]interface{}{
+		"fqdn": "foo.example.com",
+		"tls":  map[string]interface{}{"secretName": "foo-tls"},
+	}
+
+	routes := []interface{}{
+		map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"header": map[string]interface{}{"name": "x-foo"}},
+				map[string]interface{}{"prefix": "/api"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		proxy    *unstructured.Unstructured
+		expected string
+	}{
+		{
+			name:     "httpproxy without TLS",
+			proxy:    newHTTPProxy(nil, map[string]interface{}{"virtualhost": virtualhost("foo.example.com")}),
+			expected: "http://foo.example.com",
+		},
+		{
+			name:     "httpproxy with TLS",
+			proxy:    newHTTPProxy(nil, map[string]interface{}{"virtualhost": tlsVirtualhost}),
+			expected: "https://foo.example.com",
+		},
+		{
+			name:     "prefix condition of the first route",
+			proxy:    newHTTPProxy(nil, map[string]interface{}{"virtualhost": tlsVirtualhost, "routes": routes}),
+			expected: "https://foo.example.com/api",
+		},
+		{
+			name: "path override",
+			proxy: newHTTPProxy(
+				map[string]string{config.AnnotationPathOverride: "/health"},
+				map[string]interface{}{"virtualhost": tlsVirtualhost, "routes": routes},
+			),
+			expected: "https://foo.example.com/health",
+		},
+		{
+			name: "force https",
+			proxy: newHTTPProxy(
+				map[string]string{config.AnnotationForceHTTPS: "true"},
+				map[string]interface{}{"virtualhost": virtualhost("foo.example.com")},
+			),
+			expected: "https://foo.example.com",
+		},
+		{
+			name: "force http",
+			proxy: newHTTPProxy(
+				map[string]string{config.AnnotationForceHTTP: "true"},
+				map[string]interface{}{"virtualhost": tlsVirtualhost},
+			),
+			expected: "http://foo.example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url, err := BuildMonitorURL(test.proxy)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, url)
+		})
+	}
+}
+
+func TestNewMonitorSource(t *testing.T) {
+	annotations := map[string]string{config.AnnotationEnabled: "true"}
+
+	source, err := NewMonitorSource(newHTTPProxy(annotations, map[string]interface{}{"virtualhost": virtualhost("foo.example.com")}))
+	require.NoError(t, err)
+
+	expected := models.MonitorSource{
+		Kind:        "HTTPProxy",
+		Name:        "foo",
+		Namespace:   "default",
+		Annotations: annotations,
+		URL:         "http://foo.example.com",
+	}
+
+	assert.Equal(t, expected, source)
+}
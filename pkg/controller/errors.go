@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// resultForError translates the error of a reconciliation into the result
+// that is returned to controller-runtime. Permanent errors are not retried,
+// as requeueing cannot resolve them. They were already surfaced via events
+// and the last-error annotation and are resolved by the next change of the
+// resource. Rate limited errors are requeued after the duration requested
+// by the provider. All other errors are returned to be retried with the
+// controller's exponential backoff.
+func resultForError(req reconcile.Request, err error) (reconcile.Result, error) {
+	if err == nil {
+		return reconcile.Result{}, nil
+	}
+
+	if models.IsPermanent(err) {
+		log.Info("not retrying reconciliation due to permanent error", "namespace", req.Namespace, "name", req.Name, "error", err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	if retryAfter, ok := models.RetryAfter(err); ok && retryAfter > 0 {
+		log.V(1).Info("provider is rate limiting requests, requeueing", "namespace", req.Namespace, "name", req.Name, "after", retryAfter)
+		return reconcile.Result{RequeueAfter: retryAfter}, nil
+	}
+
+	return reconcile.Result{}, err
+}
+
+// failureMessage returns the message of the event about a failed operation,
+// e.g. "ensure monitor". Permanent errors are flagged as such, so that users
+// know that the resource needs to be fixed.
+func failureMessage(operation string, err error) string {
+	if models.IsPermanent(err) {
+		return fmt.Sprintf("Failed to %s, not retrying: %v", operation, err)
+	}
+
+	return fmt.Sprintf("Failed to %s: %v", operation, err)
+}
+
+// failureReason returns the reason of the event about a failed operation.
+// Annotations that were rejected by the validation of a provider are
+// reported as validation failures, as the monitor was never sent to the
+// provider.
+func failureReason(err error) string {
+	var annotationErrs config.AnnotationErrors
+	if errors.As(err, &annotationErrs) {
+		return ReasonValidationFailed
+	}
+
+	return ReasonProviderError
+}
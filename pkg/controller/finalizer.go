@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// CleanupFinalizer is added to monitored resources if finalizers are
+// enabled. It blocks the deletion of a resource until all of its monitors
+// were deleted.
+const CleanupFinalizer = "ingress-monitor.bonial.com/cleanup"
+
+// finalize deletes all monitors of obj, which is being deleted, and removes
+// the cleanup finalizer afterwards. If obj does not carry the finalizer,
+// finalize is a no-op and the monitors are deleted once obj is gone.
+func (h *monitorHandler) finalize(ctx context.Context, obj client.Object) error {
+	if !controllerutil.ContainsFinalizer(obj, CleanupFinalizer) {
+		return nil
+	}
+
+	return h.deleteMonitors(ctx, obj)
+}
+
+// deleteMonitors deletes the monitor of obj and the monitors of all hosts
+// recorded on obj. Afterwards, the state annotations and the cleanup
+// finalizer are removed from obj.
+func (h *monitorHandler) deleteMonitors(ctx context.Context, obj client.Object) error {
+	source := models.MonitorSource{
+		Kind:        h.kind,
+		Name:        obj.GetName(),
+		Namespace:   obj.GetNamespace(),
+		Annotations: obj.GetAnnotations(),
+	}
+
+	err := h.deleteMonitor(obj, source)
+	if err != nil {
+		return err
+	}
+
+	err = h.reconcileMonitoredHosts(ctx, obj, nil)
+	if err != nil {
+		return err
+	}
+
+	err = h.clearMonitorState(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	return h.removeFinalizer(ctx, obj)
+}
+
+// addFinalizer adds the cleanup finalizer to obj if finalizers are enabled.
+// In dry run mode, the finalizer is never added.
+func (h *monitorHandler) addFinalizer(ctx context.Context, obj client.Object) error {
+	if !h.finalizer || h.dryRun || controllerutil.ContainsFinalizer(obj, CleanupFinalizer) {
+		return nil
+	}
+
+	original := obj.DeepCopyObject().(client.Object)
+
+	controllerutil.AddFinalizer(obj, CleanupFinalizer)
+
+	return h.client.Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+// removeFinalizer removes the cleanup finalizer from obj. The finalizer is
+// removed even if finalizers are disabled or in dry run mode, so that
+// resources which were finalized before are not blocked from deletion.
+func (h *monitorHandler) removeFinalizer(ctx context.Context, obj client.Object) error {
+	if !controllerutil.ContainsFinalizer(obj, CleanupFinalizer) {
+		return nil
+	}
+
+	original := obj.DeepCopyObject().(client.Object)
+
+	controllerutil.RemoveFinalizer(obj, CleanupFinalizer)
+
+	return h.client.Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/contour"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/httproute"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/ingress"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitorresource"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/policy"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/route"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/traefik"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// GarbageCollector periodically deletes monitors owned by the controller
+// whose source resource does not exist or is not enabled anymore. These
+// orphaned monitors are left behind if the controller misses a deletion, for
+// example because it was not running at the time. It implements
+// manager.Runnable.
+type GarbageCollector struct {
+	client                client.Reader
+	monitorService        monitor.Service
+	policies              *policy.Resolver
+	interval              time.Duration
+	multiHost             bool
+	enableHTTPRoute       bool
+	enableGRPCRoute       bool
+	enableTLSRoute        bool
+	enableService         bool
+	enableOpenShiftRoute  bool
+	enableTraefik         bool
+	enableContour         bool
+	enableMonitorResource bool
+}
+
+// NewGarbageCollector creates a new *GarbageCollector which lists resources
+// using client.
+func NewGarbageCollector(client client.Reader, monitorService monitor.Service, options *config.Options) *GarbageCollector {
+	return &GarbageCollector{
+		client:                client,
+		monitorService:        monitorService,
+		policies:              newPolicyResolver(client, options),
+		interval:              options.GCInterval,
+		multiHost:             options.MultiHost,
+		enableHTTPRoute:       options.EnableHTTPRoute,
+		enableGRPCRoute:       options.EnableGRPCRoute,
+		enableTLSRoute:        options.EnableTLSRoute,
+		enableService:         options.EnableService,
+		enableOpenShiftRoute:  options.EnableOpenShiftRoute,
+		enableTraefik:         options.EnableTraefik,
+		enableContour:         options.EnableContour,
+		enableMonitorResource: options.EnableMonitorResource,
+	}
+}
+
+// Start runs the garbage collection once per interval until ctx is
+// cancelled. The first run happens after one interval has passed to give the
+// reconcilers a chance to process all resources first. It implements
+// manager.Runnable.
+func (gc *GarbageCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(gc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := gc.Collect(ctx)
+			if err != nil {
+				log.Error(err, "garbage collection failed")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Only the
+// leader may delete monitors.
+func (gc *GarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Collect deletes all orphaned monitors once.
+func (gc *GarbageCollector) Collect(ctx context.Context) error {
+	// The resources are only listed after the service listed the monitors,
+	// so that monitors of resources created in the meantime are kept.
+	orphans, err := gc.monitorService.DeleteOrphanedMonitors(func() ([]models.MonitorSource, error) {
+		return gc.expectedSources(ctx)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete orphaned monitors")
+	}
+
+	log.Info("garbage collection finished", "orphans", len(orphans))
+
+	return nil
+}
+
+// expectedSources returns the sources of all monitors that are expected to
+// exist for the enabled resources in the cluster.
+func (gc *GarbageCollector) expectedSources(ctx context.Context) ([]models.MonitorSource, error) {
+	ingresses := &networkingv1.IngressList{}
+
+	err := gc.client.List(ctx, ingresses)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list ingresses")
+	}
+
+	var sources []models.MonitorSource
+
+	for i := range ingresses.Items {
+		ing := &ingresses.Items[i]
+
+		if ing.Annotations[config.AnnotationEnabled] != "true" {
+			continue
+		}
+
+		ing, err := policy.Apply(ctx, gc.policies, ing)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, knownSources("Ingress", ing)...)
+
+		if ingress.MultiHostEnabled(ing, gc.multiHost) {
+			hostSources, err := ingress.NewMonitorSources(ing)
+			if err == nil {
+				sources = append(sources, hostSources...)
+			}
+		}
+	}
+
+	if gc.enableHTTPRoute {
+		routeSources, err := gc.httpRouteSources(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, routeSources...)
+	}
+
+	if gc.enableGRPCRoute {
+		routes := &gatewayv1.GRPCRouteList{}
+
+		err = gc.client.List(ctx, routes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list grpcroutes")
+		}
+
+		for i := range routes.Items {
+			if routes.Items[i].GetAnnotations()[config.AnnotationEnabled] != "true" {
+				continue
+			}
+
+			objSources, err := gc.policySources(ctx, httproute.GRPCRouteKind, &routes.Items[i])
+			if err != nil {
+				return nil, err
+			}
+
+			sources = append(sources, objSources...)
+		}
+	}
+
+	if gc.enableTLSRoute {
+		routes := &gatewayv1alpha2.TLSRouteList{}
+
+		err = gc.client.List(ctx, routes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list tlsroutes")
+		}
+
+		for i := range routes.Items {
+			if routes.Items[i].GetAnnotations()[config.AnnotationEnabled] != "true" {
+				continue
+			}
+
+			objSources, err := gc.policySources(ctx, httproute.TLSRouteKind, &routes.Items[i])
+			if err != nil {
+				return nil, err
+			}
+
+			sources = append(sources, objSources...)
+		}
+	}
+
+	if gc.enableService {
+		services := &corev1.ServiceList{}
+
+		err = gc.client.List(ctx, services)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list services")
+		}
+
+		for i := range services.Items {
+			if services.Items[i].Annotations[config.AnnotationEnabled] == "true" {
+				sources = append(sources, knownSources("Service", &services.Items[i])...)
+			}
+		}
+	}
+
+	if gc.enableOpenShiftRoute {
+		routes := route.NewList()
+
+		err = gc.client.List(ctx, routes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list openshift routes")
+		}
+
+		for i := range routes.Items {
+			if routes.Items[i].GetAnnotations()[config.AnnotationEnabled] != "true" {
+				continue
+			}
+
+			objSources, err := gc.policySources(ctx, route.Kind, &routes.Items[i])
+			if err != nil {
+				return nil, err
+			}
+
+			sources = append(sources, objSources...)
+		}
+	}
+
+	if gc.enableTraefik {
+		routes := traefik.NewList()
+
+		err = gc.client.List(ctx, routes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list traefik ingressroutes")
+		}
+
+		for i := range routes.Items {
+			if routes.Items[i].GetAnnotations()[config.AnnotationEnabled] != "true" {
+				continue
+			}
+
+			objSources, err := gc.policySources(ctx, traefik.Kind, &routes.Items[i])
+			if err != nil {
+				return nil, err
+			}
+
+			sources = append(sources, objSources...)
+		}
+	}
+
+	if gc.enableContour {
+		proxies := contour.NewList()
+
+		err = gc.client.List(ctx, proxies)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list contour httpproxies")
+		}
+
+		for i := range proxies.Items {
+			if proxies.Items[i].GetAnnotations()[config.AnnotationEnabled] != "true" {
+				continue
+			}
+
+			objSources, err := gc.policySources(ctx, contour.Kind, &proxies.Items[i])
+			if err != nil {
+				return nil, err
+			}
+
+			sources = append(sources, objSources...)
+		}
+	}
+
+	if gc.enableMonitorResource {
+		monitors := &v1alpha1.MonitorList{}
+
+		err = gc.client.List(ctx, monitors)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list monitors")
+		}
+
+		for i := range monitors.Items {
+			sources = append(sources, monitorresource.DeleteSource(&monitors.Items[i]))
+		}
+	}
+
+	return sources, nil
+}
+
+// httpRouteSources returns the sources of all monitors that are expected to
+// exist for the enabled HTTPRoutes in the cluster.
+func (gc *GarbageCollector) httpRouteSources(ctx context.Context) ([]models.MonitorSource, error) {
+	routes := &gatewayv1.HTTPRouteList{}
+
+	err := gc.client.List(ctx, routes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list httproutes")
+	}
+
+	var sources []models.MonitorSource
+
+	for i := range routes.Items {
+		route := &routes.Items[i]
+
+		if route.Annotations[config.AnnotationEnabled] != "true" {
+			continue
+		}
+
+		route, err := policy.Apply(ctx, gc.policies, route)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, knownSources("HTTPRoute", route)...)
+
+		if httproute.MultiHostEnabled(route, gc.multiHost) {
+			hostSources, err := httproute.NewMonitorSources(route, nil)
+			if err == nil {
+				sources = append(sources, hostSources...)
+			}
+		}
+	}
+
+	return sources, nil
+}
+
+// policySources applies the monitor policies to obj and returns the known
+// sources of the result. Policies may select the providers of a monitor, so
+// the sources must carry the defaults to protect monitors in all of them.
+func (gc *GarbageCollector) policySources(ctx context.Context, kind string, obj client.Object) ([]models.MonitorSource, error) {
+	obj, err := policy.Apply(ctx, gc.policies, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return knownSources(kind, obj), nil
+}
+
+// knownSources returns the source of the single monitor of obj and the
+// sources of all hosts recorded on obj. Monitor names only depend on the
+// fields set here, so these are sufficient to protect the monitors of obj
+// from garbage collection.
+func knownSources(kind string, obj client.Object) []models.MonitorSource {
+	source := models.MonitorSource{
+		Kind:        kind,
+		Name:        obj.GetName(),
+		Namespace:   obj.GetNamespace(),
+		Annotations: obj.GetAnnotations(),
+	}
+
+	return append([]models.MonitorSource{source}, recordedSources(kind, obj)...)
+}
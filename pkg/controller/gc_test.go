@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/contour"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/route"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/traefik"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGarbageCollector_Collect(t *testing.T) {
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	tests := []struct {
+		name        string
+		options     config.Options
+		objects     []client.Object
+		setup       func(*fake.Service)
+		expected    []string
+		validate    func(*testing.T, []models.MonitorSource)
+		expectError bool
+	}{
+		{
+			name: "only enabled ingresses are expected",
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return([]string{"default-baz"}, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com"},
+		},
+		{
+			name:    "includes httproutes if enabled",
+			options: config.Options{EnableHTTPRoute: true},
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com", "HTTPRoute/default/qux"},
+		},
+		{
+			name:    "includes enabled services if enabled",
+			options: config.Options{EnableService: true},
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com", "Service/default/lb"},
+		},
+		{
+			name:    "includes enabled openshift routes if enabled",
+			options: config.Options{EnableOpenShiftRoute: true},
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com", "Route/default/corge"},
+		},
+		{
+			name:    "includes enabled traefik ingressroutes and contour httpproxies if enabled",
+			options: config.Options{EnableTraefik: true, EnableContour: true},
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com", "IngressRoute/default/grault", "HTTPProxy/default/garply"},
+		},
+		{
+			name:    "includes monitor resources if enabled",
+			options: config.Options{EnableMonitorResource: true},
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com", "Monitor/default/quux"},
+		},
+		{
+			name:    "includes the sources of all hosts in multi host mode",
+			options: config.Options{MultiHost: true},
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com", "Ingress/default/foo/foo.example.com", "Ingress/default/foo/bar.example.com"},
+		},
+		{
+			name:    "applies monitor policies to the sources",
+			options: config.Options{EnableMonitorPolicy: true},
+			objects: []client.Object{
+				&v1alpha1.MonitorPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+					Spec: v1alpha1.MonitorPolicySpec{
+						Annotations: map[string]string{config.AnnotationProviders: "uptimerobot"},
+					},
+				},
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, nil)
+			},
+			expected: []string{"Ingress/default/foo", "Ingress/default/foo/foo.example.com"},
+			validate: func(t *testing.T, sources []models.MonitorSource) {
+				// The policy selects a non-default provider, which must be
+				// respected to keep the monitors there.
+				for _, source := range sources {
+					assert.Equal(t, "uptimerobot", source.Annotations[config.AnnotationProviders])
+				}
+			},
+		},
+		{
+			name: "returns service errors",
+			setup: func(s *fake.Service) {
+				s.On("DeleteOrphanedMonitors", mock.Anything).Return(nil, errors.New("whoops"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheme := fakeclient.NewClientBuilder().Build().Scheme()
+			_ = gatewayv1.Install(scheme)
+			_ = v1alpha1.AddToScheme(scheme)
+
+			client := fakeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(
+					&networkingv1.Ingress{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "foo",
+							Namespace: "default",
+							Annotations: map[string]string{
+								config.AnnotationEnabled:        "true",
+								config.AnnotationMonitoredHosts: "foo.example.com",
+							},
+						},
+						Spec: networkingv1.IngressSpec{
+							Rules: []networkingv1.IngressRule{
+								{Host: "foo.example.com"},
+								{Host: "bar.example.com"},
+							},
+						},
+					},
+					&networkingv1.Ingress{
+						ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"},
+					},
+					&gatewayv1.HTTPRoute{
+						ObjectMeta: metav1.ObjectMeta{Name: "qux", Namespace: "default", Annotations: enabled},
+					},
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default", Annotations: enabled},
+					},
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{Name: "disabled", Namespace: "default"},
+					},
+					func() client.Object {
+						rt := route.New()
+						rt.SetName("corge")
+						rt.SetNamespace("default")
+						rt.SetAnnotations(enabled)
+						return rt
+					}(),
+					func() client.Object {
+						rt := traefik.New()
+						rt.SetName("grault")
+						rt.SetNamespace("default")
+						rt.SetAnnotations(enabled)
+						return rt
+					}(),
+					func() client.Object {
+						proxy := contour.New()
+						proxy.SetName("garply")
+						proxy.SetNamespace("default")
+						proxy.SetAnnotations(enabled)
+						return proxy
+					}(),
+					&v1alpha1.Monitor{
+						ObjectMeta: metav1.ObjectMeta{Name: "quux", Namespace: "default"},
+						Spec:       v1alpha1.MonitorSpec{URL: "https://quux.example.com"},
+					},
+				).
+				WithObjects(test.objects...).
+				Build()
+
+			svc := &fake.Service{}
+
+			test.setup(svc)
+
+			gc := NewGarbageCollector(client, svc, &test.options)
+
+			err := gc.Collect(context.Background())
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			sources := svc.Calls[0].Arguments.Get(0).([]models.MonitorSource)
+
+			keys := make([]string, 0, len(sources))
+			for _, source := range sources {
+				key := source.Kind + "/" + source.Namespace + "/" + source.Name
+				if source.Host != "" {
+					key += "/" + source.Host + source.Path
+				}
+
+				keys = append(keys, key)
+			}
+
+			assert.Equal(t, test.expected, keys)
+
+			if test.validate != nil {
+				test.validate(t, sources)
+			}
+		})
+	}
+}
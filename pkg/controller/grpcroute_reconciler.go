@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/httproute"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GRPCRouteReconciler reconciles GRPCRoute resources to their desired
+// monitoring state.
+type GRPCRouteReconciler struct {
+	client.Client
+
+	gateways client.Reader
+	monitors *monitorHandler
+}
+
+// NewGRPCRouteReconciler creates a new *GRPCRouteReconciler. Events about
+// the monitors of a route are recorded on the route using recorder. Parent
+// Gateways outside of the watched namespaces are read using apiReader.
+func NewGRPCRouteReconciler(client client.Client, apiReader client.Reader, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *GRPCRouteReconciler {
+	return &GRPCRouteReconciler{
+		Client:   client,
+		gateways: httproute.NewGatewayReader(client, apiReader, options.WatchNamespaces()),
+		monitors: newMonitorHandler(client, recorder, monitorService, httproute.GRPCRouteKind, options),
+	}
+}
+
+// Reconcile creates, updates or deletes monitors whenever a GRPCRoute
+// changes. It implements reconcile.Reconciler.
+func (r *GRPCRouteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcileSource(ctx, r.monitors, req, &gatewayv1.GRPCRoute{}, r.buildSources)
+}
+
+// buildSources builds the monitor source of a GRPCRoute from the listeners
+// of its parent Gateways. It implements sourceBuilder.
+func (r *GRPCRouteReconciler) buildSources(ctx context.Context, route *gatewayv1.GRPCRoute) ([]models.MonitorSource, error) {
+	listeners, err := httproute.GRPCRouteParentListeners(ctx, r.gateways, route)
+	if err != nil {
+		return nil, err
+	}
+
+	err = httproute.ValidateGRPCRoute(route)
+	if err != nil {
+		metrics.GRPCRouteValidationErrorsTotal.WithLabelValues(route.Namespace, route.Name).Inc()
+		return nil, invalidSource(err)
+	}
+
+	return []models.MonitorSource{httproute.NewGRPCRouteMonitorSource(route, listeners)}, nil
+}
+
+// MapGatewayToGRPCRoutes maps a Gateway to reconcile requests for all
+// GRPCRoutes that reference it in their ParentRefs. It requires the
+// GatewayIndexField index to be registered for GRPCRoutes.
+func (r *GRPCRouteReconciler) MapGatewayToGRPCRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	routes := &gatewayv1.GRPCRouteList{}
+
+	err := r.List(ctx, routes, client.MatchingFields{GatewayIndexField: client.ObjectKeyFromObject(obj).String()})
+	if err != nil {
+		log.Error(err, "failed to list grpcroutes for gateway", "gateway", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(routes.Items))
+
+	for _, route := range routes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&route)})
+	}
+
+	return requests
+}
+
+// MapPolicyToGRPCRoutes maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all GRPCRoutes it may apply to.
+func (r *GRPCRouteReconciler) MapPolicyToGRPCRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, &gatewayv1.GRPCRouteList{})
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGRPCRouteReconciler_buildSources(t *testing.T) {
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "default"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 8443},
+			},
+		},
+	}
+
+	newRoute := func(hostnames ...gatewayv1.Hostname) *gatewayv1.GRPCRoute {
+		return &gatewayv1.GRPCRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: enabled},
+			Spec: gatewayv1.GRPCRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "public"}},
+				},
+				Hostnames: hostnames,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		obj         *gatewayv1.GRPCRoute
+		expected    []models.MonitorSource
+		expectedErr string
+	}{
+		{
+			name: "grpcroute uses the port of the parent listener",
+			obj:  newRoute("grpc.example.com"),
+			expected: []models.MonitorSource{{
+				Kind:        "GRPCRoute",
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: enabled,
+				Type:        models.MonitorTypeGRPC,
+				URL:         "https://grpc.example.com:8443",
+			}},
+		},
+		{
+			name:        "grpcroute without hostnames fails validation",
+			obj:         newRoute(),
+			expectedErr: "grpcroute does not have any hostnames",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewGRPCRouteReconciler(newHTTPRouteSchemeClient(gateway), nil, events.NewFakeRecorder(100), &fake.Service{}, &config.Options{})
+
+			sources, err := r.buildSources(context.Background(), test.obj)
+			if test.expectedErr != "" {
+				var invalidErr *invalidSourceError
+				require.ErrorAs(t, err, &invalidErr)
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sources)
+		})
+	}
+}
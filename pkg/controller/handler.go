@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/policy"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reasons of the events that are recorded on the monitored resources.
+const (
+	ReasonMonitorCreated   = "MonitorCreated"
+	ReasonMonitorUpdated   = "MonitorUpdated"
+	ReasonMonitorDeleted   = "MonitorDeleted"
+	ReasonValidationFailed = "ValidationFailed"
+	ReasonProviderError    = "ProviderError"
+
+	// In dry run mode, the operations are only reported using these
+	// reasons instead.
+	ReasonWouldCreateMonitor = "WouldCreateMonitor"
+	ReasonWouldUpdateMonitor = "WouldUpdateMonitor"
+	ReasonWouldDeleteMonitor = "WouldDeleteMonitor"
+)
+
+// monitorHandler ensures and deletes the monitors of a resource through the
+// monitor service, records their state on the resource and emits events
+// about the outcome. In dry run mode, the resource is never written to.
+type monitorHandler struct {
+	client        client.Client
+	recorder      events.EventRecorder
+	service       monitor.Service
+	policies      *policy.Resolver
+	kind          string
+	finalizer     bool
+	creationDelay time.Duration
+	dryRun        bool
+}
+
+// newMonitorHandler creates a new *monitorHandler for resources of kind.
+func newMonitorHandler(client client.Client, recorder events.EventRecorder, service monitor.Service, kind string, options *config.Options) *monitorHandler {
+	return &monitorHandler{
+		client:        client,
+		recorder:      recorder,
+		service:       service,
+		policies:      newPolicyResolver(client, options),
+		kind:          kind,
+		finalizer:     options.UseFinalizer,
+		creationDelay: options.CreationDelay,
+		dryRun:        options.DryRun,
+	}
+}
+
+// sourceBuilder builds the monitor sources of obj after the monitor policies
+// were applied to it. It returns an error created via invalidSource if obj
+// cannot be monitored.
+type sourceBuilder[T client.Object] func(ctx context.Context, obj T) ([]models.MonitorSource, error)
+
+// invalidSourceError is returned by a sourceBuilder if the resource fails
+// validation.
+type invalidSourceError struct {
+	err error
+}
+
+// invalidSource wraps the validation error err of a resource.
+func invalidSource(err error) error {
+	return &invalidSourceError{err: err}
+}
+
+// Error implements error.
+func (e *invalidSourceError) Error() string {
+	return e.err.Error()
+}
+
+// reconcileSource reads the resource referenced by req into obj and
+// reconciles its monitors. The monitors of resources that are gone or not
+// enabled anymore are deleted. For enabled resources, the monitors are
+// ensured for the sources returned by buildSources once the creation delay
+// has passed.
+func reconcileSource[T client.Object](ctx context.Context, h *monitorHandler, req reconcile.Request, obj T, buildSources sourceBuilder[T]) (reconcile.Result, error) {
+	err := h.client.Get(ctx, req.NamespacedName, obj)
+	if apierrors.IsNotFound(err) {
+		source := models.MonitorSource{
+			Kind:      h.kind,
+			Name:      req.Name,
+			Namespace: req.Namespace,
+		}
+
+		_, err = h.service.DeleteMonitor(source)
+	} else if err == nil {
+		if !obj.GetDeletionTimestamp().IsZero() {
+			err = h.finalize(ctx, obj)
+		} else if obj.GetAnnotations()[config.AnnotationEnabled] == "true" {
+			createAfter := time.Until(obj.GetCreationTimestamp().Add(h.creationDelay))
+
+			if createAfter > 0 {
+				return reconcile.Result{RequeueAfter: createAfter}, nil
+			}
+
+			err = createOrUpdateSource(ctx, h, obj, buildSources)
+		} else {
+			err = h.deleteMonitors(ctx, obj)
+		}
+	}
+
+	return resultForError(req, err)
+}
+
+// createOrUpdateSource applies the monitor policies to obj and ensures the
+// monitors for the sources returned by buildSources. Validation errors are
+// recorded on obj.
+func createOrUpdateSource[T client.Object](ctx context.Context, h *monitorHandler, obj T, buildSources sourceBuilder[T]) error {
+	// See IngressReconciler.handleCreateOrUpdate.
+	obj, err := policy.Apply(ctx, h.policies, obj)
+	if err != nil {
+		return err
+	}
+
+	sources, err := buildSources(ctx, obj)
+
+	var invalidErr *invalidSourceError
+	if errors.As(err, &invalidErr) {
+		return h.validationFailed(ctx, obj, invalidErr.err)
+	} else if err != nil {
+		return err
+	}
+
+	return h.ensureMonitors(ctx, obj, sources)
+}
+
+// ensureMonitors ensures that the monitors for all sources are present and
+// records their state in the annotations of obj. If finalizers are enabled,
+// the cleanup finalizer is added to obj before any monitor is created. If
+// the monitor service returns an error, it is recorded in the last-error
+// annotation of obj and returned.
+func (h *monitorHandler) ensureMonitors(ctx context.Context, obj client.Object, sources []models.MonitorSource) error {
+	err := h.addFinalizer(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	monitors := make([]*models.Monitor, 0, len(sources))
+	changed := false
+
+	for _, source := range sources {
+		result, err := h.service.EnsureMonitor(source)
+		if result != nil {
+			// Providers that succeeded still report their result if
+			// others failed.
+			h.recordResult(obj, result)
+		}
+
+		if err != nil {
+			h.recorder.Eventf(obj, nil, corev1.EventTypeWarning, failureReason(err), "EnsureMonitor", "%s", failureMessage("ensure monitor", err))
+
+			recordErr := h.recordMonitorError(ctx, obj, err)
+			if recordErr != nil {
+				log.Error(recordErr, "failed to record monitor error", "kind", h.kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+			}
+
+			return err
+		}
+
+		if result != nil {
+			monitors = append(monitors, resultMonitors(result)...)
+			changed = changed || result.Operation != monitor.OperationNone
+		}
+	}
+
+	return h.recordMonitorState(ctx, obj, monitors, changed)
+}
+
+// deleteMonitor deletes the monitor for source, which belongs to obj.
+func (h *monitorHandler) deleteMonitor(obj client.Object, source models.MonitorSource) error {
+	result, err := h.service.DeleteMonitor(source)
+	if result != nil {
+		h.recordResult(obj, result)
+	}
+
+	if err != nil {
+		h.recorder.Eventf(obj, nil, corev1.EventTypeWarning, ReasonProviderError, "DeleteMonitor", "%s", failureMessage("delete monitor", err))
+		return err
+	}
+
+	return nil
+}
+
+// validationFailed emits an event for the validation error err and records
+// it in the last-error annotation of obj.
+func (h *monitorHandler) validationFailed(ctx context.Context, obj client.Object, err error) error {
+	h.recorder.Eventf(obj, nil, corev1.EventTypeWarning, ReasonValidationFailed, "Validate", "Not monitoring %s: %v", h.kind, err)
+
+	return h.recordMonitorError(ctx, obj, err)
+}
+
+// recordResult emits events about the operations in result. If the monitor
+// is managed by multiple providers, an event is emitted for each provider
+// that succeeded.
+func (h *monitorHandler) recordResult(obj client.Object, result *monitor.Result) {
+	if result.Monitor == nil {
+		return
+	}
+
+	if len(result.Providers) <= 1 {
+		h.recordOperation(obj, result.Operation, result.Monitor.Name, "")
+		return
+	}
+
+	for _, providerResult := range result.Providers {
+		if providerResult.Err == nil {
+			h.recordOperation(obj, providerResult.Operation, providerResult.Monitor.Name, providerResult.Provider)
+		}
+	}
+}
+
+func (h *monitorHandler) recordOperation(obj client.Object, op monitor.Operation, name, provider string) {
+	target := strconv.Quote(name)
+	if provider != "" {
+		target = fmt.Sprintf("%q in provider %q", name, provider)
+	}
+
+	if h.dryRun {
+		switch op {
+		case monitor.OperationCreated:
+			h.recorder.Eventf(obj, nil, corev1.EventTypeNormal, ReasonWouldCreateMonitor, "CreateMonitor", "Would create monitor %s", target)
+		case monitor.OperationUpdated:
+			h.recorder.Eventf(obj, nil, corev1.EventTypeNormal, ReasonWouldUpdateMonitor, "UpdateMonitor", "Would update monitor %s", target)
+		case monitor.OperationDeleted:
+			h.recorder.Eventf(obj, nil, corev1.EventTypeNormal, ReasonWouldDeleteMonitor, "DeleteMonitor", "Would delete monitor %s", target)
+		}
+
+		return
+	}
+
+	switch op {
+	case monitor.OperationCreated:
+		h.recorder.Eventf(obj, nil, corev1.EventTypeNormal, ReasonMonitorCreated, "CreateMonitor", "Created monitor %s", target)
+	case monitor.OperationUpdated:
+		h.recorder.Eventf(obj, nil, corev1.EventTypeNormal, ReasonMonitorUpdated, "UpdateMonitor", "Updated monitor %s", target)
+	case monitor.OperationDeleted:
+		h.recorder.Eventf(obj, nil, corev1.EventTypeNormal, ReasonMonitorDeleted, "DeleteMonitor", "Deleted monitor %s", target)
+	}
+}
+
+// resultMonitors returns the monitors of all providers that succeeded in
+// result. Falls back to the monitor of result for services that do not
+// report provider results.
+func resultMonitors(result *monitor.Result) []*models.Monitor {
+	if len(result.Providers) == 0 {
+		if result.Monitor == nil {
+			return nil
+		}
+
+		return []*models.Monitor{result.Monitor}
+	}
+
+	monitors := make([]*models.Monitor, 0, len(result.Providers))
+
+	for _, providerResult := range result.Providers {
+		if providerResult.Err == nil {
+			monitors = append(monitors, providerResult.Monitor)
+		}
+	}
+
+	return monitors
+}
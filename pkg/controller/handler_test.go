@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileSource(t *testing.T) {
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	newService := func(annotations map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: annotations,
+			},
+		}
+	}
+
+	// buildSources returns a single source carrying the annotations of the
+	// service, so that the applied policy defaults are visible. Services
+	// with the "invalid" annotation fail validation.
+	buildSources := func(_ context.Context, svc *corev1.Service) ([]models.MonitorSource, error) {
+		if _, ok := svc.Annotations["invalid"]; ok {
+			return nil, invalidSource(errors.New("service is invalid"))
+		}
+
+		return []models.MonitorSource{{
+			Kind:        "Service",
+			Name:        svc.Name,
+			Namespace:   svc.Namespace,
+			Annotations: svc.Annotations,
+			URL:         "https://foo.example.com",
+		}}, nil
+	}
+
+	tests := []struct {
+		name     string
+		objects  []client.Object
+		options  config.Options
+		setup    func(*fake.Service)
+		validate func(*testing.T, client.Client, reconcile.Result)
+	}{
+		{
+			name: "it deletes monitors if the resource was deleted",
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", models.MonitorSource{Kind: "Service", Name: "foo", Namespace: "default"}).Return(nil, nil)
+			},
+		},
+		{
+			name: "it deletes monitors and removes the finalizer if the resource is being deleted",
+			objects: []client.Object{
+				func() client.Object {
+					svc := newService(enabled)
+					svc.Finalizers = []string{CleanupFinalizer}
+					svc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+					return svc
+				}(),
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", matchMonitorSource("foo", "default")).Return(nil, nil)
+			},
+		},
+		{
+			name:    "it ensures the monitors of the built sources",
+			objects: []client.Object{newService(enabled)},
+			setup: func(s *fake.Service) {
+				s.On("EnsureMonitor", models.MonitorSource{
+					Kind:        "Service",
+					Name:        "foo",
+					Namespace:   "default",
+					Annotations: enabled,
+					URL:         "https://foo.example.com",
+				}).Return(nil, nil)
+			},
+		},
+		{
+			name: "it applies monitor policies before building the sources",
+			objects: []client.Object{
+				newService(enabled),
+				&v1alpha1.MonitorPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+					Spec:       v1alpha1.MonitorPolicySpec{PathOverride: "/health"},
+				},
+			},
+			options: config.Options{EnableMonitorPolicy: true},
+			setup: func(s *fake.Service) {
+				s.On("EnsureMonitor", models.MonitorSource{
+					Kind:      "Service",
+					Name:      "foo",
+					Namespace: "default",
+					Annotations: map[string]string{
+						config.AnnotationEnabled:      "true",
+						config.AnnotationPathOverride: "/health",
+					},
+					URL: "https://foo.example.com",
+				}).Return(nil, nil)
+			},
+			validate: func(t *testing.T, c client.Client, _ reconcile.Result) {
+				svc := &corev1.Service{}
+				require.NoError(t, c.Get(context.Background(), req.NamespacedName, svc))
+
+				assert.NotContains(t, svc.Annotations, config.AnnotationPathOverride)
+			},
+		},
+		{
+			name:    "it deletes monitors if the resource is not enabled",
+			objects: []client.Object{newService(nil)},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", matchMonitorSource("foo", "default")).Return(nil, nil)
+			},
+		},
+		{
+			name:    "it records validation errors",
+			objects: []client.Object{newService(map[string]string{config.AnnotationEnabled: "true", "invalid": ""})},
+			validate: func(t *testing.T, c client.Client, _ reconcile.Result) {
+				svc := &corev1.Service{}
+				require.NoError(t, c.Get(context.Background(), req.NamespacedName, svc))
+
+				assert.Equal(t, "service is invalid", svc.Annotations[config.AnnotationLastError])
+			},
+		},
+		{
+			name: "it requeues resources until the creation delay passed",
+			objects: []client.Object{
+				func() client.Object {
+					svc := newService(enabled)
+					svc.CreationTimestamp = metav1.Now()
+					return svc
+				}(),
+			},
+			options: config.Options{CreationDelay: time.Minute},
+			validate: func(t *testing.T, _ client.Client, result reconcile.Result) {
+				assert.Greater(t, result.RequeueAfter, time.Duration(0))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl := newMonitorSchemeClient(test.objects...)
+
+			svc := &fake.Service{}
+
+			if test.setup != nil {
+				test.setup(svc)
+			}
+
+			h := newMonitorHandler(cl, events.NewFakeRecorder(100), svc, "Service", &test.options)
+
+			result, err := reconcileSource(context.Background(), h, req, &corev1.Service{}, buildSources)
+			require.NoError(t, err)
+
+			svc.AssertExpectations(t)
+
+			if test.validate != nil {
+				test.validate(t, cl, result)
+			} else {
+				assert.Equal(t, reconcile.Result{}, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/contour"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// HTTPProxyReconciler reconciles Contour HTTPProxies to their desired
+// monitoring state.
+type HTTPProxyReconciler struct {
+	client.Client
+
+	monitors *monitorHandler
+}
+
+// NewHTTPProxyReconciler creates a new *HTTPProxyReconciler. Events about the
+// monitors of a proxy are recorded on the proxy using recorder.
+func NewHTTPProxyReconciler(client client.Client, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *HTTPProxyReconciler {
+	return &HTTPProxyReconciler{
+		Client:   client,
+		monitors: newMonitorHandler(client, recorder, monitorService, contour.Kind, options),
+	}
+}
+
+// Reconcile creates, updates or deletes monitors whenever a Contour
+// HTTPProxy changes. It implements reconcile.Reconciler.
+func (r *HTTPProxyReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcileSource(ctx, r.monitors, req, contour.New(), r.buildSources)
+}
+
+// buildSources builds the monitor source of a Contour HTTPProxy. It
+// implements sourceBuilder.
+func (r *HTTPProxyReconciler) buildSources(_ context.Context, proxy *unstructured.Unstructured) ([]models.MonitorSource, error) {
+	err := contour.Validate(proxy)
+	if err != nil {
+		metrics.HTTPProxyValidationErrorsTotal.WithLabelValues(proxy.GetNamespace(), proxy.GetName()).Inc()
+		return nil, invalidSource(err)
+	}
+
+	source, err := contour.NewMonitorSource(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.MonitorSource{source}, nil
+}
+
+// MapPolicyToHTTPProxies maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all Contour HTTPProxies it may apply to.
+func (r *HTTPProxyReconciler) MapPolicyToHTTPProxies(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, contour.NewList())
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/contour"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHTTPProxyReconciler_buildSources(t *testing.T) {
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	newHTTPProxy := func(spec map[string]interface{}) *unstructured.Unstructured {
+		proxy := contour.New()
+		proxy.SetName("foo")
+		proxy.SetNamespace("default")
+		proxy.SetAnnotations(enabled)
+		proxy.Object["spec"] = spec
+		return proxy
+	}
+
+	tests := []struct {
+		name        string
+		obj         *unstructured.Unstructured
+		expected    []models.MonitorSource
+		expectedErr string
+	}{
+		{
+			name: "httpproxy with fqdn and route prefix",
+			obj: newHTTPProxy(map[string]interface{}{
+				"virtualhost": map[string]interface{}{
+					"fqdn": "foo.example.com",
+					"tls":  map[string]interface{}{"secretName": "foo-tls"},
+				},
+				"routes": []interface{}{
+					map[string]interface{}{
+						"conditions": []interface{}{map[string]interface{}{"prefix": "/api"}},
+					},
+				},
+			}),
+			expected: []models.MonitorSource{{
+				Kind:        "HTTPProxy",
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: enabled,
+				URL:         "https://foo.example.com/api",
+			}},
+		},
+		{
+			name:        "httpproxy without fqdn fails validation",
+			obj:         newHTTPProxy(map[string]interface{}{}),
+			expectedErr: "httpproxy does not have a virtualhost fqdn",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewHTTPProxyReconciler(fakeclient.NewClientBuilder().Build(), events.NewFakeRecorder(100), &fake.Service{}, &config.Options{})
+
+			sources, err := r.buildSources(context.Background(), test.obj)
+			if test.expectedErr != "" {
+				var invalidErr *invalidSourceError
+				require.ErrorAs(t, err, &invalidErr)
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sources)
+		})
+	}
+}
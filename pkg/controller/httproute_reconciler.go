@@ -9,28 +9,45 @@ import (
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/policy"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+var log = logf.Log.WithName("controller")
+
+// GatewayIndexField is the name of the field index that maps HTTPRoutes,
+// GRPCRoutes and TLSRoutes to the Gateways referenced in their ParentRefs.
+const GatewayIndexField = "spec.parentRefs.gateway"
+
 // HTTPRouteReconciler reconciles HTTPRoute resources to their desired
 // monitoring state.
 type HTTPRouteReconciler struct {
 	client.Client
 
+	gateways       client.Reader
 	monitorService monitor.Service
-	creationDelay  time.Duration
+	monitors       *monitorHandler
+	multiHost      bool
 }
 
-// NewHTTPRouteReconciler creates a new *HTTPRouteReconciler.
-func NewHTTPRouteReconciler(client client.Client, monitorService monitor.Service, options *config.Options) *HTTPRouteReconciler {
+// NewHTTPRouteReconciler creates a new *HTTPRouteReconciler. Events about the
+// monitors of a route are recorded on the route using recorder. Parent
+// Gateways outside of the watched namespaces are read using apiReader.
+func NewHTTPRouteReconciler(client client.Client, apiReader client.Reader, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *HTTPRouteReconciler {
 	return &HTTPRouteReconciler{
 		Client:         client,
+		gateways:       httproute.NewGatewayReader(client, apiReader, options.WatchNamespaces()),
 		monitorService: monitorService,
-		creationDelay:  options.CreationDelay,
+		monitors:       newMonitorHandler(client, recorder, monitorService, "HTTPRoute", options),
+		multiHost:      options.MultiHost,
 	}
 }
 
@@ -42,44 +59,141 @@ func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 	err := r.Get(ctx, req.NamespacedName, route)
 	if apierrors.IsNotFound(err) {
 		source := models.MonitorSource{
+			Kind:      "HTTPRoute",
 			Name:      req.Name,
 			Namespace: req.Namespace,
 		}
 
-		err = r.monitorService.DeleteMonitor(source)
+		_, err = r.monitorService.DeleteMonitor(source)
 	} else if err == nil {
-		if route.Annotations[config.AnnotationEnabled] == "true" {
-			createAfter := time.Until(route.CreationTimestamp.Add(r.creationDelay))
+		if !route.DeletionTimestamp.IsZero() {
+			err = r.monitors.finalize(ctx, route)
+		} else if route.Annotations[config.AnnotationEnabled] == "true" {
+			createAfter := time.Until(route.CreationTimestamp.Add(r.monitors.creationDelay))
 
 			if createAfter > 0 {
 				return reconcile.Result{RequeueAfter: createAfter}, nil
 			}
 
-			err = r.handleCreateOrUpdate(route)
+			err = r.handleCreateOrUpdate(ctx, route)
 		} else {
-			source := models.MonitorSource{
-				Name:      route.Name,
-				Namespace: route.Namespace,
-			}
-
-			err = r.monitorService.DeleteMonitor(source)
+			err = r.handleDelete(ctx, route)
 		}
 	}
 
-	return reconcile.Result{}, err
+	return resultForError(req, err)
 }
 
-func (r *HTTPRouteReconciler) handleCreateOrUpdate(route *gatewayv1.HTTPRoute) error {
-	err := httproute.Validate(route)
+func (r *HTTPRouteReconciler) handleCreateOrUpdate(ctx context.Context, route *gatewayv1.HTTPRoute) error {
+	listeners, err := httproute.ParentListeners(ctx, r.gateways, route)
+	if err != nil {
+		return err
+	}
+
+	// See IngressReconciler.handleCreateOrUpdate.
+	route, err = policy.Apply(ctx, r.monitors.policies, route)
+	if err != nil {
+		return err
+	}
+
+	if httproute.MultiHostEnabled(route, r.multiHost) {
+		return r.handleMultiHostCreateOrUpdate(ctx, route, listeners)
+	}
+
+	err = httproute.Validate(route)
 	if err != nil {
 		metrics.HTTPRouteValidationErrorsTotal.WithLabelValues(route.Namespace, route.Name).Inc()
-		return nil
+		return r.monitors.validationFailed(ctx, route, err)
 	}
 
-	source, err := httproute.NewMonitorSource(route)
+	source, err := httproute.NewMonitorSource(route, listeners)
 	if err != nil {
 		return err
 	}
 
-	return r.monitorService.EnsureMonitor(source)
+	err = r.monitors.ensureMonitors(ctx, route, []models.MonitorSource{source})
+	if err != nil {
+		return err
+	}
+
+	// Clean up the monitors of the individual hostnames in case the route
+	// was switched back from multi host mode.
+	return r.monitors.reconcileMonitoredHosts(ctx, route, nil)
+}
+
+// handleMultiHostCreateOrUpdate ensures that there is a monitor for each
+// distinct hostname of the route and deletes the monitors of hostnames that
+// were removed from the route.
+func (r *HTTPRouteReconciler) handleMultiHostCreateOrUpdate(ctx context.Context, route *gatewayv1.HTTPRoute, listeners []gatewayv1.Listener) error {
+	sources, err := httproute.NewMonitorSources(route, listeners)
+	if err != nil {
+		metrics.HTTPRouteValidationErrorsTotal.WithLabelValues(route.Namespace, route.Name).Inc()
+		return r.monitors.validationFailed(ctx, route, err)
+	}
+
+	err = r.monitors.ensureMonitors(ctx, route, sources)
+	if err != nil {
+		return err
+	}
+
+	return r.monitors.reconcileMonitoredHosts(ctx, route, sources)
+}
+
+// handleDelete deletes all monitors of a route that is not enabled anymore.
+func (r *HTTPRouteReconciler) handleDelete(ctx context.Context, route *gatewayv1.HTTPRoute) error {
+	return r.monitors.deleteMonitors(ctx, route)
+}
+
+// MapGatewayToHTTPRoutes maps a Gateway to reconcile requests for all
+// HTTPRoutes that reference it in their ParentRefs. This is used to
+// re-reconcile routes whenever the listeners of their Gateway change. It
+// requires the GatewayIndexField index to be registered.
+func (r *HTTPRouteReconciler) MapGatewayToHTTPRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	routes := &gatewayv1.HTTPRouteList{}
+
+	err := r.List(ctx, routes, client.MatchingFields{GatewayIndexField: client.ObjectKeyFromObject(obj).String()})
+	if err != nil {
+		log.Error(err, "failed to list httproutes for gateway", "gateway", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(routes.Items))
+
+	for _, route := range routes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&route)})
+	}
+
+	return requests
+}
+
+// MapPolicyToHTTPRoutes maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all HTTPRoutes it may apply to.
+func (r *HTTPRouteReconciler) MapPolicyToHTTPRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, &gatewayv1.HTTPRouteList{})
+}
+
+// IndexParentGateways is a client.IndexerFunc which indexes HTTPRoutes,
+// GRPCRoutes and TLSRoutes by the namespaced names of the Gateways referenced
+// in their ParentRefs.
+func IndexParentGateways(obj client.Object) []string {
+	var gateways []types.NamespacedName
+
+	switch route := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		gateways = httproute.ParentGateways(route)
+	case *gatewayv1.GRPCRoute:
+		gateways = httproute.GRPCRouteParentGateways(route)
+	case *gatewayv1alpha2.TLSRoute:
+		gateways = httproute.TLSRouteParentGateways(route)
+	default:
+		return nil
+	}
+
+	values := make([]string, 0, len(gateways))
+
+	for _, gateway := range gateways {
+		values = append(values, gateway.String())
+	}
+
+	return values
 }
@@ -9,9 +9,11 @@ import (
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -21,7 +23,11 @@ import (
 func newHTTPRouteSchemeClient(objects ...client.Object) client.Client {
 	scheme := fakeclient.NewClientBuilder().Build().Scheme()
 	_ = gatewayv1.Install(scheme)
-	return fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	return fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithIndex(&gatewayv1.HTTPRoute{}, GatewayIndexField, IndexParentGateways).
+		Build()
 }
 
 func TestHTTPRouteReconciler_Reconcile(t *testing.T) {
@@ -43,7 +49,7 @@ func TestHTTPRouteReconciler_Reconcile(t *testing.T) {
 				},
 			},
 			setup: func(s *fake.Service) {
-				s.On("DeleteMonitor", matchMonitorSource("foo", "default")).Return(nil)
+				s.On("DeleteMonitor", matchMonitorSource("foo", "default")).Return(nil, nil)
 			},
 		},
 		{
@@ -81,7 +87,7 @@ func TestHTTPRouteReconciler_Reconcile(t *testing.T) {
 						config.AnnotationEnabled: "true",
 					},
 					URL: "https://bar.example.com",
-				}).Return(nil)
+				}).Return(nil, nil)
 			},
 		},
 		{
@@ -108,7 +114,7 @@ func TestHTTPRouteReconciler_Reconcile(t *testing.T) {
 				})
 			},
 			setup: func(s *fake.Service) {
-				s.On("DeleteMonitor", matchMonitorSource("bar", "default")).Return(nil)
+				s.On("DeleteMonitor", matchMonitorSource("bar", "default")).Return(nil, nil)
 			},
 		},
 		{
@@ -135,6 +141,111 @@ func TestHTTPRouteReconciler_Reconcile(t *testing.T) {
 				})
 			},
 		},
+		{
+			name: "it derives the scheme and port from the parent gateway listener",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "default",
+				},
+			},
+			clientFn: func() client.Client {
+				return newHTTPRouteSchemeClient(
+					&gatewayv1.Gateway{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "internal",
+							Namespace: "default",
+						},
+						Spec: gatewayv1.GatewaySpec{
+							Listeners: []gatewayv1.Listener{
+								{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 8080},
+							},
+						},
+					},
+					&gatewayv1.HTTPRoute{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "bar",
+							Namespace: "default",
+							Annotations: map[string]string{
+								config.AnnotationEnabled: "true",
+							},
+						},
+						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{
+								ParentRefs: []gatewayv1.ParentReference{{Name: "internal"}},
+							},
+							Hostnames: []gatewayv1.Hostname{"bar.example.com"},
+						},
+					},
+				)
+			},
+			setup: func(s *fake.Service) {
+				s.On("EnsureMonitor", mock.MatchedBy(func(source models.MonitorSource) bool {
+					return source.URL == "http://bar.example.com:8080"
+				})).Return(nil, nil)
+			},
+		},
+		{
+			name:    "it ensures one monitor per hostname in multi host mode",
+			options: config.Options{MultiHost: true},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "default",
+				},
+			},
+			clientFn: func() client.Client {
+				return newHTTPRouteSchemeClient(&gatewayv1.HTTPRoute{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "HTTPRoute",
+						APIVersion: "gateway.networking.k8s.io/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "default",
+						Annotations: map[string]string{
+							config.AnnotationEnabled:        "true",
+							config.AnnotationMonitoredHosts: "bar.example.com,old.example.com",
+						},
+					},
+					Spec: gatewayv1.HTTPRouteSpec{
+						Hostnames: []gatewayv1.Hostname{"bar.example.com", "foo.example.com"},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("EnsureMonitor", matchMonitorSourceHost("bar", "default", "bar.example.com")).Return(nil, nil)
+				s.On("EnsureMonitor", matchMonitorSourceHost("bar", "default", "foo.example.com")).Return(nil, nil)
+				s.On("DeleteMonitor", matchMonitorSourceHost("bar", "default", "old.example.com")).Return(nil, nil)
+			},
+		},
+		{
+			name: "it deletes the monitor of an httproute that is being deleted and removes the finalizer",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "default",
+				},
+			},
+			clientFn: func() client.Client {
+				return newHTTPRouteSchemeClient(&gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "bar",
+						Namespace:         "default",
+						DeletionTimestamp: &metav1.Time{Time: time.Now()},
+						Finalizers:        []string{CleanupFinalizer},
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", mock.MatchedBy(func(source models.MonitorSource) bool {
+					return source.Kind == "HTTPRoute" && source.Name == "bar" && source.Namespace == "default"
+				})).Return(nil, nil)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -153,7 +264,7 @@ func TestHTTPRouteReconciler_Reconcile(t *testing.T) {
 				test.setup(svc)
 			}
 
-			r := NewHTTPRouteReconciler(cl, svc, &test.options)
+			r := NewHTTPRouteReconciler(cl, nil, events.NewFakeRecorder(100), svc, &test.options)
 
 			result, err := r.Reconcile(context.Background(), test.req)
 			if test.expectError {
@@ -187,7 +298,7 @@ func TestHTTPRouteReconciler_Reconcile_DelayCreation(t *testing.T) {
 		},
 	})
 
-	r := NewHTTPRouteReconciler(cl, &fake.Service{}, &config.Options{
+	r := NewHTTPRouteReconciler(cl, nil, events.NewFakeRecorder(100), &fake.Service{}, &config.Options{
 		CreationDelay: 1 * time.Minute,
 	})
 
@@ -205,3 +316,36 @@ func TestHTTPRouteReconciler_Reconcile_DelayCreation(t *testing.T) {
 		t.Fatalf("expected result.RequeueAfter to be greater than 0, got %s", result.RequeueAfter)
 	}
 }
+
+func TestHTTPRouteReconciler_MapGatewayToHTTPRoutes(t *testing.T) {
+	namespace := gatewayv1.Namespace("infra")
+
+	cl := newHTTPRouteSchemeClient(
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "public", Namespace: &namespace}},
+				},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "public"}},
+				},
+			},
+		},
+	)
+
+	r := NewHTTPRouteReconciler(cl, nil, events.NewFakeRecorder(100), &fake.Service{}, &config.Options{})
+
+	requests := r.MapGatewayToHTTPRoutes(context.Background(), &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "infra"},
+	})
+
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}},
+	}, requests)
+}
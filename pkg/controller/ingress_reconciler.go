@@ -9,8 +9,10 @@ import (
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/policy"
 	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -30,15 +32,18 @@ type IngressReconciler struct {
 	client.Client
 
 	monitorService IngressService
-	creationDelay  time.Duration
+	monitors       *monitorHandler
+	multiHost      bool
 }
 
-// NewIngressReconciler creates a new *IngressReconciler.
-func NewIngressReconciler(client client.Client, monitorService IngressService, options *config.Options) *IngressReconciler {
+// NewIngressReconciler creates a new *IngressReconciler. Events about the
+// monitors of an ingress are recorded on the ingress using recorder.
+func NewIngressReconciler(client client.Client, recorder events.EventRecorder, monitorService IngressService, options *config.Options) *IngressReconciler {
 	return &IngressReconciler{
 		Client:         client,
 		monitorService: monitorService,
-		creationDelay:  options.CreationDelay,
+		monitors:       newMonitorHandler(client, recorder, monitorService, "Ingress", options),
+		multiHost:      options.MultiHost,
 	}
 }
 
@@ -50,16 +55,20 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req reconcile.Request
 	err := r.Get(ctx, req.NamespacedName, ing)
 	if apierrors.IsNotFound(err) {
 		// The ingress was deleted. Construct a minimal source for monitor
-		// deletion.
+		// deletion. If the ingress carried the cleanup finalizer, its
+		// monitors were already deleted before.
 		source := models.MonitorSource{
+			Kind:      "Ingress",
 			Name:      req.Name,
 			Namespace: req.Namespace,
 		}
 
-		err = r.monitorService.DeleteMonitor(source)
+		_, err = r.monitorService.DeleteMonitor(source)
 	} else if err == nil {
-		if ing.Annotations[config.AnnotationEnabled] == "true" {
-			createAfter := time.Until(ing.CreationTimestamp.Add(r.creationDelay))
+		if !ing.DeletionTimestamp.IsZero() {
+			err = r.monitors.finalize(ctx, ing)
+		} else if ing.Annotations[config.AnnotationEnabled] == "true" {
+			createAfter := time.Until(ing.CreationTimestamp.Add(r.monitors.creationDelay))
 
 			// If a creation delay was configured, we will requeue the
 			// reconciliation until after the creation delay passed.
@@ -69,16 +78,11 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req reconcile.Request
 
 			err = r.handleCreateOrUpdate(ctx, ing)
 		} else {
-			source := models.MonitorSource{
-				Name:      ing.Name,
-				Namespace: ing.Namespace,
-			}
-
-			err = r.monitorService.DeleteMonitor(source)
+			err = r.handleDelete(ctx, ing)
 		}
 	}
 
-	return reconcile.Result{}, err
+	return resultForError(req, err)
 }
 
 func (r *IngressReconciler) handleCreateOrUpdate(ctx context.Context, ing *networkingv1.Ingress) error {
@@ -94,10 +98,23 @@ func (r *IngressReconciler) handleCreateOrUpdate(ctx context.Context, ing *netwo
 		return err
 	}
 
+	// The monitor sources are built from a copy of the ingress that carries
+	// the policy defaults as annotations. Writes to the copy only patch the
+	// state annotations and the finalizer, so the defaults are never
+	// persisted.
+	ing, err = policy.Apply(ctx, r.monitors.policies, ing)
+	if err != nil {
+		return err
+	}
+
+	if ingress.MultiHostEnabled(ing, r.multiHost) {
+		return r.handleMultiHostCreateOrUpdate(ctx, ing)
+	}
+
 	err = ingress.Validate(ing)
 	if err != nil {
 		metrics.IngressValidationErrorsTotal.WithLabelValues(ing.Namespace, ing.Name).Inc()
-		return nil
+		return r.monitors.validationFailed(ctx, ing, err)
 	}
 
 	source, err := ingress.NewMonitorSource(ing)
@@ -105,7 +122,38 @@ func (r *IngressReconciler) handleCreateOrUpdate(ctx context.Context, ing *netwo
 		return err
 	}
 
-	return r.monitorService.EnsureMonitor(source)
+	err = r.monitors.ensureMonitors(ctx, ing, []models.MonitorSource{source})
+	if err != nil {
+		return err
+	}
+
+	// Clean up the monitors of the individual hosts in case the ingress was
+	// switched back from multi host mode.
+	return r.monitors.reconcileMonitoredHosts(ctx, ing, nil)
+}
+
+// handleMultiHostCreateOrUpdate ensures that there is a monitor for each
+// distinct host (and path) of the ingress and deletes the monitors of hosts
+// that were removed from the ingress.
+func (r *IngressReconciler) handleMultiHostCreateOrUpdate(ctx context.Context, ing *networkingv1.Ingress) error {
+	sources, err := ingress.NewMonitorSources(ing)
+	if err != nil {
+		metrics.IngressValidationErrorsTotal.WithLabelValues(ing.Namespace, ing.Name).Inc()
+		return r.monitors.validationFailed(ctx, ing, err)
+	}
+
+	err = r.monitors.ensureMonitors(ctx, ing, sources)
+	if err != nil {
+		return err
+	}
+
+	return r.monitors.reconcileMonitoredHosts(ctx, ing, sources)
+}
+
+// handleDelete deletes all monitors of an ingress that is not enabled
+// anymore.
+func (r *IngressReconciler) handleDelete(ctx context.Context, ing *networkingv1.Ingress) error {
+	return r.monitors.deleteMonitors(ctx, ing)
 }
 
 // reconcileAnnotations reconciles the ingress annotations, that is, it may
@@ -113,7 +161,8 @@ func (r *IngressReconciler) handleCreateOrUpdate(ctx context.Context, ing *netwo
 // with ip source ranges of the monitor provider. If annotations were updated,
 // it will update the ingress object on the cluster and return true and the
 // first return value. The will effectively cause the creation of a new ingress
-// update event which is then picked up by the reconciler.
+// update event which is then picked up by the reconciler. In dry run mode,
+// the update is only logged.
 func (r *IngressReconciler) reconcileAnnotations(ctx context.Context, ingress *networkingv1.Ingress) (updated bool, err error) {
 	ingressCopy := ingress.DeepCopy()
 
@@ -122,6 +171,11 @@ func (r *IngressReconciler) reconcileAnnotations(ctx context.Context, ingress *n
 		return false, err
 	}
 
+	if r.monitors.dryRun {
+		log.Info("would update ingress annotations", "namespace", ingress.Namespace, "name", ingress.Name, "annotations", ingressCopy.Annotations)
+		return false, nil
+	}
+
 	err = r.Update(ctx, ingressCopy)
 	if err != nil {
 		return false, err
@@ -129,3 +183,9 @@ func (r *IngressReconciler) reconcileAnnotations(ctx context.Context, ingress *n
 
 	return true, nil
 }
+
+// MapPolicyToIngresses maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all Ingresses it may apply to.
+func (r *IngressReconciler) MapPolicyToIngresses(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, &networkingv1.IngressList{})
+}
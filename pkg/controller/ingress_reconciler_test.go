@@ -2,18 +2,22 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -51,16 +55,39 @@ func matchMonitorSource(name, namespace string) interface{} {
 	})
 }
 
+// matchMonitorSourceHost creates a matcher that verifies a MonitorSource has
+// the expected name, namespace and host.
+func matchMonitorSourceHost(name, namespace, host string) interface{} {
+	return mock.MatchedBy(func(source models.MonitorSource) bool {
+		return source.Name == name && source.Namespace == namespace && source.Host == host
+	})
+}
+
+// recordedEvents drains the events that were recorded by recorder.
+func recordedEvents(recorder *events.FakeRecorder) []string {
+	var result []string
+
+	for {
+		select {
+		case event := <-recorder.Events:
+			result = append(result, event)
+		default:
+			return result
+		}
+	}
+}
+
 func TestIngressReconciler_Reconcile(t *testing.T) {
 	tests := []struct {
-		name        string
-		clientFn    func() client.Client
-		setup       func(*fake.Service)
-		options     config.Options
-		req         reconcile.Request
-		expected    reconcile.Result
-		expectError bool
-		validate    func(*testing.T, client.Client, *fake.Service)
+		name           string
+		clientFn       func() client.Client
+		setup          func(*fake.Service)
+		options        config.Options
+		req            reconcile.Request
+		expected       reconcile.Result
+		expectError    bool
+		expectedEvents []string
+		validate       func(*testing.T, client.Client, *fake.Service)
 	}{
 		{
 			name: "it deletes monitors if ingress was deleted",
@@ -71,7 +98,7 @@ func TestIngressReconciler_Reconcile(t *testing.T) {
 				},
 			},
 			setup: func(s *fake.Service) {
-				s.On("DeleteMonitor", matchMonitorSource("foo", "kube-system")).Return(nil)
+				s.On("DeleteMonitor", matchMonitorSource("foo", "kube-system")).Return(nil, nil)
 			},
 		},
 		{
@@ -108,7 +135,7 @@ func TestIngressReconciler_Reconcile(t *testing.T) {
 				}
 
 				s.On("AnnotateIngress", matchIngressWithAnnotations("bar", "kube-system", annotations)).Return(false, nil)
-				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, nil)
 			},
 		},
 		{
@@ -168,7 +195,623 @@ func TestIngressReconciler_Reconcile(t *testing.T) {
 				})
 			},
 			setup: func(s *fake.Service) {
-				s.On("DeleteMonitor", matchMonitorSource("bar", "kube-system")).Return(nil)
+				s.On("DeleteMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, nil)
+			},
+		},
+		{
+			name:    "it ensures one monitor per host in multi host mode",
+			options: config.Options{MultiHost: true},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "foo.example.com"},
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSourceHost("bar", "kube-system", "foo.example.com")).Return(nil, nil)
+				s.On("EnsureMonitor", matchMonitorSourceHost("bar", "kube-system", "bar.example.com")).Return(nil, nil)
+				// The monitor of the single host mode is cleaned up.
+				s.On("DeleteMonitor", matchMonitorSourceHost("bar", "kube-system", "")).Return(nil, nil)
+			},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "bar.example.com,foo.example.com", ing.Annotations[config.AnnotationMonitoredHosts])
+			},
+		},
+		{
+			name: "it deletes monitors of hosts that were removed from the ingress",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled:        "true",
+							config.AnnotationMultiHost:      "true",
+							config.AnnotationMonitoredHosts: "bar.example.com,old.example.com/api",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSourceHost("bar", "kube-system", "bar.example.com")).Return(nil, nil)
+				s.On("DeleteMonitor", mock.MatchedBy(func(source models.MonitorSource) bool {
+					return source.Host == "old.example.com" && source.Path == "/api"
+				})).Return(nil, nil)
+			},
+			validate: func(t *testing.T, c client.Client, s *fake.Service) {
+				s.AssertNumberOfCalls(t, "DeleteMonitor", 1)
+
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "bar.example.com", ing.Annotations[config.AnnotationMonitoredHosts])
+			},
+		},
+		{
+			name: "it deletes monitors of all recorded hosts if ingress does not have annotation",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationMonitoredHosts: "bar.example.com,foo.example.com",
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", matchMonitorSourceHost("bar", "kube-system", "")).Return(nil, nil)
+				s.On("DeleteMonitor", matchMonitorSourceHost("bar", "kube-system", "bar.example.com")).Return(nil, nil)
+				s.On("DeleteMonitor", matchMonitorSourceHost("bar", "kube-system", "foo.example.com")).Return(nil, nil)
+			},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.NotContains(t, ing.Annotations, config.AnnotationMonitoredHosts)
+			},
+		},
+		{
+			name: "it records the monitor state in the ingress annotations",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled:   "true",
+							config.AnnotationLastError: "whoops",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(&monitor.Result{
+					Monitor:   &models.Monitor{ID: "123", Name: "kube-system-bar"},
+					Operation: monitor.OperationCreated,
+				}, nil)
+			},
+			expectedEvents: []string{`Normal MonitorCreated Created monitor "kube-system-bar"`},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "123", ing.Annotations[config.AnnotationMonitorID])
+				assert.Equal(t, "kube-system-bar", ing.Annotations[config.AnnotationMonitorName])
+				assert.NotEmpty(t, ing.Annotations[config.AnnotationLastSync])
+				assert.NotContains(t, ing.Annotations, config.AnnotationLastError)
+			},
+		},
+		{
+			name: "it does not write to the ingress in dry run mode",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			options: config.Options{DryRun: true, UseFinalizer: true},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Run(func(args mock.Arguments) {
+					ing := args.Get(0).(*networkingv1.Ingress)
+					ing.Annotations["nginx.ingress.kubernetes.io/whitelist-source-range"] = "1.2.3.4/32"
+				}).Return(true, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(&monitor.Result{
+					Monitor:   &models.Monitor{ID: "123", Name: "kube-system-bar"},
+					Operation: monitor.OperationCreated,
+				}, nil)
+			},
+			expectedEvents: []string{`Normal WouldCreateMonitor Would create monitor "kube-system-bar"`},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, map[string]string{config.AnnotationEnabled: "true"}, ing.Annotations)
+				assert.Empty(t, ing.Finalizers)
+			},
+		},
+		{
+			name: "it records the state of monitors managed by multiple providers",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled:   "true",
+							config.AnnotationProviders: "site24x7,uptimekuma",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(&monitor.Result{
+					Monitor:   &models.Monitor{ID: "123", Name: "kube-system-bar"},
+					Operation: monitor.OperationCreated,
+					Providers: []monitor.ProviderResult{
+						{Provider: "site24x7", Monitor: &models.Monitor{ID: "123", Name: "kube-system-bar"}, Operation: monitor.OperationNone},
+						{Provider: "uptimekuma", Monitor: &models.Monitor{ID: "42", Name: "kube-system-bar"}, Operation: monitor.OperationCreated},
+					},
+				}, nil)
+			},
+			expectedEvents: []string{`Normal MonitorCreated Created monitor "kube-system-bar" in provider "uptimekuma"`},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "123,42", ing.Annotations[config.AnnotationMonitorID])
+				assert.Equal(t, "kube-system-bar", ing.Annotations[config.AnnotationMonitorName])
+			},
+		},
+		{
+			name: "it records validation errors in the ingress annotations",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+			},
+			expectedEvents: []string{"Warning ValidationFailed Not monitoring Ingress: ingress does not have any rules"},
+			validate: func(t *testing.T, c client.Client, s *fake.Service) {
+				s.AssertNotCalled(t, "EnsureMonitor", mock.Anything)
+
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "ingress does not have any rules", ing.Annotations[config.AnnotationLastError])
+			},
+		},
+		{
+			name: "it records provider errors in the ingress annotations",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled:     "true",
+							config.AnnotationMonitorID:   "123",
+							config.AnnotationMonitorName: "kube-system-bar",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, errors.New("provider unavailable"))
+			},
+			expectError:    true,
+			expectedEvents: []string{"Warning ProviderError Failed to ensure monitor: provider unavailable"},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "provider unavailable", ing.Annotations[config.AnnotationLastError])
+				assert.Equal(t, "123", ing.Annotations[config.AnnotationMonitorID])
+			},
+		},
+		{
+			name: "it tolerates results without monitor",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(&monitor.Result{Operation: monitor.OperationNone}, nil)
+			},
+			expected: reconcile.Result{},
+		},
+		{
+			name: "it does not patch the state of up to date monitors",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled:     "true",
+							config.AnnotationMonitorID:   "123",
+							config.AnnotationMonitorName: "kube-system-bar",
+							config.AnnotationLastSync:    "2026-01-01T00:00:00Z",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(&monitor.Result{
+					Monitor:   &models.Monitor{ID: "123", Name: "kube-system-bar"},
+					Operation: monitor.OperationNone,
+				}, nil)
+			},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "2026-01-01T00:00:00Z", ing.Annotations[config.AnnotationLastSync])
+				assert.Equal(t, "999", ing.ResourceVersion)
+			},
+		},
+		{
+			name: "it does not requeue on permanent provider errors",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, models.InvalidConfig(errors.New("invalid check frequency")))
+			},
+			expected:       reconcile.Result{},
+			expectedEvents: []string{"Warning ProviderError Failed to ensure monitor, not retrying: invalid check frequency"},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, "invalid check frequency", ing.Annotations[config.AnnotationLastError])
+			},
+		},
+		{
+			name: "it surfaces annotation validation errors",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled:         "true",
+							config.AnnotationSite24x7Timeout: "abc",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, models.InvalidConfig(config.AnnotationErrors{
+					{Name: config.AnnotationSite24x7Timeout, Value: "abc", Reason: `invalid integer "abc"`},
+				}))
+			},
+			expected:       reconcile.Result{},
+			expectedEvents: []string{`Warning ValidationFailed Failed to ensure monitor, not retrying: annotation "site24x7.ingress-monitor.bonial.com/timeout": invalid integer "abc"`},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, `annotation "site24x7.ingress-monitor.bonial.com/timeout": invalid integer "abc"`, ing.Annotations[config.AnnotationLastError])
+			},
+		},
+		{
+			name: "it requeues after the requested duration if provider is rate limiting",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, models.RateLimited(errors.New("too many requests"), 30*time.Second))
+			},
+			expected:       reconcile.Result{RequeueAfter: 30 * time.Second},
+			expectedEvents: []string{"Warning ProviderError Failed to ensure monitor: too many requests"},
+		},
+		{
+			name: "it removes the monitor state if ingress does not have annotation",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationMonitorID:   "123",
+							config.AnnotationMonitorName: "kube-system-bar",
+							config.AnnotationLastSync:    "2026-01-01T00:00:00Z",
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", matchMonitorSource("bar", "kube-system")).Return(&monitor.Result{
+					Monitor:   &models.Monitor{Name: "kube-system-bar"},
+					Operation: monitor.OperationDeleted,
+				}, nil)
+			},
+			expectedEvents: []string{`Normal MonitorDeleted Deleted monitor "kube-system-bar"`},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Empty(t, ing.Annotations)
+			},
+		},
+		{
+			name:    "it adds the cleanup finalizer if finalizers are enabled",
+			options: config.Options{UseFinalizer: true},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bar",
+						Namespace: "kube-system",
+						Annotations: map[string]string{
+							config.AnnotationEnabled: "true",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "bar.example.com"},
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("AnnotateIngress", mock.Anything).Return(false, nil)
+				s.On("EnsureMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, nil)
+			},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, []string{CleanupFinalizer}, ing.Finalizers)
+			},
+		},
+		{
+			name: "it deletes all monitors of an ingress that is being deleted and removes the finalizer",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "bar",
+						Namespace:         "kube-system",
+						DeletionTimestamp: &metav1.Time{Time: time.Now()},
+						Finalizers:        []string{CleanupFinalizer},
+						Annotations: map[string]string{
+							config.AnnotationEnabled:        "true",
+							config.AnnotationMonitoredHosts: "bar.example.com",
+						},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", mock.MatchedBy(func(source models.MonitorSource) bool {
+					return source.Kind == "Ingress" && source.Name == "bar" && source.Host == ""
+				})).Return(nil, nil)
+				s.On("DeleteMonitor", matchMonitorSourceHost("bar", "kube-system", "bar.example.com")).Return(nil, nil)
+			},
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				err := c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, &networkingv1.Ingress{})
+				assert.True(t, apierrors.IsNotFound(err))
+			},
+		},
+		{
+			name: "it keeps the finalizer if monitor deletion fails",
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "bar",
+					Namespace: "kube-system",
+				},
+			},
+			clientFn: func() client.Client {
+				return fakeclient.NewFakeClient(&networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "bar",
+						Namespace:         "kube-system",
+						DeletionTimestamp: &metav1.Time{Time: time.Now()},
+						Finalizers:        []string{CleanupFinalizer},
+					},
+				})
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", matchMonitorSource("bar", "kube-system")).Return(nil, errors.New("provider unavailable"))
+			},
+			expectError: true,
+			validate: func(t *testing.T, c client.Client, _ *fake.Service) {
+				ing := &networkingv1.Ingress{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "bar", Namespace: "kube-system"}, ing))
+				assert.Equal(t, []string{CleanupFinalizer}, ing.Finalizers)
 			},
 		},
 	}
@@ -189,7 +832,9 @@ func TestIngressReconciler_Reconcile(t *testing.T) {
 				test.setup(svc)
 			}
 
-			r := NewIngressReconciler(client, svc, &test.options)
+			recorder := events.NewFakeRecorder(100)
+
+			r := NewIngressReconciler(client, recorder, svc, &test.options)
 
 			result, err := r.Reconcile(context.Background(), test.req)
 			if test.expectError {
@@ -199,6 +844,10 @@ func TestIngressReconciler_Reconcile(t *testing.T) {
 				assert.Equal(t, test.expected, result)
 			}
 
+			if test.expectedEvents != nil {
+				assert.Equal(t, test.expectedEvents, recordedEvents(recorder))
+			}
+
 			if test.validate != nil {
 				test.validate(t, client, svc)
 			}
@@ -225,7 +874,7 @@ func TestIngressReconciler_Reconcile_DelayCreation(t *testing.T) {
 		},
 	})
 
-	r := NewIngressReconciler(client, &fake.Service{}, &config.Options{
+	r := NewIngressReconciler(client, events.NewFakeRecorder(100), &fake.Service{}, &config.Options{
 		CreationDelay: 1 * time.Minute,
 	})
 
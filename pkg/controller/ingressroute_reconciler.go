@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/traefik"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// IngressRouteReconciler reconciles Traefik IngressRoutes to their desired
+// monitoring state.
+type IngressRouteReconciler struct {
+	client.Client
+
+	monitors *monitorHandler
+}
+
+// NewIngressRouteReconciler creates a new *IngressRouteReconciler. Events
+// about the monitors of a route are recorded on the route using recorder.
+func NewIngressRouteReconciler(client client.Client, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *IngressRouteReconciler {
+	return &IngressRouteReconciler{
+		Client:   client,
+		monitors: newMonitorHandler(client, recorder, monitorService, traefik.Kind, options),
+	}
+}
+
+// Reconcile creates, updates or deletes monitors whenever a Traefik
+// IngressRoute changes. It implements reconcile.Reconciler.
+func (r *IngressRouteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcileSource(ctx, r.monitors, req, traefik.New(), r.buildSources)
+}
+
+// buildSources builds the monitor source of a Traefik IngressRoute. It
+// implements sourceBuilder.
+func (r *IngressRouteReconciler) buildSources(_ context.Context, rt *unstructured.Unstructured) ([]models.MonitorSource, error) {
+	err := traefik.Validate(rt)
+	if err != nil {
+		metrics.IngressRouteValidationErrorsTotal.WithLabelValues(rt.GetNamespace(), rt.GetName()).Inc()
+		return nil, invalidSource(err)
+	}
+
+	source, err := traefik.NewMonitorSource(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.MonitorSource{source}, nil
+}
+
+// MapPolicyToIngressRoutes maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all Traefik IngressRoutes it may apply to.
+func (r *IngressRouteReconciler) MapPolicyToIngressRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, traefik.NewList())
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/traefik"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIngressRouteReconciler_buildSources(t *testing.T) {
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	newIngressRoute := func(spec map[string]interface{}) *unstructured.Unstructured {
+		rt := traefik.New()
+		rt.SetName("foo")
+		rt.SetNamespace("default")
+		rt.SetAnnotations(enabled)
+		rt.Object["spec"] = spec
+		return rt
+	}
+
+	tests := []struct {
+		name        string
+		obj         *unstructured.Unstructured
+		expected    []models.MonitorSource
+		expectedErr string
+	}{
+		{
+			name: "ingressroute with host match rule",
+			obj: newIngressRoute(map[string]interface{}{
+				"routes": []interface{}{
+					map[string]interface{}{"match": "Host(`foo.example.com`) && PathPrefix(`/api`)"},
+				},
+				"tls": map[string]interface{}{},
+			}),
+			expected: []models.MonitorSource{{
+				Kind:        "IngressRoute",
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: enabled,
+				URL:         "https://foo.example.com/api",
+			}},
+		},
+		{
+			name:        "ingressroute without host fails validation",
+			obj:         newIngressRoute(map[string]interface{}{}),
+			expectedErr: "ingressroute does not have a Host match rule",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewIngressRouteReconciler(fakeclient.NewClientBuilder().Build(), events.NewFakeRecorder(100), &fake.Service{}, &config.Options{})
+
+			sources, err := r.buildSources(context.Background(), test.obj)
+			if test.expectedErr != "" {
+				var invalidErr *invalidSourceError
+				require.ErrorAs(t, err, &invalidErr)
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sources)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitorresource"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReasonMonitorSynced is the reason of the Synced condition of a Monitor
+// resource whose monitor is in sync with its spec.
+const ReasonMonitorSynced = "MonitorSynced"
+
+// MonitorReconciler reconciles Monitor resources to their desired
+// monitoring state. The state of the monitor is reported in the status of
+// the resource.
+type MonitorReconciler struct {
+	client.Client
+
+	monitorService monitor.Service
+	monitors       *monitorHandler
+}
+
+// NewMonitorReconciler creates a new *MonitorReconciler. Events about the
+// monitor of a Monitor resource are recorded on the resource using recorder.
+// The cleanup finalizer is always added to Monitor resources outside of dry
+// run mode, as they exist for the sole purpose of managing their monitor.
+func NewMonitorReconciler(client client.Client, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *MonitorReconciler {
+	return &MonitorReconciler{
+		Client:         client,
+		monitorService: monitorService,
+		monitors: &monitorHandler{
+			client:    client,
+			recorder:  recorder,
+			service:   monitorService,
+			kind:      monitorresource.Kind,
+			finalizer: true,
+			dryRun:    options.DryRun,
+		},
+	}
+}
+
+// Reconcile creates, updates or deletes monitors whenever a Monitor
+// resource changes. It implements reconcile.Reconciler.
+func (r *MonitorReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	m := &v1alpha1.Monitor{}
+
+	err := r.Get(ctx, req.NamespacedName, m)
+	if apierrors.IsNotFound(err) {
+		source := models.MonitorSource{
+			Kind:      monitorresource.Kind,
+			Name:      req.Name,
+			Namespace: req.Namespace,
+		}
+
+		_, err = r.monitorService.DeleteMonitor(source)
+	} else if err == nil {
+		if !m.DeletionTimestamp.IsZero() {
+			err = r.handleDelete(ctx, m)
+		} else {
+			err = r.handleCreateOrUpdate(ctx, m)
+		}
+	}
+
+	return resultForError(req, err)
+}
+
+func (r *MonitorReconciler) handleCreateOrUpdate(ctx context.Context, m *v1alpha1.Monitor) error {
+	err := monitorresource.Validate(m)
+	if err != nil {
+		r.monitors.recorder.Eventf(m, nil, corev1.EventTypeWarning, ReasonValidationFailed, "Validate", "Not monitoring %s: %v", monitorresource.Kind, err)
+
+		return r.updateStatus(ctx, m, func(status *v1alpha1.MonitorStatus) {
+			setSyncedCondition(status, m.Generation, metav1.ConditionFalse, ReasonValidationFailed, err.Error())
+		})
+	}
+
+	err = r.monitors.addFinalizer(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.monitorService.EnsureMonitor(monitorresource.NewMonitorSource(m))
+	if result != nil {
+		r.monitors.recordResult(m, result)
+	}
+
+	if err != nil {
+		r.monitors.recorder.Eventf(m, nil, corev1.EventTypeWarning, failureReason(err), "EnsureMonitor", "%s", failureMessage("ensure monitor", err))
+
+		statusErr := r.updateStatus(ctx, m, func(status *v1alpha1.MonitorStatus) {
+			setSyncedCondition(status, m.Generation, metav1.ConditionFalse, ReasonProviderError, err.Error())
+		})
+		if statusErr != nil {
+			log.Error(statusErr, "failed to record monitor error", "kind", monitorresource.Kind, "namespace", m.Namespace, "name", m.Name)
+		}
+
+		return err
+	}
+
+	return r.updateStatus(ctx, m, func(status *v1alpha1.MonitorStatus) {
+		if result != nil && result.Monitor != nil {
+			// Not all providers report IDs, so a previously known ID is
+			// kept.
+			if result.Monitor.ID != "" {
+				status.ID = result.Monitor.ID
+			}
+
+			status.Name = result.Monitor.Name
+		}
+
+		status.LastSyncTime = &metav1.Time{Time: time.Now()}
+		setSyncedCondition(status, m.Generation, metav1.ConditionTrue, ReasonMonitorSynced, "Monitor is in sync")
+	})
+}
+
+// handleDelete deletes the monitor of a Monitor resource that is being
+// deleted and removes the cleanup finalizer afterwards.
+func (r *MonitorReconciler) handleDelete(ctx context.Context, m *v1alpha1.Monitor) error {
+	if !controllerutil.ContainsFinalizer(m, CleanupFinalizer) {
+		return nil
+	}
+
+	err := r.monitors.deleteMonitor(m, monitorresource.DeleteSource(m))
+	if err != nil {
+		return err
+	}
+
+	return r.monitors.removeFinalizer(ctx, m)
+}
+
+// updateStatus applies mutate to the status of m, sets the observed
+// generation and patches the status subresource. In dry run mode, the
+// status is left untouched.
+func (r *MonitorReconciler) updateStatus(ctx context.Context, m *v1alpha1.Monitor, mutate func(*v1alpha1.MonitorStatus)) error {
+	if r.monitors.dryRun {
+		return nil
+	}
+
+	original := m.DeepCopy()
+
+	mutate(&m.Status)
+	m.Status.ObservedGeneration = m.Generation
+
+	return r.Status().Patch(ctx, m, client.MergeFrom(original))
+}
+
+func setSyncedCondition(status *v1alpha1.MonitorStatus, generation int64, conditionStatus metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1alpha1.MonitorConditionSynced,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
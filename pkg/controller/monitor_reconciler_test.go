@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newMonitorSchemeClient(objects ...client.Object) client.Client {
+	scheme := fakeclient.NewClientBuilder().Build().Scheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	return fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&v1alpha1.Monitor{}).
+		Build()
+}
+
+func TestMonitorReconciler_Reconcile(t *testing.T) {
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+
+	newMonitor := func(url string) *v1alpha1.Monitor {
+		return &v1alpha1.Monitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "foo",
+				Namespace:  "default",
+				Generation: 2,
+			},
+			Spec: v1alpha1.MonitorSpec{
+				URL:      url,
+				Site24x7: &v1alpha1.Site24x7Settings{CheckFrequency: "5"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		objects     []client.Object
+		options     config.Options
+		setup       func(*fake.Service)
+		validate    func(*testing.T, client.Client)
+		expectError bool
+	}{
+		{
+			name: "it deletes the monitor if the resource was deleted",
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", matchMonitorSource("foo", "default")).Return(nil, nil)
+			},
+		},
+		{
+			name:    "it ensures the monitor and reports its state in the status",
+			objects: []client.Object{newMonitor("https://foo.example.com/health")},
+			setup: func(s *fake.Service) {
+				s.On("EnsureMonitor", models.MonitorSource{
+					Kind:      "Monitor",
+					Name:      "foo",
+					Namespace: "default",
+					Annotations: map[string]string{
+						config.AnnotationSite24x7CheckFrequency: "5",
+					},
+					URL: "https://foo.example.com/health",
+				}).Return(&monitor.Result{
+					Monitor:   &models.Monitor{ID: "123", Name: "default-foo"},
+					Operation: monitor.OperationCreated,
+				}, nil)
+			},
+			validate: func(t *testing.T, c client.Client) {
+				m := getMonitor(t, c)
+
+				assert.True(t, controllerutil.ContainsFinalizer(m, CleanupFinalizer))
+				assert.Equal(t, "123", m.Status.ID)
+				assert.Equal(t, "default-foo", m.Status.Name)
+				assert.Equal(t, int64(2), m.Status.ObservedGeneration)
+				assert.NotNil(t, m.Status.LastSyncTime)
+				assert.True(t, meta.IsStatusConditionTrue(m.Status.Conditions, v1alpha1.MonitorConditionSynced))
+			},
+		},
+		{
+			name:    "it neither adds the finalizer nor reports the status in dry run mode",
+			objects: []client.Object{newMonitor("https://foo.example.com/health")},
+			options: config.Options{DryRun: true},
+			setup: func(s *fake.Service) {
+				s.On("EnsureMonitor", matchMonitorSource("foo", "default")).Return(&monitor.Result{
+					Monitor:   &models.Monitor{Name: "default-foo"},
+					Operation: monitor.OperationCreated,
+				}, nil)
+			},
+			validate: func(t *testing.T, c client.Client) {
+				m := getMonitor(t, c)
+
+				assert.Empty(t, m.Finalizers)
+				assert.Equal(t, v1alpha1.MonitorStatus{}, m.Status)
+			},
+		},
+		{
+			name:    "provider errors are reported in the status",
+			objects: []client.Object{newMonitor("https://foo.example.com")},
+			setup: func(s *fake.Service) {
+				s.On("EnsureMonitor", matchMonitorSource("foo", "default")).Return(nil, errors.New("whoops"))
+			},
+			validate: func(t *testing.T, c client.Client) {
+				condition := meta.FindStatusCondition(getMonitor(t, c).Status.Conditions, v1alpha1.MonitorConditionSynced)
+				require.NotNil(t, condition)
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				assert.Equal(t, ReasonProviderError, condition.Reason)
+				assert.Equal(t, "whoops", condition.Message)
+			},
+			expectError: true,
+		},
+		{
+			name:    "invalid monitors are reported in the status",
+			objects: []client.Object{newMonitor("foo.example.com")},
+			validate: func(t *testing.T, c client.Client) {
+				m := getMonitor(t, c)
+
+				condition := meta.FindStatusCondition(m.Status.Conditions, v1alpha1.MonitorConditionSynced)
+				require.NotNil(t, condition)
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				assert.Equal(t, ReasonValidationFailed, condition.Reason)
+				assert.False(t, controllerutil.ContainsFinalizer(m, CleanupFinalizer))
+			},
+		},
+		{
+			name: "it deletes the monitor and removes the finalizer if the resource is being deleted",
+			objects: []client.Object{
+				func() client.Object {
+					m := newMonitor("https://foo.example.com")
+					m.Finalizers = []string{CleanupFinalizer, "other"}
+					m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+					return m
+				}(),
+			},
+			setup: func(s *fake.Service) {
+				s.On("DeleteMonitor", matchMonitorSource("foo", "default")).Return(&monitor.Result{
+					Monitor:   &models.Monitor{Name: "default-foo"},
+					Operation: monitor.OperationDeleted,
+				}, nil)
+			},
+			validate: func(t *testing.T, c client.Client) {
+				assert.Equal(t, []string{"other"}, getMonitor(t, c).Finalizers)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl := newMonitorSchemeClient(test.objects...)
+
+			svc := &fake.Service{}
+
+			if test.setup != nil {
+				test.setup(svc)
+			}
+
+			r := NewMonitorReconciler(cl, events.NewFakeRecorder(100), svc, &test.options)
+
+			_, err := r.Reconcile(context.Background(), req)
+			if test.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			svc.AssertExpectations(t)
+
+			if test.validate != nil {
+				test.validate(t, cl)
+			}
+		})
+	}
+}
+
+func getMonitor(t *testing.T, c client.Client) *v1alpha1.Monitor {
+	m := &v1alpha1.Monitor{}
+
+	err := c.Get(context.Background(), types.NamespacedName{Name: "foo", Namespace: "default"}, m)
+	require.NoError(t, err)
+
+	return m
+}
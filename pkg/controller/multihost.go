@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileMonitoredHosts deletes the monitors of hosts that were recorded
+// in the ingress-monitor.bonial.com/monitored-hosts annotation of obj but are
+// not part of sources anymore. Afterwards, sources are recorded in the
+// annotation. If sources is empty, the annotation is removed. If obj was not
+// in multi host mode before, the monitor that was created for obj in single
+// host mode is deleted as well.
+func (h *monitorHandler) reconcileMonitoredHosts(ctx context.Context, obj client.Object, sources []models.MonitorSource) error {
+	annotations := obj.GetAnnotations()
+	_, recorded := annotations[config.AnnotationMonitoredHosts]
+
+	keys := sets.New[string]()
+	for _, source := range sources {
+		keys.Insert(monitoredHostKey(source))
+	}
+
+	if !recorded && len(sources) > 0 {
+		err := h.deleteMonitor(obj, models.MonitorSource{
+			Kind:        h.kind,
+			Name:        obj.GetName(),
+			Namespace:   obj.GetNamespace(),
+			Annotations: annotations,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, source := range recordedSources(h.kind, obj) {
+		if keys.Has(monitoredHostKey(source)) {
+			continue
+		}
+
+		err := h.deleteMonitor(obj, source)
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.patchAnnotations(ctx, obj, func(annotations map[string]string) {
+		setOrDelete(annotations, config.AnnotationMonitoredHosts, strings.Join(sets.List(keys), ","))
+	})
+}
+
+// recordedSources returns the sources that are recorded in the
+// ingress-monitor.bonial.com/monitored-hosts annotation of obj. The returned
+// sources carry all fields required for deleting their monitors.
+func recordedSources(kind string, obj client.Object) []models.MonitorSource {
+	value := obj.GetAnnotations()[config.AnnotationMonitoredHosts]
+	if value == "" {
+		return nil
+	}
+
+	keys := strings.Split(value, ",")
+	sort.Strings(keys)
+
+	sources := make([]models.MonitorSource, 0, len(keys))
+
+	for _, key := range keys {
+		host, path := key, ""
+		if i := strings.Index(key, "/"); i >= 0 {
+			host, path = key[:i], key[i:]
+		}
+
+		sources = append(sources, models.MonitorSource{
+			Kind:        kind,
+			Name:        obj.GetName(),
+			Namespace:   obj.GetNamespace(),
+			Annotations: obj.GetAnnotations(),
+			Host:        host,
+			Path:        path,
+		})
+	}
+
+	return sources
+}
+
+func monitoredHostKey(source models.MonitorSource) string {
+	return source.Host + source.Path
+}
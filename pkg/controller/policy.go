@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/policy"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// newPolicyResolver creates a *policy.Resolver if monitor policies are
+// enabled, nil otherwise.
+func newPolicyResolver(client client.Reader, options *config.Options) *policy.Resolver {
+	if !options.EnableMonitorPolicy {
+		return nil
+	}
+
+	return policy.NewResolver(client)
+}
+
+// mapPolicyToRequests maps a policy to reconcile requests for all objects
+// of list that it may apply to. MonitorPolicies apply to the objects in
+// their namespace, ClusterMonitorPolicies to the objects in all namespaces.
+func mapPolicyToRequests(ctx context.Context, c client.Reader, obj client.Object, list client.ObjectList) []reconcile.Request {
+	var opts []client.ListOption
+	if _, ok := obj.(*v1alpha1.MonitorPolicy); ok {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+
+	err := c.List(ctx, list, opts...)
+	if err != nil {
+		log.Error(err, "failed to list objects for monitor policy", "policy", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	err = meta.EachListItem(list, func(item runtime.Object) error {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(item.(client.Object))})
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "failed to map monitor policy", "policy", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	return requests
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIngressReconciler_MapPolicyToIngresses(t *testing.T) {
+	c := fakeclient.NewClientBuilder().
+		WithObjects(
+			&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team"}},
+			&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "other"}},
+		).
+		Build()
+
+	r := &IngressReconciler{Client: c}
+
+	tests := []struct {
+		name     string
+		policy   client.Object
+		expected []reconcile.Request
+	}{
+		{
+			name:   "monitor policy maps to ingresses in its namespace",
+			policy: &v1alpha1.MonitorPolicy{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "team"}},
+			expected: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "team"}},
+			},
+		},
+		{
+			name:   "cluster monitor policy maps to ingresses in all namespaces",
+			policy: &v1alpha1.ClusterMonitorPolicy{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			expected: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "bar", Namespace: "other"}},
+				{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "team"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expected, r.MapPolicyToIngresses(context.Background(), test.policy))
+		})
+	}
+}
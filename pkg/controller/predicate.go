@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// IgnoreStateAnnotationChanges returns a predicate that filters out update
+// events which only change the state annotations managed by the controller.
+// Without it, recording the monitor state on a resource would immediately
+// trigger another reconciliation of the same resource.
+func IgnoreStateAnnotationChanges() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+
+			return !equality.Semantic.DeepEqual(withoutState(e.ObjectOld), withoutState(e.ObjectNew))
+		},
+	}
+}
+
+// withoutState returns a copy of obj without the state annotations and the
+// metadata fields that change with every write. Changes to these do not
+// trigger a reconciliation.
+func withoutState(obj client.Object) client.Object {
+	obj = obj.DeepCopyObject().(client.Object)
+
+	annotations := obj.GetAnnotations()
+	for _, key := range config.StateAnnotations {
+		delete(annotations, key)
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	obj.SetAnnotations(annotations)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	return obj
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestIgnoreStateAnnotationChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      map[string]string
+		new      map[string]string
+		expected bool
+	}{
+		{
+			name:     "ignores updates of state annotations",
+			old:      map[string]string{config.AnnotationEnabled: "true"},
+			new:      map[string]string{config.AnnotationEnabled: "true", config.AnnotationLastSync: "2026-01-01T00:00:00Z", config.AnnotationMonitorID: "123"},
+			expected: false,
+		},
+		{
+			name:     "ignores removal of state annotations",
+			old:      map[string]string{config.AnnotationLastError: "whoops", config.AnnotationMonitoredHosts: "foo.example.com"},
+			new:      nil,
+			expected: false,
+		},
+		{
+			name:     "does not ignore updates of other annotations",
+			old:      map[string]string{config.AnnotationEnabled: "true"},
+			new:      map[string]string{config.AnnotationEnabled: "false", config.AnnotationLastSync: "2026-01-01T00:00:00Z"},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldIng := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "1", Annotations: test.old}}
+			newIng := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "2", Annotations: test.new}}
+
+			p := IgnoreStateAnnotationChanges()
+
+			assert.Equal(t, test.expected, p.Update(event.UpdateEvent{ObjectOld: oldIng, ObjectNew: newIng}))
+		})
+	}
+}
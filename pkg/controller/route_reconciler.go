@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/route"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RouteReconciler reconciles OpenShift Routes to their desired monitoring
+// state.
+type RouteReconciler struct {
+	client.Client
+
+	monitors *monitorHandler
+}
+
+// NewRouteReconciler creates a new *RouteReconciler. Events about the
+// monitors of a route are recorded on the route using recorder.
+func NewRouteReconciler(client client.Client, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *RouteReconciler {
+	return &RouteReconciler{
+		Client:   client,
+		monitors: newMonitorHandler(client, recorder, monitorService, route.Kind, options),
+	}
+}
+
+// Reconcile creates, updates or deletes monitors whenever an OpenShift
+// Route changes. It implements reconcile.Reconciler.
+func (r *RouteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcileSource(ctx, r.monitors, req, route.New(), r.buildSources)
+}
+
+// buildSources builds the monitor source of an OpenShift Route. It
+// implements sourceBuilder.
+func (r *RouteReconciler) buildSources(_ context.Context, rt *unstructured.Unstructured) ([]models.MonitorSource, error) {
+	err := route.Validate(rt)
+	if err != nil {
+		metrics.RouteValidationErrorsTotal.WithLabelValues(rt.GetNamespace(), rt.GetName()).Inc()
+		return nil, invalidSource(err)
+	}
+
+	source, err := route.NewMonitorSource(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.MonitorSource{source}, nil
+}
+
+// MapPolicyToRoutes maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all OpenShift Routes it may apply to.
+func (r *RouteReconciler) MapPolicyToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, route.NewList())
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/route"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRouteReconciler_buildSources(t *testing.T) {
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	newRoute := func(spec map[string]interface{}) *unstructured.Unstructured {
+		rt := route.New()
+		rt.SetName("foo")
+		rt.SetNamespace("default")
+		rt.SetAnnotations(enabled)
+		rt.Object["spec"] = spec
+		return rt
+	}
+
+	tests := []struct {
+		name        string
+		obj         *unstructured.Unstructured
+		expected    []models.MonitorSource
+		expectedErr string
+	}{
+		{
+			name: "route with edge tls termination",
+			obj: newRoute(map[string]interface{}{
+				"host": "foo.example.com",
+				"path": "/api",
+				"tls":  map[string]interface{}{"termination": "edge"},
+			}),
+			expected: []models.MonitorSource{{
+				Kind:        "Route",
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: enabled,
+				URL:         "https://foo.example.com/api",
+			}},
+		},
+		{
+			name:        "route without host fails validation",
+			obj:         newRoute(map[string]interface{}{}),
+			expectedErr: "route does not have a host",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewRouteReconciler(fakeclient.NewClientBuilder().Build(), events.NewFakeRecorder(100), &fake.Service{}, &config.Options{})
+
+			sources, err := r.buildSources(context.Background(), test.obj)
+			if test.expectedErr != "" {
+				var invalidErr *invalidSourceError
+				require.ErrorAs(t, err, &invalidErr)
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sources)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/service"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServiceReconciler reconciles Services of type LoadBalancer to their
+// desired monitoring state.
+type ServiceReconciler struct {
+	client.Client
+
+	monitors *monitorHandler
+}
+
+// NewServiceReconciler creates a new *ServiceReconciler. Events about the
+// monitors of a service are recorded on the service using recorder.
+func NewServiceReconciler(client client.Client, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *ServiceReconciler {
+	return &ServiceReconciler{
+		Client:   client,
+		monitors: newMonitorHandler(client, recorder, monitorService, "Service", options),
+	}
+}
+
+// Reconcile creates, updates or deletes monitors whenever a Service changes.
+// It implements reconcile.Reconciler.
+func (r *ServiceReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcileSource(ctx, r.monitors, req, &corev1.Service{}, r.buildSources)
+}
+
+// buildSources builds the monitor source of a Service. It implements
+// sourceBuilder.
+func (r *ServiceReconciler) buildSources(_ context.Context, svc *corev1.Service) ([]models.MonitorSource, error) {
+	err := service.Validate(svc)
+	if err != nil {
+		metrics.ServiceValidationErrorsTotal.WithLabelValues(svc.Namespace, svc.Name).Inc()
+		return nil, invalidSource(err)
+	}
+
+	source, err := service.NewMonitorSource(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.MonitorSource{source}, nil
+}
+
+// MapPolicyToServices maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all Services it may apply to.
+func (r *ServiceReconciler) MapPolicyToServices(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, &corev1.ServiceList{})
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestServiceReconciler_buildSources(t *testing.T) {
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	newService := func(ingress ...corev1.LoadBalancerIngress) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: enabled,
+			},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 443}},
+			},
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		obj         *corev1.Service
+		expected    []models.MonitorSource
+		expectedErr string
+	}{
+		{
+			name: "service with load balancer hostname",
+			obj:  newService(corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expected: []models.MonitorSource{{
+				Kind:        "Service",
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: enabled,
+				URL:         "https://lb.example.com",
+			}},
+		},
+		{
+			name:        "service without load balancer fails validation",
+			obj:         newService(),
+			expectedErr: "service load balancer does not have a hostname or IP yet",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewServiceReconciler(fakeclient.NewClientBuilder().Build(), events.NewFakeRecorder(100), &fake.Service{}, &config.Options{})
+
+			sources, err := r.buildSources(context.Background(), test.obj)
+			if test.expectedErr != "" {
+				var invalidErr *invalidSourceError
+				require.ErrorAs(t, err, &invalidErr)
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sources)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recordMonitorState records the names and IDs of monitors in the state
+// annotations of obj and removes the last-error annotation. Monitors that
+// are managed by multiple providers share their name, so each name is only
+// recorded once. The last-sync annotation is only updated if a monitor was
+// changed or the state annotations changed, so that resyncs of resources
+// whose monitors are up to date do not patch them.
+func (h *monitorHandler) recordMonitorState(ctx context.Context, obj client.Object, monitors []*models.Monitor, changed bool) error {
+	names := make([]string, 0, len(monitors))
+	ids := make([]string, 0, len(monitors))
+
+	for _, monitor := range monitors {
+		if !slices.Contains(names, monitor.Name) {
+			names = append(names, monitor.Name)
+		}
+
+		if monitor.ID != "" {
+			ids = append(ids, monitor.ID)
+		}
+	}
+
+	return h.patchAnnotations(ctx, obj, func(annotations map[string]string) {
+		previous := maps.Clone(annotations)
+
+		setOrDelete(annotations, config.AnnotationMonitorName, strings.Join(names, ","))
+		setOrDelete(annotations, config.AnnotationMonitorID, strings.Join(ids, ","))
+		delete(annotations, config.AnnotationLastError)
+
+		if changed || !maps.Equal(previous, annotations) || annotations[config.AnnotationLastSync] == "" {
+			annotations[config.AnnotationLastSync] = time.Now().UTC().Format(time.RFC3339)
+		}
+	})
+}
+
+// recordMonitorError records err in the last-error annotation of obj. The
+// other state annotations are left untouched, as the monitors may still
+// exist.
+func (h *monitorHandler) recordMonitorError(ctx context.Context, obj client.Object, err error) error {
+	return h.patchAnnotations(ctx, obj, func(annotations map[string]string) {
+		annotations[config.AnnotationLastError] = err.Error()
+	})
+}
+
+// clearMonitorState removes the state annotations from obj. It is used
+// after the monitors of obj were deleted.
+func (h *monitorHandler) clearMonitorState(ctx context.Context, obj client.Object) error {
+	return h.patchAnnotations(ctx, obj, func(annotations map[string]string) {
+		delete(annotations, config.AnnotationMonitorID)
+		delete(annotations, config.AnnotationMonitorName)
+		delete(annotations, config.AnnotationLastSync)
+		delete(annotations, config.AnnotationLastError)
+	})
+}
+
+// patchAnnotations applies mutate to the annotations of obj and patches obj
+// if the annotations were changed. In dry run mode, obj is left untouched.
+func (h *monitorHandler) patchAnnotations(ctx context.Context, obj client.Object, mutate func(map[string]string)) error {
+	if h.dryRun {
+		return nil
+	}
+
+	original := obj.DeepCopyObject().(client.Object)
+
+	annotations := maps.Clone(obj.GetAnnotations())
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	mutate(annotations)
+
+	if maps.Equal(original.GetAnnotations(), annotations) {
+		return nil
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	obj.SetAnnotations(annotations)
+
+	return h.client.Patch(ctx, obj, client.MergeFrom(original))
+}
+
+func setOrDelete(annotations map[string]string, key, value string) {
+	if value == "" {
+		delete(annotations, key)
+	} else {
+		annotations[key] = value
+	}
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/httproute"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// TLSRouteReconciler reconciles TLSRoute resources to their desired
+// monitoring state.
+type TLSRouteReconciler struct {
+	client.Client
+
+	gateways client.Reader
+	monitors *monitorHandler
+}
+
+// NewTLSRouteReconciler creates a new *TLSRouteReconciler. Events about
+// the monitors of a route are recorded on the route using recorder. Parent
+// Gateways outside of the watched namespaces are read using apiReader.
+func NewTLSRouteReconciler(client client.Client, apiReader client.Reader, recorder events.EventRecorder, monitorService monitor.Service, options *config.Options) *TLSRouteReconciler {
+	return &TLSRouteReconciler{
+		Client:   client,
+		gateways: httproute.NewGatewayReader(client, apiReader, options.WatchNamespaces()),
+		monitors: newMonitorHandler(client, recorder, monitorService, httproute.TLSRouteKind, options),
+	}
+}
+
+// Reconcile creates, updates or deletes monitors whenever a TLSRoute
+// changes. It implements reconcile.Reconciler.
+func (r *TLSRouteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcileSource(ctx, r.monitors, req, &gatewayv1alpha2.TLSRoute{}, r.buildSources)
+}
+
+// buildSources builds the monitor source of a TLSRoute from the listeners
+// of its parent Gateways. It implements sourceBuilder.
+func (r *TLSRouteReconciler) buildSources(ctx context.Context, route *gatewayv1alpha2.TLSRoute) ([]models.MonitorSource, error) {
+	listeners, err := httproute.TLSRouteParentListeners(ctx, r.gateways, route)
+	if err != nil {
+		return nil, err
+	}
+
+	err = httproute.ValidateTLSRoute(route)
+	if err != nil {
+		metrics.TLSRouteValidationErrorsTotal.WithLabelValues(route.Namespace, route.Name).Inc()
+		return nil, invalidSource(err)
+	}
+
+	return []models.MonitorSource{httproute.NewTLSRouteMonitorSource(route, listeners)}, nil
+}
+
+// MapGatewayToTLSRoutes maps a Gateway to reconcile requests for all
+// TLSRoutes that reference it in their ParentRefs. It requires the
+// GatewayIndexField index to be registered for TLSRoutes.
+func (r *TLSRouteReconciler) MapGatewayToTLSRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	routes := &gatewayv1alpha2.TLSRouteList{}
+
+	err := r.List(ctx, routes, client.MatchingFields{GatewayIndexField: client.ObjectKeyFromObject(obj).String()})
+	if err != nil {
+		log.Error(err, "failed to list tlsroutes for gateway", "gateway", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(routes.Items))
+
+	for _, route := range routes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&route)})
+	}
+
+	return requests
+}
+
+// MapPolicyToTLSRoutes maps a MonitorPolicy or ClusterMonitorPolicy to
+// reconcile requests for all TLSRoutes it may apply to.
+func (r *TLSRouteReconciler) MapPolicyToTLSRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return mapPolicyToRequests(ctx, r.Client, obj, &gatewayv1alpha2.TLSRouteList{})
+}
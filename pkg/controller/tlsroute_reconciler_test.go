@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestTLSRouteReconciler_buildSources(t *testing.T) {
+	enabled := map[string]string{config.AnnotationEnabled: "true"}
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "default"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				{Name: "tls", Protocol: gatewayv1.TLSProtocolType, Port: 6443},
+			},
+		},
+	}
+
+	newRoute := func(hostnames ...gatewayv1alpha2.Hostname) *gatewayv1alpha2.TLSRoute {
+		return &gatewayv1alpha2.TLSRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: enabled},
+			Spec: gatewayv1alpha2.TLSRouteSpec{
+				CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+					ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "public"}},
+				},
+				Hostnames: hostnames,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		obj         *gatewayv1alpha2.TLSRoute
+		expected    []models.MonitorSource
+		expectedErr string
+	}{
+		{
+			name: "tlsroute uses the port of the tls listener",
+			obj:  newRoute("db.example.com"),
+			expected: []models.MonitorSource{{
+				Kind:        "TLSRoute",
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: enabled,
+				Type:        models.MonitorTypeTLS,
+				URL:         "https://db.example.com:6443",
+			}},
+		},
+		{
+			name:        "tlsroute with wildcard hostname fails validation",
+			obj:         newRoute("*.example.com"),
+			expectedErr: `tlsroute hostname "*.example.com" contains wildcards`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewTLSRouteReconciler(newHTTPRouteSchemeClient(gateway), nil, events.NewFakeRecorder(100), &fake.Service{}, &config.Options{})
+
+			sources, err := r.buildSources(context.Background(), test.obj)
+			if test.expectedErr != "" {
+				var invalidErr *invalidSourceError
+				require.ErrorAs(t, err, &invalidErr)
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sources)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package httproute
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var log = logf.Log.WithName("httproute")
+
+const (
+	defaultHTTPPort  = 80
+	defaultHTTPSPort = 443
+	defaultTLSPort   = 443
+)
+
+// ParentGateways returns the namespaced names of the Gateways referenced in
+// the ParentRefs of route. ParentRefs of other kinds than Gateway are
+// ignored.
+func ParentGateways(route *gatewayv1.HTTPRoute) []types.NamespacedName {
+	return parentGateways(route.Namespace, route.Spec.ParentRefs)
+}
+
+func parentGateways(namespace string, refs []gatewayv1.ParentReference) []types.NamespacedName {
+	var gateways []types.NamespacedName
+
+	for _, ref := range refs {
+		if !isGatewayRef(ref) {
+			continue
+		}
+
+		gateways = append(gateways, gatewayName(namespace, ref))
+	}
+
+	return gateways
+}
+
+// ParentListeners looks up the Gateways referenced in the ParentRefs of route
+// and returns the HTTP and HTTPS listeners the route attaches to. If a
+// ParentRef specifies a sectionName or port, only matching listeners are
+// returned. Gateways that do not exist or that the controller is not
+// allowed to read are ignored, so that the route falls back to the default
+// scheme. Use NewGatewayReader to read Gateways outside of the watched
+// namespaces (see --namespace).
+func ParentListeners(ctx context.Context, c client.Reader, route *gatewayv1.HTTPRoute) ([]gatewayv1.Listener, error) {
+	return parentListeners(ctx, c, route.Namespace, route.Spec.ParentRefs, gatewayv1.HTTPProtocolType, gatewayv1.HTTPSProtocolType)
+}
+
+// parentListeners returns the listeners with one of protocols that the
+// ParentRefs refs of a route in namespace attach to.
+func parentListeners(ctx context.Context, c client.Reader, namespace string, refs []gatewayv1.ParentReference, protocols ...gatewayv1.ProtocolType) ([]gatewayv1.Listener, error) {
+	var listeners []gatewayv1.Listener
+
+	for _, ref := range refs {
+		if !isGatewayRef(ref) {
+			continue
+		}
+
+		gateway := &gatewayv1.Gateway{}
+
+		name := gatewayName(namespace, ref)
+
+		err := c.Get(ctx, name, gateway)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if apierrors.IsForbidden(err) {
+			log.Info("not allowed to read parent gateway, falling back to default scheme",
+				"gateway", name.String(), "error", err.Error())
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, listener := range gateway.Spec.Listeners {
+			if !slices.Contains(protocols, listener.Protocol) {
+				continue
+			}
+
+			if ref.SectionName != nil && *ref.SectionName != listener.Name {
+				continue
+			}
+
+			if ref.Port != nil && *ref.Port != listener.Port {
+				continue
+			}
+
+			listeners = append(listeners, listener)
+		}
+	}
+
+	return listeners, nil
+}
+
+// gatewayReader reads Gateways in the watched namespaces from a cache and
+// Gateways in all other namespaces directly from the API server.
+type gatewayReader struct {
+	cached     client.Reader
+	uncached   client.Reader
+	namespaces sets.Set[string]
+}
+
+// NewGatewayReader creates a client.Reader for the Gateways referenced by
+// routes. A cache that is restricted to the watched namespaces cannot serve
+// Gateways in other namespaces, e.g. a shared infrastructure namespace. These
+// are read using uncached instead, which is usually the API reader of the
+// manager. If namespaces is empty, all namespaces are watched and cached is
+// returned as is.
+func NewGatewayReader(cached, uncached client.Reader, namespaces []string) client.Reader {
+	if len(namespaces) == 0 {
+		return cached
+	}
+
+	return &gatewayReader{
+		cached:     cached,
+		uncached:   uncached,
+		namespaces: sets.New(namespaces...),
+	}
+}
+
+// Get implements client.Reader.
+func (r *gatewayReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if r.namespaces.Has(key.Namespace) {
+		return r.cached.Get(ctx, key, obj, opts...)
+	}
+
+	return r.uncached.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Reader. Lists are always served by the cache.
+func (r *gatewayReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return r.cached.List(ctx, list, opts...)
+}
+
+func isGatewayRef(ref gatewayv1.ParentReference) bool {
+	if ref.Group != nil && *ref.Group != gatewayv1.Group(gatewayv1.GroupVersion.Group) {
+		return false
+	}
+
+	return ref.Kind == nil || *ref.Kind == "Gateway"
+}
+
+func gatewayName(namespace string, ref gatewayv1.ParentReference) types.NamespacedName {
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: string(ref.Name)}
+}
+
+// selectListener returns the first listener with given protocol that accepts
+// hostname. Returns nil if there is none.
+func selectListener(listeners []gatewayv1.Listener, hostname string, protocol gatewayv1.ProtocolType) *gatewayv1.Listener {
+	for i, listener := range listeners {
+		if listener.Protocol == protocol && hostnameMatches(listener.Hostname, hostname) {
+			return &listeners[i]
+		}
+	}
+
+	return nil
+}
+
+// hostnameMatches returns true if a listener with listenerHostname accepts
+// routes for hostname. A listener without hostname accepts all hostnames.
+func hostnameMatches(listenerHostname *gatewayv1.Hostname, hostname string) bool {
+	if listenerHostname == nil || *listenerHostname == "" {
+		return true
+	}
+
+	pattern := string(*listenerHostname)
+
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(hostname, suffix) && len(hostname) > len(suffix)
+	}
+
+	return pattern == hostname
+}
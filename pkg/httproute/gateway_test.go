@@ -0,0 +1,184 @@
+package httproute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestParentListeners(t *testing.T) {
+	sectionName := gatewayv1.SectionName("https")
+	port := gatewayv1.PortNumber(8080)
+	namespace := gatewayv1.Namespace("infra")
+	kind := gatewayv1.Kind("Service")
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "infra"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 8080},
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				{Name: "tcp", Protocol: gatewayv1.TCPProtocolType, Port: 5432},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		parentRefs []gatewayv1.ParentReference
+		expected   []gatewayv1.Listener
+	}{
+		{
+			name: "all http and https listeners of the gateway",
+			parentRefs: []gatewayv1.ParentReference{
+				{Name: "public", Namespace: &namespace},
+			},
+			expected: gateway.Spec.Listeners[:2],
+		},
+		{
+			name: "listener selected by section name",
+			parentRefs: []gatewayv1.ParentReference{
+				{Name: "public", Namespace: &namespace, SectionName: &sectionName},
+			},
+			expected: gateway.Spec.Listeners[1:2],
+		},
+		{
+			name: "listener selected by port",
+			parentRefs: []gatewayv1.ParentReference{
+				{Name: "public", Namespace: &namespace, Port: &port},
+			},
+			expected: gateway.Spec.Listeners[:1],
+		},
+		{
+			name: "ignores missing gateways and other parent kinds",
+			parentRefs: []gatewayv1.ParentReference{
+				{Name: "public"},
+				{Name: "public", Namespace: &namespace, Kind: &kind},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-route", Namespace: "default"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: test.parentRefs},
+				},
+			}
+
+			listeners, err := ParentListeners(context.Background(), newTestClient(gateway), route)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, listeners)
+		})
+	}
+}
+
+// namespacedReader mimics a cache that is restricted to namespace.
+type namespacedReader struct {
+	client.Reader
+
+	namespace string
+}
+
+func (r *namespacedReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if key.Namespace != r.namespace {
+		return fmt.Errorf("unable to get: %v because of unknown namespace for the cache", key)
+	}
+
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+// forbiddenReader mimics an API reader without permissions.
+type forbiddenReader struct {
+	client.Reader
+}
+
+func (r *forbiddenReader) Get(_ context.Context, key client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	return apierrors.NewForbidden(schema.GroupResource{Group: gatewayv1.GroupVersion.Group, Resource: "gateways"}, key.Name, errors.New("no access"))
+}
+
+func TestParentListeners_UnwatchedNamespace(t *testing.T) {
+	namespace := gatewayv1.Namespace("infra")
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "public", Namespace: "infra"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+			},
+		},
+	}
+
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-route", Namespace: "default"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{
+					{Name: "public", Namespace: &namespace},
+				},
+			},
+		},
+	}
+
+	cached := &namespacedReader{Reader: newTestClient(), namespace: "default"}
+
+	// Gateways outside of the watched namespaces are read uncached.
+	reader := NewGatewayReader(cached, newTestClient(gateway), []string{"default"})
+
+	listeners, err := ParentListeners(context.Background(), reader, route)
+	require.NoError(t, err)
+	assert.Equal(t, gateway.Spec.Listeners, listeners)
+
+	// Gateways that cannot be read are ignored.
+	reader = NewGatewayReader(cached, &forbiddenReader{}, []string{"default"})
+
+	listeners, err = ParentListeners(context.Background(), reader, route)
+	require.NoError(t, err)
+	assert.Empty(t, listeners)
+
+	// Without listeners the route falls back to HTTPS.
+	route.Spec.Hostnames = []gatewayv1.Hostname{"app.example.com"}
+
+	url, err := BuildMonitorURL(route, listeners)
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com", url)
+}
+
+func TestParentGateways(t *testing.T) {
+	namespace := gatewayv1.Namespace("infra")
+
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-route", Namespace: "default"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{
+					{Name: "internal"},
+					{Name: "public", Namespace: &namespace},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []types.NamespacedName{
+		{Namespace: "default", Name: "internal"},
+		{Namespace: "infra", Name: "public"},
+	}, ParentGateways(route))
+}
+
+func newTestClient(objects ...client.Object) client.Client {
+	scheme := fakeclient.NewClientBuilder().Build().Scheme()
+	_ = gatewayv1.Install(scheme)
+	return fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
@@ -0,0 +1,68 @@
+package httproute
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GRPCRouteKind is the kind of GRPCRoute resources.
+const GRPCRouteKind = "GRPCRoute"
+
+// ValidateGRPCRoute checks if a GRPCRoute fulfills all criteria for
+// monitoring and returns an error on any violation. The GRPCRoute must have
+// at least one hostname and hostnames must not contain wildcards.
+func ValidateGRPCRoute(route *gatewayv1.GRPCRoute) error {
+	if len(route.Spec.Hostnames) == 0 {
+		return errors.New("grpcroute does not have any hostnames")
+	}
+
+	hostname := string(route.Spec.Hostnames[0])
+
+	if containsWildcard(hostname) {
+		return errors.Errorf("grpcroute hostname %q contains wildcards", hostname)
+	}
+
+	return nil
+}
+
+// BuildGRPCRouteMonitorURL builds the URL of the gRPC endpoint that should be
+// monitored for the GRPCRoute. Scheme and port are determined by the HTTP and
+// HTTPS listeners of the parent Gateways in the same way as for HTTPRoutes.
+// The URL never contains a path, as the endpoint is checked using the gRPC
+// health checking protocol. Unvalidated GRPCRoutes may cause
+// BuildGRPCRouteMonitorURL to panic.
+func BuildGRPCRouteMonitorURL(route *gatewayv1.GRPCRoute, listeners []gatewayv1.Listener) string {
+	return buildHostURL(string(route.Spec.Hostnames[0]), route.Annotations, listeners)
+}
+
+// GRPCRouteParentGateways returns the namespaced names of the Gateways
+// referenced in the ParentRefs of route. See ParentGateways.
+func GRPCRouteParentGateways(route *gatewayv1.GRPCRoute) []types.NamespacedName {
+	return parentGateways(route.Namespace, route.Spec.ParentRefs)
+}
+
+// GRPCRouteParentListeners returns the HTTP and HTTPS listeners the GRPCRoute
+// attaches to. See ParentListeners.
+func GRPCRouteParentListeners(ctx context.Context, c client.Reader, route *gatewayv1.GRPCRoute) ([]gatewayv1.Listener, error) {
+	return parentListeners(ctx, c, route.Namespace, route.Spec.ParentRefs, gatewayv1.HTTPProtocolType, gatewayv1.HTTPSProtocolType)
+}
+
+// NewGRPCRouteMonitorSource creates a MonitorSource for a gRPC health check
+// from a GRPCRoute resource. The route must have been validated before
+// calling this function. Listeners are the listeners of the route's parent
+// Gateways.
+func NewGRPCRouteMonitorSource(route *gatewayv1.GRPCRoute, listeners []gatewayv1.Listener) models.MonitorSource {
+	return models.MonitorSource{
+		Kind:        GRPCRouteKind,
+		Name:        route.Name,
+		Namespace:   route.Namespace,
+		Annotations: route.Annotations,
+		Type:        models.MonitorTypeGRPC,
+		URL:         BuildGRPCRouteMonitorURL(route, listeners),
+	}
+}
@@ -0,0 +1,105 @@
+package httproute
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestValidateGRPCRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    *gatewayv1.GRPCRoute
+		expected error
+	}{
+		{
+			name: "valid grpcroute with hostname",
+			route: &gatewayv1.GRPCRoute{
+				Spec: gatewayv1.GRPCRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.bar.baz"},
+				},
+			},
+		},
+		{
+			name: "wildcard hostnames are not supported",
+			route: &gatewayv1.GRPCRoute{
+				Spec: gatewayv1.GRPCRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"*.bar.baz"},
+				},
+			},
+			expected: errors.New(`grpcroute hostname "*.bar.baz" contains wildcards`),
+		},
+		{
+			name:     "grpcroute needs to have at least one hostname",
+			route:    &gatewayv1.GRPCRoute{},
+			expected: errors.New("grpcroute does not have any hostnames"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateGRPCRoute(test.route)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewGRPCRouteMonitorSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		listeners   []gatewayv1.Listener
+		expected    string
+	}{
+		{
+			name:     "defaults to https without listeners",
+			expected: "https://grpc.example.com",
+		},
+		{
+			name: "uses port of https listener",
+			listeners: []gatewayv1.Listener{
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 8443},
+			},
+			expected: "https://grpc.example.com:8443",
+		},
+		{
+			name:        "force-http uses http listener",
+			annotations: map[string]string{config.AnnotationForceHTTP: "true"},
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 8080},
+			},
+			expected: "http://grpc.example.com:8080",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &gatewayv1.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Annotations: test.annotations},
+				Spec: gatewayv1.GRPCRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"grpc.example.com"},
+				},
+			}
+
+			assert.Equal(t, models.MonitorSource{
+				Kind:        "GRPCRoute",
+				Name:        "foo",
+				Namespace:   "bar",
+				Annotations: test.annotations,
+				Type:        models.MonitorTypeGRPC,
+				URL:         test.expected,
+			}, NewGRPCRouteMonitorSource(route, test.listeners))
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -29,10 +30,15 @@ func Validate(route *gatewayv1.HTTPRoute) error {
 }
 
 // BuildMonitorURL builds the URL that should be monitored for the HTTPRoute.
-// Unvalidated HTTPRoutes may cause BuildMonitorURL to panic.
-func BuildMonitorURL(route *gatewayv1.HTTPRoute) (string, error) {
-	hostname := string(route.Spec.Hostnames[0])
-	host := buildHostURL(hostname, route.Annotations)
+// The listeners of the parent Gateways (see ParentListeners) determine the
+// scheme and port of the URL. Unvalidated HTTPRoutes may cause
+// BuildMonitorURL to panic.
+func BuildMonitorURL(route *gatewayv1.HTTPRoute, listeners []gatewayv1.Listener) (string, error) {
+	return buildMonitorURL(route, string(route.Spec.Hostnames[0]), listeners)
+}
+
+func buildMonitorURL(route *gatewayv1.HTTPRoute, hostname string, listeners []gatewayv1.Listener) (string, error) {
+	host := buildHostURL(hostname, route.Annotations, listeners)
 
 	u, err := url.Parse(host)
 	if err != nil {
@@ -40,31 +46,82 @@ func BuildMonitorURL(route *gatewayv1.HTTPRoute) (string, error) {
 	}
 
 	path, found := route.Annotations[config.AnnotationPathOverride]
-	if found {
+	if !found {
+		path = matchedPath(route)
+	}
+
+	if path != "" {
 		u.Path = path
 	}
 
 	return u.String(), nil
 }
 
-func buildHostURL(hostname string, annotations map[string]string) string {
+// matchedPath returns the value of the first Exact or PathPrefix path match
+// of the route's rules. A match without type is treated as PathPrefix, which
+// is the Gateway API default. Returns an empty string if there is no such
+// match or if it matches the root path.
+func matchedPath(route *gatewayv1.HTTPRoute) string {
+	for _, rule := range route.Spec.Rules {
+		for _, match := range rule.Matches {
+			if match.Path == nil || match.Path.Value == nil {
+				continue
+			}
+
+			if match.Path.Type != nil && *match.Path.Type != gatewayv1.PathMatchExact && *match.Path.Type != gatewayv1.PathMatchPathPrefix {
+				continue
+			}
+
+			if path := *match.Path.Value; path != "/" {
+				return path
+			}
+		}
+	}
+
+	return ""
+}
+
+// buildHostURL builds the scheme and host part of the monitor URL. If the
+// route attaches to an HTTPS listener for hostname, HTTPS is used, otherwise
+// HTTP if it attaches to an HTTP listener. If there are no matching
+// listeners, HTTPS is assumed. Non-default listener ports are added to the
+// host.
+func buildHostURL(hostname string, annotations map[string]string, listeners []gatewayv1.Listener) string {
 	a := config.Annotations(annotations)
 
 	if a.BoolValue(config.AnnotationForceHTTP) {
-		return fmt.Sprintf("http://%s", hostname)
+		listener := selectListener(listeners, hostname, gatewayv1.HTTPProtocolType)
+		return fmt.Sprintf("http://%s", hostWithPort(hostname, listener, defaultHTTPPort))
+	}
+
+	if listener := selectListener(listeners, hostname, gatewayv1.HTTPSProtocolType); listener != nil {
+		return fmt.Sprintf("https://%s", hostWithPort(hostname, listener, defaultHTTPSPort))
+	}
+
+	if listener := selectListener(listeners, hostname, gatewayv1.HTTPProtocolType); listener != nil {
+		return fmt.Sprintf("http://%s", hostWithPort(hostname, listener, defaultHTTPPort))
 	}
 
 	return fmt.Sprintf("https://%s", hostname)
 }
 
+func hostWithPort(hostname string, listener *gatewayv1.Listener, defaultPort gatewayv1.PortNumber) string {
+	if listener == nil || listener.Port == 0 || listener.Port == defaultPort {
+		return hostname
+	}
+
+	return fmt.Sprintf("%s:%d", hostname, listener.Port)
+}
+
 func containsWildcard(hostname string) bool {
 	return strings.Contains(hostname, "*")
 }
 
 // NewMonitorSource creates a MonitorSource from an HTTPRoute resource. The
-// route must have been validated before calling this function.
-func NewMonitorSource(route *gatewayv1.HTTPRoute) (models.MonitorSource, error) {
-	monitorURL, err := BuildMonitorURL(route)
+// route must have been validated before calling this function. Listeners are
+// the listeners of the route's parent Gateways.
+func NewMonitorSource(route *gatewayv1.HTTPRoute, listeners []gatewayv1.Listener) (models.MonitorSource, error) {
+	monitorURL, err := BuildMonitorURL(route, listeners)
 	if err != nil {
 		return models.MonitorSource{}, err
 	}
@@ -77,3 +134,49 @@ func NewMonitorSource(route *gatewayv1.HTTPRoute) (models.MonitorSource, error)
 		URL:         monitorURL,
 	}, nil
 }
+
+// MultiHostEnabled returns true if one monitor per hostname should be created
+// for the HTTPRoute. The ingress-monitor.bonial.com/multi-host annotation
+// takes precedence over defaultValue.
+func MultiHostEnabled(route *gatewayv1.HTTPRoute, defaultValue bool) bool {
+	return config.Annotations(route.Annotations).BoolValue(config.AnnotationMultiHost, defaultValue)
+}
+
+// NewMonitorSources creates one MonitorSource per distinct hostname of the
+// HTTPRoute. Hostnames containing wildcards are skipped. Listeners are the
+// listeners of the route's parent Gateways. Returns an error if the HTTPRoute
+// does not contain any hostname that can be monitored.
+func NewMonitorSources(route *gatewayv1.HTTPRoute, listeners []gatewayv1.Listener) ([]models.MonitorSource, error) {
+	var sources []models.MonitorSource
+
+	seen := sets.New[string]()
+
+	for _, hostname := range route.Spec.Hostnames {
+		host := string(hostname)
+		if containsWildcard(host) || seen.Has(host) {
+			continue
+		}
+
+		seen.Insert(host)
+
+		monitorURL, err := buildMonitorURL(route, host, listeners)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, models.MonitorSource{
+			Kind:        "HTTPRoute",
+			Name:        route.Name,
+			Namespace:   route.Namespace,
+			Annotations: route.Annotations,
+			URL:         monitorURL,
+			Host:        host,
+		})
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.New("httproute does not have any hostnames without wildcards")
+	}
+
+	return sources, nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,9 +57,10 @@ func TestValidate(t *testing.T) {
 
 func TestBuildMonitorURL(t *testing.T) {
 	tests := []struct {
-		name     string
-		route    *gatewayv1.HTTPRoute
-		expected string
+		name      string
+		route     *gatewayv1.HTTPRoute
+		listeners []gatewayv1.Listener
+		expected  string
 	}{
 		{
 			name: "defaults to https",
@@ -112,11 +114,111 @@ func TestBuildMonitorURL(t *testing.T) {
 			},
 			expected: "http://foo.bar.baz/health",
 		},
+		{
+			name: "path from first exact or prefix match",
+			route: &gatewayv1.HTTPRoute{
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.bar.baz"},
+					Rules: []gatewayv1.HTTPRouteRule{
+						{
+							Matches: []gatewayv1.HTTPRouteMatch{
+								pathMatch(gatewayv1.PathMatchRegularExpression, "/v[0-9]+"),
+								pathMatch(gatewayv1.PathMatchPathPrefix, "/"),
+							},
+						},
+						{
+							Matches: []gatewayv1.HTTPRouteMatch{
+								pathMatch(gatewayv1.PathMatchPathPrefix, "/api"),
+								pathMatch(gatewayv1.PathMatchExact, "/web"),
+							},
+						},
+					},
+				},
+			},
+			expected: "https://foo.bar.baz/api",
+		},
+		{
+			name: "path override takes precedence over path matches",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						config.AnnotationPathOverride: "/health",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.bar.baz"},
+					Rules: []gatewayv1.HTTPRouteRule{
+						{
+							Matches: []gatewayv1.HTTPRouteMatch{
+								pathMatch("", "/api"),
+							},
+						},
+					},
+				},
+			},
+			expected: "https://foo.bar.baz/health",
+		},
+		{
+			name: "https listener with non-default port",
+			route: &gatewayv1.HTTPRoute{
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.bar.baz"},
+				},
+			},
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 8443},
+			},
+			expected: "https://foo.bar.baz:8443",
+		},
+		{
+			name: "http only listener",
+			route: &gatewayv1.HTTPRoute{
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.bar.baz"},
+				},
+			},
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+			},
+			expected: "http://foo.bar.baz",
+		},
+		{
+			name: "ignores listeners for other hostnames",
+			route: &gatewayv1.HTTPRoute{
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.bar.baz"},
+				},
+			},
+			listeners: []gatewayv1.Listener{
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443, Hostname: hostname("other.bar.baz")},
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 8080, Hostname: hostname("*.bar.baz")},
+			},
+			expected: "http://foo.bar.baz:8080",
+		},
+		{
+			name: "force http uses port of http listener",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						config.AnnotationForceHTTP: "true",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.bar.baz"},
+				},
+			},
+			listeners: []gatewayv1.Listener{
+				{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 8080},
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+			},
+			expected: "http://foo.bar.baz:8080",
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			url, err := BuildMonitorURL(test.route)
+			url, err := BuildMonitorURL(test.route, test.listeners)
 			require.NoError(t, err)
 			assert.Equal(t, test.expected, url)
 		})
@@ -137,10 +239,81 @@ func TestNewMonitorSource(t *testing.T) {
 		},
 	}
 
-	source, err := NewMonitorSource(route)
+	source, err := NewMonitorSource(route, nil)
 	require.NoError(t, err)
 	assert.Equal(t, "my-route", source.Name)
 	assert.Equal(t, "default", source.Namespace)
 	assert.Equal(t, "https://app.example.com", source.URL)
 	assert.Equal(t, "true", source.Annotations[config.AnnotationEnabled])
 }
+
+func TestNewMonitorSources(t *testing.T) {
+	tests := []struct {
+		name        string
+		route       *gatewayv1.HTTPRoute
+		expected    []models.MonitorSource
+		expectedErr error
+	}{
+		{
+			name: "one source per distinct hostname",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-route", Namespace: "default"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"foo.example.com", "*.example.com", "bar.example.com", "foo.example.com"},
+					Rules: []gatewayv1.HTTPRouteRule{
+						{
+							Matches: []gatewayv1.HTTPRouteMatch{
+								pathMatch(gatewayv1.PathMatchPathPrefix, "/api"),
+							},
+						},
+					},
+				},
+			},
+			expected: []models.MonitorSource{
+				{Kind: "HTTPRoute", Name: "my-route", Namespace: "default", URL: "https://foo.example.com/api", Host: "foo.example.com"},
+				{Kind: "HTTPRoute", Name: "my-route", Namespace: "default", URL: "https://bar.example.com/api", Host: "bar.example.com"},
+			},
+		},
+		{
+			name: "httproute needs at least one hostname without wildcards",
+			route: &gatewayv1.HTTPRoute{
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"*.example.com"},
+				},
+			},
+			expectedErr: errors.New("httproute does not have any hostnames without wildcards"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sources, err := NewMonitorSources(test.route, nil)
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, sources)
+			}
+		})
+	}
+}
+
+// pathMatch creates an HTTPRouteMatch for the path value. If matchType is
+// empty, the match type is left unset.
+func pathMatch(matchType gatewayv1.PathMatchType, value string) gatewayv1.HTTPRouteMatch {
+	match := gatewayv1.HTTPRouteMatch{
+		Path: &gatewayv1.HTTPPathMatch{Value: &value},
+	}
+
+	if matchType != "" {
+		match.Path.Type = &matchType
+	}
+
+	return match
+}
+
+func hostname(name string) *gatewayv1.Hostname {
+	h := gatewayv1.Hostname(name)
+	return &h
+}
@@ -0,0 +1,71 @@
+package httproute
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// TLSRouteKind is the kind of TLSRoute resources.
+const TLSRouteKind = "TLSRoute"
+
+// ValidateTLSRoute checks if a TLSRoute fulfills all criteria for monitoring
+// and returns an error on any violation. The TLSRoute must have at least one
+// hostname and hostnames must not contain wildcards.
+func ValidateTLSRoute(route *gatewayv1alpha2.TLSRoute) error {
+	if len(route.Spec.Hostnames) == 0 {
+		return errors.New("tlsroute does not have any hostnames")
+	}
+
+	hostname := string(route.Spec.Hostnames[0])
+
+	if containsWildcard(hostname) {
+		return errors.Errorf("tlsroute hostname %q contains wildcards", hostname)
+	}
+
+	return nil
+}
+
+// BuildTLSRouteMonitorURL builds the URL of the TLS endpoint that should be
+// monitored for the TLSRoute. The URL always uses the https scheme. The port
+// of the first TLS listener of the parent Gateways accepting the hostname is
+// added if it is not the default port 443. Unvalidated TLSRoutes may cause
+// BuildTLSRouteMonitorURL to panic.
+func BuildTLSRouteMonitorURL(route *gatewayv1alpha2.TLSRoute, listeners []gatewayv1.Listener) string {
+	hostname := string(route.Spec.Hostnames[0])
+	listener := selectListener(listeners, hostname, gatewayv1.TLSProtocolType)
+
+	return fmt.Sprintf("https://%s", hostWithPort(hostname, listener, defaultTLSPort))
+}
+
+// TLSRouteParentGateways returns the namespaced names of the Gateways
+// referenced in the ParentRefs of route. See ParentGateways.
+func TLSRouteParentGateways(route *gatewayv1alpha2.TLSRoute) []types.NamespacedName {
+	return parentGateways(route.Namespace, route.Spec.ParentRefs)
+}
+
+// TLSRouteParentListeners returns the TLS listeners the TLSRoute attaches
+// to. See ParentListeners.
+func TLSRouteParentListeners(ctx context.Context, c client.Reader, route *gatewayv1alpha2.TLSRoute) ([]gatewayv1.Listener, error) {
+	return parentListeners(ctx, c, route.Namespace, route.Spec.ParentRefs, gatewayv1.TLSProtocolType)
+}
+
+// NewTLSRouteMonitorSource creates a MonitorSource for a TLS check from a
+// TLSRoute resource. The route must have been validated before calling this
+// function. Listeners are the listeners of the route's parent Gateways.
+func NewTLSRouteMonitorSource(route *gatewayv1alpha2.TLSRoute, listeners []gatewayv1.Listener) models.MonitorSource {
+	return models.MonitorSource{
+		Kind:        TLSRouteKind,
+		Name:        route.Name,
+		Namespace:   route.Namespace,
+		Annotations: route.Annotations,
+		Type:        models.MonitorTypeTLS,
+		URL:         BuildTLSRouteMonitorURL(route, listeners),
+	}
+}
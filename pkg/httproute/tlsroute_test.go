@@ -0,0 +1,100 @@
+package httproute
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestValidateTLSRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    *gatewayv1alpha2.TLSRoute
+		expected error
+	}{
+		{
+			name: "valid tlsroute with hostname",
+			route: &gatewayv1alpha2.TLSRoute{
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					Hostnames: []gatewayv1alpha2.Hostname{"foo.bar.baz"},
+				},
+			},
+		},
+		{
+			name: "wildcard hostnames are not supported",
+			route: &gatewayv1alpha2.TLSRoute{
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					Hostnames: []gatewayv1alpha2.Hostname{"*.bar.baz"},
+				},
+			},
+			expected: errors.New(`tlsroute hostname "*.bar.baz" contains wildcards`),
+		},
+		{
+			name:     "tlsroute needs to have at least one hostname",
+			route:    &gatewayv1alpha2.TLSRoute{},
+			expected: errors.New("tlsroute does not have any hostnames"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateTLSRoute(test.route)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewTLSRouteMonitorSource(t *testing.T) {
+	hostname := gatewayv1.Hostname("*.example.com")
+	otherHostname := gatewayv1.Hostname("other.example.org")
+
+	tests := []struct {
+		name      string
+		listeners []gatewayv1.Listener
+		expected  string
+	}{
+		{
+			name:     "defaults to port 443 without listeners",
+			expected: "https://db.example.com",
+		},
+		{
+			name: "uses port of matching tls listener",
+			listeners: []gatewayv1.Listener{
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 8443},
+				{Name: "other", Protocol: gatewayv1.TLSProtocolType, Port: 7443, Hostname: &otherHostname},
+				{Name: "tls", Protocol: gatewayv1.TLSProtocolType, Port: 6443, Hostname: &hostname},
+			},
+			expected: "https://db.example.com:6443",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &gatewayv1alpha2.TLSRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					Hostnames: []gatewayv1alpha2.Hostname{"db.example.com"},
+				},
+			}
+
+			assert.Equal(t, models.MonitorSource{
+				Kind:      "TLSRoute",
+				Name:      "foo",
+				Namespace: "bar",
+				Type:      models.MonitorTypeTLS,
+				URL:       test.expected,
+			}, NewTLSRouteMonitorSource(route, test.listeners))
+		})
+	}
+}
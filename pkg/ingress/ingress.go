@@ -9,6 +9,7 @@ import (
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/pkg/errors"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const (
@@ -96,3 +97,104 @@ func NewMonitorSource(ing *networkingv1.Ingress) (models.MonitorSource, error) {
 		URL:         monitorURL,
 	}, nil
 }
+
+// MultiHostEnabled returns true if one monitor per host should be created for
+// the ingress. The ingress-monitor.bonial.com/multi-host annotation takes
+// precedence over defaultValue.
+func MultiHostEnabled(ing *networkingv1.Ingress, defaultValue bool) bool {
+	return config.Annotations(ing.Annotations).BoolValue(config.AnnotationMultiHost, defaultValue)
+}
+
+// NewMonitorSources creates one MonitorSource per distinct host of the
+// ingress. If the ingress-monitor.bonial.com/multi-path annotation is set to
+// "true", one MonitorSource per distinct host and rule path is created
+// instead. Only paths of type Exact and Prefix are considered. Hosts
+// containing wildcards are skipped. Returns an error if the ingress does not
+// contain any host that can be monitored.
+func NewMonitorSources(ing *networkingv1.Ingress) ([]models.MonitorSource, error) {
+	annotations := config.Annotations(ing.Annotations)
+	multiPath := annotations.BoolValue(config.AnnotationMultiPath)
+	pathOverride, hasPathOverride := annotations[config.AnnotationPathOverride]
+
+	tlsHosts := sets.New[string]()
+	for _, tls := range ing.Spec.TLS {
+		tlsHosts.Insert(tls.Hosts...)
+	}
+
+	var sources []models.MonitorSource
+
+	seen := sets.New[string]()
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host == "" || containsWildcard(rule.Host) {
+			continue
+		}
+
+		paths := []string{""}
+		if multiPath && !hasPathOverride {
+			paths = rulePaths(rule)
+		}
+
+		for _, path := range paths {
+			key := rule.Host + path
+			if seen.Has(key) {
+				continue
+			}
+
+			seen.Insert(key)
+
+			scheme := "http"
+			if tlsHosts.Has(rule.Host) || forceHTTPS(ing) {
+				scheme = "https"
+			}
+
+			monitorURL := &url.URL{Scheme: scheme, Host: rule.Host, Path: path}
+			if hasPathOverride {
+				monitorURL.Path = pathOverride
+			}
+
+			sources = append(sources, models.MonitorSource{
+				Kind:        "Ingress",
+				Name:        ing.Name,
+				Namespace:   ing.Namespace,
+				Annotations: ing.Annotations,
+				URL:         monitorURL.String(),
+				Host:        rule.Host,
+				Path:        path,
+			})
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.New("ingress does not have any hosts without wildcards")
+	}
+
+	return sources, nil
+}
+
+// rulePaths returns the distinct Exact and Prefix paths of rule. If the rule
+// does not have any of these, the root path is returned.
+func rulePaths(rule networkingv1.IngressRule) []string {
+	if rule.HTTP == nil {
+		return []string{""}
+	}
+
+	var paths []string
+
+	for _, path := range rule.HTTP.Paths {
+		if path.PathType == nil || path.Path == "" || path.Path == "/" {
+			continue
+		}
+
+		switch *path.PathType {
+		case networkingv1.PathTypeExact, networkingv1.PathTypePrefix:
+			paths = append(paths, path.Path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return []string{""}
+	}
+
+	return paths
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -175,3 +176,154 @@ func TestBuildMonitorURL(t *testing.T) {
 		})
 	}
 }
+
+func TestNewMonitorSources(t *testing.T) {
+	prefix := networkingv1.PathTypePrefix
+	implementationSpecific := networkingv1.PathTypeImplementationSpecific
+
+	tests := []struct {
+		name        string
+		ingress     *networkingv1.Ingress
+		expected    []models.MonitorSource
+		expectedErr error
+	}{
+		{
+			name: "one source per distinct host",
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "kube-system"},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{Hosts: []string{"bar.example.com"}},
+					},
+					Rules: []networkingv1.IngressRule{
+						{Host: "foo.example.com"},
+						{Host: "bar.example.com"},
+						{Host: "foo.example.com"},
+						{Host: "*.example.com"},
+					},
+				},
+			},
+			expected: []models.MonitorSource{
+				{Kind: "Ingress", Name: "foo", Namespace: "kube-system", URL: "http://foo.example.com", Host: "foo.example.com"},
+				{Kind: "Ingress", Name: "foo", Namespace: "kube-system", URL: "https://bar.example.com", Host: "bar.example.com"},
+			},
+		},
+		{
+			name: "one source per host and path",
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "kube-system",
+					Annotations: map[string]string{
+						config.AnnotationMultiPath: "true",
+					},
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{
+						{
+							Host: "foo.example.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{Path: "/api", PathType: &prefix},
+										{Path: "/admin(/|$)(.*)", PathType: &implementationSpecific},
+										{Path: "/web", PathType: &prefix},
+									},
+								},
+							},
+						},
+						{Host: "bar.example.com"},
+					},
+				},
+			},
+			expected: []models.MonitorSource{
+				{
+					Kind: "Ingress", Name: "foo", Namespace: "kube-system", URL: "http://foo.example.com/api", Host: "foo.example.com", Path: "/api",
+					Annotations: map[string]string{config.AnnotationMultiPath: "true"},
+				},
+				{
+					Kind: "Ingress", Name: "foo", Namespace: "kube-system", URL: "http://foo.example.com/web", Host: "foo.example.com", Path: "/web",
+					Annotations: map[string]string{config.AnnotationMultiPath: "true"},
+				},
+				{
+					Kind: "Ingress", Name: "foo", Namespace: "kube-system", URL: "http://bar.example.com", Host: "bar.example.com",
+					Annotations: map[string]string{config.AnnotationMultiPath: "true"},
+				},
+			},
+		},
+		{
+			name: "path override takes precedence over rule paths",
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "kube-system",
+					Annotations: map[string]string{
+						config.AnnotationMultiPath:    "true",
+						config.AnnotationPathOverride: "/health",
+						config.AnnotationForceHTTPS:   "true",
+					},
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{
+						{
+							Host: "foo.example.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{Path: "/api", PathType: &prefix},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []models.MonitorSource{
+				{
+					Kind: "Ingress", Name: "foo", Namespace: "kube-system", URL: "https://foo.example.com/health", Host: "foo.example.com",
+					Annotations: map[string]string{
+						config.AnnotationMultiPath:    "true",
+						config.AnnotationPathOverride: "/health",
+						config.AnnotationForceHTTPS:   "true",
+					},
+				},
+			},
+		},
+		{
+			name: "ingress needs at least one host without wildcards",
+			ingress: &networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{
+						{Host: "*.example.com"},
+						{},
+					},
+				},
+			},
+			expectedErr: errors.New("ingress does not have any hosts without wildcards"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sources, err := NewMonitorSources(test.ingress)
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, sources)
+			}
+		})
+	}
+}
+
+func TestMultiHostEnabled(t *testing.T) {
+	ing := &networkingv1.Ingress{}
+
+	assert.False(t, MultiHostEnabled(ing, false))
+	assert.True(t, MultiHostEnabled(ing, true))
+
+	ing.Annotations = map[string]string{config.AnnotationMultiHost: "false"}
+
+	assert.False(t, MultiHostEnabled(ing, true))
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// redacted replaces the values of sensitive fields in diffs.
+const redacted = "<redacted>"
+
+// FieldDiff describes a single field in which an existing monitor differs
+// from the desired monitor.
+type FieldDiff struct {
+	// Field is the provider specific name of the field.
+	Field string
+
+	// Current is the formatted value of the field of the existing monitor.
+	Current string
+
+	// Desired is the formatted value of the field of the desired monitor.
+	Desired string
+}
+
+// String implements fmt.Stringer.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Field, d.Current, d.Desired)
+}
+
+// FieldDiffs is a list of field differences between an existing and a
+// desired monitor. An empty list means that the existing monitor is up to
+// date.
+type FieldDiffs []FieldDiff
+
+// Compare appends a FieldDiff for field if current and desired are not
+// semantically equal. Nil and empty slices and maps are considered equal.
+func (d *FieldDiffs) Compare(field string, current, desired interface{}) {
+	if equality.Semantic.DeepEqual(current, desired) {
+		return
+	}
+
+	*d = append(*d, FieldDiff{
+		Field:   field,
+		Current: fmt.Sprintf("%v", current),
+		Desired: fmt.Sprintf("%v", desired),
+	})
+}
+
+// CompareSensitive is like Compare, but does not include the values in the
+// FieldDiff. It should be used for credentials and other secrets.
+func (d *FieldDiffs) CompareSensitive(field string, current, desired interface{}) {
+	if equality.Semantic.DeepEqual(current, desired) {
+		return
+	}
+
+	*d = append(*d, FieldDiff{
+		Field:   field,
+		Current: redacted,
+		Desired: redacted,
+	})
+}
+
+// UnknownConfig returns FieldDiffs for an existing monitor whose Config is
+// not set or has an unexpected type. As it cannot be determined whether such
+// a monitor is up to date, it is always updated.
+func UnknownConfig() FieldDiffs {
+	return FieldDiffs{{Field: "Config", Current: "<unknown>", Desired: "<unknown>"}}
+}
+
+// Fields returns the names of all fields that differ.
+func (d FieldDiffs) Fields() []string {
+	fields := make([]string, len(d))
+	for i, diff := range d {
+		fields[i] = diff.Field
+	}
+
+	return fields
+}
+
+// String implements fmt.Stringer.
+func (d FieldDiffs) String() string {
+	diffs := make([]string, len(d))
+	for i, diff := range d {
+		diffs[i] = diff.String()
+	}
+
+	return strings.Join(diffs, ", ")
+}
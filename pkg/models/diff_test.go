@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldDiffs(t *testing.T) {
+	var diffs FieldDiffs
+
+	diffs.Compare("unchanged", "foo", "foo")
+	diffs.Compare("empty", []string{}, []string(nil))
+	diffs.Compare("changed", 1, 2)
+	diffs.Compare("slice", []string{"a"}, []string{"a", "b"})
+	diffs.CompareSensitive("password", "foo", "bar")
+	diffs.CompareSensitive("unchanged-password", "foo", "foo")
+
+	assert.Equal(t, []string{"changed", "slice", "password"}, diffs.Fields())
+	assert.Equal(t, "changed: 1 -> 2, slice: [a] -> [a b], password: <redacted> -> <redacted>", diffs.String())
+}
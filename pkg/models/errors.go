@@ -0,0 +1,213 @@
+package models
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// Providers classify their errors using the error types below, so that the
+// controller can decide how to proceed: retryable errors are retried with
+// backoff, rate limited errors are retried after the time requested by the
+// provider and permanent errors are not retried at all, as they cannot be
+// resolved without changing the resource or the controller configuration.
+// Errors which are not classified are treated as retryable by the
+// reconcilers, but are not retried inline by the provider middleware.
+
+// RetryableError indicates a transient provider failure, e.g. a timeout or
+// an HTTP 5xx response. Retrying the call may succeed.
+type RetryableError struct {
+	Err error
+}
+
+// Retryable wraps err in a *RetryableError. Returns nil if err is nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &RetryableError{Err: err}
+}
+
+// Error implements error.
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// PermanentError indicates a provider failure that does not go away by
+// retrying, e.g. missing permissions of the provider credentials.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent wraps err in a *PermanentError. Returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PermanentError{Err: err}
+}
+
+// Error implements error.
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidConfigError is a permanent error which indicates that the monitor
+// config is invalid, e.g. because of an invalid check frequency in an
+// annotation. It can only be resolved by fixing the resource or the
+// provider config.
+type InvalidConfigError struct {
+	Err error
+}
+
+// InvalidConfig wraps err in an *InvalidConfigError. Returns nil if err is
+// nil.
+func InvalidConfig(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &InvalidConfigError{Err: err}
+}
+
+// Error implements error.
+func (e *InvalidConfigError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitedError indicates that the provider rejected a call because a
+// rate limit or quota was exceeded.
+type RateLimitedError struct {
+	Err error
+
+	// RetryAfter is the duration after which the call should be retried as
+	// requested by the provider, e.g. via the Retry-After header. Zero if
+	// the provider did not request a specific duration.
+	RetryAfter time.Duration
+}
+
+// RateLimited wraps err in a *RateLimitedError. Returns nil if err is nil.
+func RateLimited(err error, retryAfter time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	return &RateLimitedError{Err: err, RetryAfter: retryAfter}
+}
+
+// Error implements error.
+func (e *RateLimitedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable returns true if err, or any of the errors combined in err,
+// is a *RetryableError, a *RateLimitedError or a network error.
+func IsRetryable(err error) bool {
+	for _, err := range splitErrors(err) {
+		var (
+			retryableErr   *RetryableError
+			rateLimitedErr *RateLimitedError
+			netErr         net.Error
+		)
+
+		if errors.As(err, &retryableErr) || errors.As(err, &rateLimitedErr) || errors.As(err, &netErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPermanent returns true if err is a *PermanentError or an
+// *InvalidConfigError. If err combines multiple errors, e.g. via
+// errors.Join, it is only permanent if all of them are.
+func IsPermanent(err error) bool {
+	errs := splitErrors(err)
+	if len(errs) == 0 {
+		return false
+	}
+
+	for _, err := range errs {
+		var (
+			permanentErr     *PermanentError
+			invalidConfigErr *InvalidConfigError
+		)
+
+		if !errors.As(err, &permanentErr) && !errors.As(err, &invalidConfigErr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RetryAfter returns the longest RetryAfter duration of the
+// *RateLimitedErrors in err. The second return value is false if err does
+// not contain a *RateLimitedError.
+func RetryAfter(err error) (time.Duration, bool) {
+	var (
+		retryAfter time.Duration
+		found      bool
+	)
+
+	for _, err := range splitErrors(err) {
+		var rateLimitedErr *RateLimitedError
+		if errors.As(err, &rateLimitedErr) {
+			found = true
+
+			if rateLimitedErr.RetryAfter > retryAfter {
+				retryAfter = rateLimitedErr.RetryAfter
+			}
+		}
+	}
+
+	return retryAfter, found
+}
+
+// splitErrors returns the errors which are combined in err, e.g. via
+// errors.Join, following wrapped errors. An error that does not combine
+// other errors is returned as is. Returns nil if err is nil.
+func splitErrors(err error) []error {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		joined, ok := e.(interface{ Unwrap() []error })
+		if !ok {
+			continue
+		}
+
+		var errs []error
+
+		for _, inner := range joined.Unwrap() {
+			errs = append(errs, splitErrors(inner)...)
+		}
+
+		return errs
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	return []error{err}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var errNetwork = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "monitor not found", err: ErrMonitorNotFound, expected: false},
+		{name: "unclassified error", err: errors.New("whoops"), expected: false},
+		{name: "network error", err: errNetwork, expected: true},
+		{name: "wrapped network error", err: pkgerrors.Wrapf(errNetwork, "failed to get monitor"), expected: true},
+		{name: "retryable error", err: pkgerrors.Wrapf(Retryable(errors.New("bad gateway")), "failed"), expected: true},
+		{name: "rate limited error", err: RateLimited(errors.New("too many requests"), 0), expected: true},
+		{name: "permanent error", err: Permanent(errors.New("forbidden")), expected: false},
+		{name: "joined errors", err: errors.Join(Permanent(errors.New("forbidden")), Retryable(errors.New("bad gateway"))), expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsRetryable(test.err))
+		})
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "unclassified error", err: errors.New("whoops"), expected: false},
+		{name: "permanent error", err: Permanent(errors.New("forbidden")), expected: true},
+		{name: "wrapped invalid config error", err: pkgerrors.Wrapf(InvalidConfig(errors.New("invalid timeout")), "provider %q", "foo"), expected: true},
+		{name: "retryable error", err: Retryable(errors.New("bad gateway")), expected: false},
+		{name: "all joined errors permanent", err: errors.Join(Permanent(errors.New("forbidden")), InvalidConfig(errors.New("invalid timeout"))), expected: true},
+		{name: "some joined errors permanent", err: errors.Join(Permanent(errors.New("forbidden")), errors.New("whoops")), expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsPermanent(test.err))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expected      time.Duration
+		expectedFound bool
+	}{
+		{name: "nil", err: nil},
+		{name: "retryable error", err: Retryable(errors.New("bad gateway"))},
+		{name: "rate limited error", err: RateLimited(errors.New("too many requests"), time.Minute), expected: time.Minute, expectedFound: true},
+		{name: "rate limited error without duration", err: RateLimited(errors.New("too many requests"), 0), expectedFound: true},
+		{
+			name: "joined errors",
+			err: errors.Join(
+				RateLimited(errors.New("too many requests"), 10*time.Second),
+				pkgerrors.Wrapf(RateLimited(errors.New("too many requests"), time.Minute), "provider %q", "foo"),
+				errors.New("whoops"),
+			),
+			expected:      time.Minute,
+			expectedFound: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			retryAfter, found := RetryAfter(test.err)
+			assert.Equal(t, test.expected, retryAfter)
+			assert.Equal(t, test.expectedFound, found)
+		})
+	}
+}
+
+func TestConstructorsReturnNilForNil(t *testing.T) {
+	assert.NoError(t, Retryable(nil))
+	assert.NoError(t, Permanent(nil))
+	assert.NoError(t, InvalidConfig(nil))
+	assert.NoError(t, RateLimited(nil, time.Second))
+}
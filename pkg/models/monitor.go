@@ -2,6 +2,8 @@ package models
 
 import (
 	"errors"
+	"net"
+	"net/url"
 
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 )
@@ -10,6 +12,24 @@ import (
 // be found.
 var ErrMonitorNotFound = errors.New("monitor not found")
 
+// MonitorType is the kind of check a monitor performs.
+type MonitorType string
+
+const (
+	// MonitorTypeHTTP checks an HTTP or HTTPS endpoint. This is the default
+	// if no type is set.
+	MonitorTypeHTTP MonitorType = "HTTP"
+
+	// MonitorTypeGRPC checks a gRPC endpoint using the gRPC health checking
+	// protocol. Providers without gRPC support reject these monitors as
+	// invalid configuration.
+	MonitorTypeGRPC MonitorType = "GRPC"
+
+	// MonitorTypeTLS checks that a TLS handshake with the endpoint succeeds
+	// and that its certificate does not expire.
+	MonitorTypeTLS MonitorType = "TLS"
+)
+
 // MonitorSource is a resource-agnostic representation of a Kubernetes resource
 // (e.g. Ingress or HTTPRoute) that serves as input for creating monitors.
 type MonitorSource struct {
@@ -27,23 +47,97 @@ type MonitorSource struct {
 	// Annotations are the annotations on the Kubernetes resource.
 	Annotations map[string]string
 
-	// URL is the pre-built monitor URL derived from the resource spec.
+	// Type is the kind of check that should be performed. Defaults to
+	// MonitorTypeHTTP if empty.
+	Type MonitorType
+
+	// URL is the pre-built monitor URL derived from the resource spec. The
+	// URL scheme is https for TLS endpoints and http otherwise, for all
+	// monitor types.
 	URL string
+
+	// Host is the host name monitored by this source. It is only set if one
+	// monitor per host is created for the resource.
+	Host string
+
+	// Path is the rule path monitored by this source. It is only set if one
+	// monitor per path is created for the resource.
+	Path string
 }
 
 // Monitor is a container for a website monitor.
 type Monitor struct {
 	// ID is the provider specific ID of a monitor.
-	ID string
+	ID string `json:"id,omitempty"`
 
 	// Name is the display name of the monitor.
-	Name string
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Kubernetes resource the monitor was
+	// created for. Providers that create Kubernetes objects do not place
+	// them next to the source resource but in a namespace of their own
+	// configuration, so that they can be looked up by monitor name.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Type is the kind of check the monitor performs. Defaults to
+	// MonitorTypeHTTP if empty.
+	Type MonitorType `json:"type,omitempty"`
 
 	// URL is the url that the monitor supervises.
-	URL string
+	URL string `json:"url"`
 
 	// Annotations are the annotations that are attached to the ingress object.
 	// These can be used by providers to set custom provider specific
 	// configuration.
-	Annotations config.Annotations
+	Annotations config.Annotations `json:"annotations,omitempty"`
+
+	// Config is the provider specific configuration of an existing monitor.
+	// It is set by the Get method of providers and passed back to their Diff
+	// method. It must be treated as read-only.
+	Config interface{} `json:"-"`
+}
+
+// TypeOrDefault returns the type of the monitor, defaulting to
+// MonitorTypeHTTP.
+func (m *Monitor) TypeOrDefault() MonitorType {
+	if m.Type == "" {
+		return MonitorTypeHTTP
+	}
+
+	return m.Type
+}
+
+// HostPort returns the host and port of the monitor URL. If the URL does not
+// contain a port, the default port of the URL scheme is returned. This is
+// used by providers that check TLS or gRPC endpoints, which are addressed by
+// host and port instead of URL.
+func (m *Monitor) HostPort() (host string, port string, err error) {
+	u, err := url.Parse(m.URL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Hostname() == "" {
+		return "", "", errors.New("monitor url does not contain a host")
+	}
+
+	port = u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	return u.Hostname(), port, nil
+}
+
+// Address returns the host:port address of the monitor URL. See HostPort.
+func (m *Monitor) Address() (string, error) {
+	host, port, err := m.HostPort()
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, port), nil
 }
@@ -1,7 +1,10 @@
 package fake
 
 import (
+	"net/http"
+
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
 	"github.com/stretchr/testify/mock"
 	networkingv1 "k8s.io/api/networking/v1"
 )
@@ -10,16 +13,32 @@ type Service struct {
 	mock.Mock
 }
 
-func (s *Service) EnsureMonitor(source models.MonitorSource) error {
+func (s *Service) EnsureMonitor(source models.MonitorSource) (*monitor.Result, error) {
 	args := s.Called(source)
 
-	return args.Error(0)
+	return result(args), args.Error(1)
 }
 
-func (s *Service) DeleteMonitor(source models.MonitorSource) error {
+func (s *Service) DeleteMonitor(source models.MonitorSource) (*monitor.Result, error) {
 	args := s.Called(source)
 
-	return args.Error(0)
+	return result(args), args.Error(1)
+}
+
+func (s *Service) DeleteOrphanedMonitors(listSources func() ([]models.MonitorSource, error)) ([]string, error) {
+	sources, err := listSources()
+	if err != nil {
+		return nil, err
+	}
+
+	args := s.Called(sources)
+
+	var orphans []string
+	if arg, ok := args.Get(0).([]string); ok {
+		orphans = arg
+	}
+
+	return orphans, args.Error(1)
 }
 
 func (s *Service) GetProviderIPSourceRanges(source models.MonitorSource) ([]string, error) {
@@ -38,3 +57,17 @@ func (s *Service) AnnotateIngress(ingress *networkingv1.Ingress) (updated bool,
 
 	return args.Bool(0), args.Error(1)
 }
+
+func (s *Service) CheckProviders(req *http.Request) error {
+	args := s.Called(req)
+
+	return args.Error(0)
+}
+
+func result(args mock.Arguments) *monitor.Result {
+	if arg, ok := args.Get(0).(*monitor.Result); ok {
+		return arg
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"net/http"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/middleware"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// withMiddleware wraps the provider p with the rate limit, retry and circuit
+// breaker middlewares that are enabled in options. The breaker sees each
+// call first, so that a call is only counted as failed after all retries
+// failed. Returns the breaker of the provider, which is nil if circuit
+// breaking is disabled.
+func withMiddleware(name string, p provider.Interface, options *config.Options) (provider.Interface, *middleware.Breaker) {
+	var (
+		middlewares []middleware.Middleware
+		breaker     *middleware.Breaker
+	)
+
+	if options.ProviderBreakerFailureThreshold > 0 {
+		breaker = middleware.NewBreaker(name, options.ProviderBreakerFailureThreshold, options.ProviderBreakerCooldown)
+		middlewares = append(middlewares, breaker.Middleware())
+	}
+
+	if options.ProviderMaxRetries > 0 {
+		middlewares = append(middlewares, middleware.Retry(name, options.ProviderMaxRetries, wait.Backoff{
+			Duration: options.ProviderRetryBackoff,
+			Factor:   2,
+			Jitter:   0.1,
+			Steps:    options.ProviderMaxRetries,
+		}))
+	}
+
+	if options.ProviderRateLimit > 0 {
+		limiter := rate.NewLimiter(rate.Limit(options.ProviderRateLimit), options.ProviderRateLimitBurst)
+		middlewares = append(middlewares, middleware.RateLimit(limiter))
+	}
+
+	return middleware.Wrap(p, middlewares...), breaker
+}
+
+// CheckProviders implements IngressService.
+func (s *service) CheckProviders(req *http.Request) error {
+	var errs []error
+
+	for _, breaker := range s.breakers {
+		err := breaker.HealthCheck(req)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return joinErrors(errs)
+}
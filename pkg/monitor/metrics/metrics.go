@@ -45,6 +45,83 @@ var (
 		Name: "ingress_monitor_controller_httproute_validation_errors_total",
 		Help: "Total number of HTTPRoute validation errors by namespace and name",
 	}, []string{"namespace", "name"})
+
+	// GRPCRouteValidationErrorsTotal is a counter for the total number of
+	// failed GRPCRoute validation events.
+	GRPCRouteValidationErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_grpcroute_validation_errors_total",
+		Help: "Total number of GRPCRoute validation errors by namespace and name",
+	}, []string{"namespace", "name"})
+
+	// TLSRouteValidationErrorsTotal is a counter for the total number of
+	// failed TLSRoute validation events.
+	TLSRouteValidationErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_tlsroute_validation_errors_total",
+		Help: "Total number of TLSRoute validation errors by namespace and name",
+	}, []string{"namespace", "name"})
+
+	// ServiceValidationErrorsTotal is a counter for the total number of
+	// failed Service validation events.
+	ServiceValidationErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_service_validation_errors_total",
+		Help: "Total number of Service validation errors by namespace and name",
+	}, []string{"namespace", "name"})
+
+	// RouteValidationErrorsTotal is a counter for the total number of failed
+	// OpenShift Route validation events.
+	RouteValidationErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_route_validation_errors_total",
+		Help: "Total number of OpenShift Route validation errors by namespace and name",
+	}, []string{"namespace", "name"})
+
+	// IngressRouteValidationErrorsTotal is a counter for the total number of
+	// failed Traefik IngressRoute validation events.
+	IngressRouteValidationErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_ingressroute_validation_errors_total",
+		Help: "Total number of Traefik IngressRoute validation errors by namespace and name",
+	}, []string{"namespace", "name"})
+
+	// HTTPProxyValidationErrorsTotal is a counter for the total number of
+	// failed Contour HTTPProxy validation events.
+	HTTPProxyValidationErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_httpproxy_validation_errors_total",
+		Help: "Total number of Contour HTTPProxy validation errors by namespace and name",
+	}, []string{"namespace", "name"})
+
+	// ProviderErrorsTotal is a counter for the total number of failed
+	// monitor operations by provider.
+	ProviderErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_provider_errors_total",
+		Help: "Total number of failed monitor operations by provider",
+	}, []string{"provider"})
+
+	// ProviderRetriesTotal is a counter for the total number of retried
+	// provider calls by provider.
+	ProviderRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_provider_retries_total",
+		Help: "Total number of retried provider calls by provider",
+	}, []string{"provider"})
+
+	// ProviderCircuitBreakerState is a gauge for the state of the circuit
+	// breaker of each provider: 0 is closed, 1 is half-open and 2 is open.
+	ProviderCircuitBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingress_monitor_controller_provider_circuit_breaker_state",
+		Help: "State of the provider circuit breaker by provider (0 = closed, 1 = half-open, 2 = open)",
+	}, []string{"provider"})
+
+	// OrphanedMonitors is a gauge for the number of orphaned monitors that
+	// were found during the last garbage collection run.
+	OrphanedMonitors = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "ingress_monitor_controller_orphaned_monitors",
+		Help: "Number of orphaned monitors found during the last garbage collection run",
+	})
+
+	// DryRunOperationsTotal is a counter for the total number of monitor
+	// operations that were skipped in dry run mode.
+	DryRunOperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress_monitor_controller_dry_run_operations_total",
+		Help: "Total number of monitor operations skipped in dry run mode by operation and monitor",
+	}, []string{"operation", "monitor"})
 )
 
 func init() {
@@ -54,5 +131,16 @@ func init() {
 		MonitorsDeletedTotal,
 		IngressValidationErrorsTotal,
 		HTTPRouteValidationErrorsTotal,
+		GRPCRouteValidationErrorsTotal,
+		TLSRouteValidationErrorsTotal,
+		ServiceValidationErrorsTotal,
+		RouteValidationErrorsTotal,
+		IngressRouteValidationErrorsTotal,
+		HTTPProxyValidationErrorsTotal,
+		ProviderErrorsTotal,
+		ProviderRetriesTotal,
+		ProviderCircuitBreakerState,
+		OrphanedMonitors,
+		DryRunOperationsTotal,
 	)
 }
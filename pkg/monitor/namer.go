@@ -20,6 +20,14 @@ type templateArgs struct {
 	IngressName string
 
 	Namespace string
+
+	// Host is the monitored host name. Only set if one monitor per host is
+	// created.
+	Host string
+
+	// Path is the monitored rule path. Only set if one monitor per path is
+	// created.
+	Path string
 }
 
 // Namer builds names for ingress monitors from a name template.
@@ -52,6 +60,8 @@ func (n *Namer) Name(source models.MonitorSource) (string, error) {
 		Name:        source.Name,
 		IngressName: source.Name,
 		Namespace:   source.Namespace,
+		Host:        source.Host,
+		Path:        source.Path,
 	})
 	if err != nil {
 		return "", err
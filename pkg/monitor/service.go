@@ -1,26 +1,106 @@
 package monitor
 
 import (
+	stderrors "errors"
+	"net/http"
+	"slices"
+	"strings"
+
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/dryrun"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/middleware"
+	"github.com/pkg/errors"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 var log = logf.Log.WithName("monitor-service")
 
+// Operation is the operation that was performed on a monitor.
+type Operation string
+
+const (
+	// OperationNone indicates that the monitor was left untouched.
+	OperationNone Operation = "none"
+
+	// OperationCreated indicates that the monitor was created.
+	OperationCreated Operation = "created"
+
+	// OperationUpdated indicates that the monitor was updated.
+	OperationUpdated Operation = "updated"
+
+	// OperationDeleted indicates that the monitor was deleted.
+	OperationDeleted Operation = "deleted"
+)
+
+// Result is the result of ensuring or deleting a monitor.
+type Result struct {
+	// Monitor is the monitor the operation was performed on. For deletions
+	// only the name of the monitor is set. If the monitor is managed by
+	// multiple providers, it is the monitor of the first provider that
+	// succeeded.
+	Monitor *models.Monitor
+
+	// Operation is the operation that was performed on the monitor. If the
+	// monitor is managed by multiple providers, it is the most significant
+	// operation that was performed by any of them.
+	Operation Operation
+
+	// Providers contains the results of the individual providers the
+	// operation was performed with, in the order of the selected providers.
+	Providers []ProviderResult
+}
+
+// ProviderResult is the result of ensuring or deleting a monitor with a
+// single provider.
+type ProviderResult struct {
+	// Provider is the name of the provider.
+	Provider string
+
+	// Monitor is the monitor the operation was performed on. It is nil if
+	// the operation failed.
+	Monitor *models.Monitor
+
+	// Operation is the operation that was performed on the monitor.
+	Operation Operation
+
+	// Err is the error returned by the provider, if any.
+	Err error
+}
+
 // Service defines the interface for a service that takes care of creating,
 // updating or deleting monitors.
 type Service interface {
-	// EnsureMonitor ensures that a monitor is in sync with the given source.
-	// If the monitor does not exist, it will be created.
-	EnsureMonitor(source models.MonitorSource) error
-
-	// DeleteMonitor deletes the monitor for the given source. It must not be
-	// treated as an error if the monitor was already deleted.
-	DeleteMonitor(source models.MonitorSource) error
+	// EnsureMonitor ensures that a monitor is in sync with the given source
+	// in each of the providers selected by the source. If the monitor does
+	// not exist, it will be created. An existing monitor is only updated if
+	// the provider reports differences to the desired monitor, otherwise the
+	// operation of the result is OperationNone. The result contains the
+	// monitor as it was sent to the provider. If some of the providers
+	// fail, the result of the others is returned along with the error.
+	EnsureMonitor(source models.MonitorSource) (*Result, error)
+
+	// DeleteMonitor deletes the monitor for the given source from each of
+	// the providers selected by the source, or from all enabled providers if
+	// the source does not select any. It must not be treated as an error if
+	// the monitor was already deleted, in which case the operation of the
+	// result is OperationNone.
+	DeleteMonitor(source models.MonitorSource) (*Result, error)
+
+	// DeleteOrphanedMonitors deletes all monitors owned by the controller
+	// which do not belong to any of the sources returned by listSources from
+	// each enabled provider. A monitor is owned by the controller if its
+	// name starts with the configured garbage collection name prefix. The
+	// monitors are listed before listSources is called, so that monitors
+	// created for new sources in the meantime are never considered
+	// orphaned. In dry run mode, orphaned monitors are only reported.
+	// Returns the names of all orphaned monitors.
+	DeleteOrphanedMonitors(listSources func() ([]models.MonitorSource, error)) ([]string, error)
 }
 
 // IngressService extends Service with Ingress-specific functionality for
@@ -37,130 +117,495 @@ type IngressService interface {
 	// annotations were added, updated or deleted, the return value will be
 	// true.
 	AnnotateIngress(ingress *networkingv1.Ingress) (updated bool, err error)
+
+	// CheckProviders returns an error if the circuit breaker of any provider
+	// is open. It has the signature of a healthz.Checker, so that it can be
+	// registered as a manager health check.
+	CheckProviders(req *http.Request) error
 }
 
 type service struct {
-	provider provider.Interface
-	namer    *Namer
-	options  *config.Options
+	providers        []namedProvider
+	breakers         []*middleware.Breaker
+	defaultProviders []string
+	namer            *Namer
+	multiHostNamer   *Namer
+	options          *config.Options
+}
+
+// namedProvider is an enabled monitor provider together with its name.
+type namedProvider struct {
+	provider.Interface
+
+	name string
+
+	// validator validates monitors before they are sent to the provider.
+	// It is nil if the provider does not implement provider.Validator. It
+	// is captured before the provider is wrapped, as the wrappers do not
+	// implement optional interfaces.
+	validator provider.Validator
 }
 
-// NewService creates a new Service with options. Returns an error if service
-// initialization fails.
-func NewService(options *config.Options) (IngressService, error) {
-	provider, err := provider.New(options.ProviderName, options.ProviderConfig)
+// validate validates the global annotations of monitor and runs the
+// validator of the provider, if any. All annotation violations are returned
+// together. Validation errors are invalid config errors, as they can only
+// be resolved by fixing the annotations of the resource.
+func (p namedProvider) validate(monitor *models.Monitor) error {
+	errs := []error{config.ValidateAnnotations(monitor.Annotations)}
+
+	if p.validator != nil {
+		errs = append(errs, p.validator.Validate(monitor))
+	}
+
+	return models.InvalidConfig(config.JoinAnnotationErrors(errs...))
+}
+
+// NewService creates a new Service with options. A provider is created for
+// each enabled provider and the client is passed on to all of them. Calls to
+// each provider are rate limited, retried and guarded by a circuit breaker
+// as configured in options. Returns an error if service initialization
+// fails.
+func NewService(options *config.Options, client client.Client) (IngressService, error) {
+	var (
+		providers []namedProvider
+		breakers  []*middleware.Breaker
+	)
+
+	for _, name := range options.EnabledProviders() {
+		p, err := provider.New(name, options.ProviderConfig, client)
+		if err != nil {
+			return nil, err
+		}
+
+		validator, _ := p.(provider.Validator)
+
+		if options.DryRun {
+			p = dryrun.NewProvider(p)
+		}
+
+		p, breaker := withMiddleware(name, p, options)
+		if breaker != nil {
+			breakers = append(breakers, breaker)
+		}
+
+		providers = append(providers, namedProvider{Interface: p, name: name, validator: validator})
+	}
+
+	namer, err := NewNamer(options.NameTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	namer, err := NewNamer(options.NameTemplate)
+	multiHostNamer, err := NewNamer(options.MultiHostNameTemplate)
 	if err != nil {
 		return nil, err
 	}
 
 	s := &service{
-		provider: provider,
-		namer:    namer,
-		options:  options,
+		providers:        providers,
+		breakers:         breakers,
+		defaultProviders: options.DefaultProviders(),
+		namer:            namer,
+		multiHostNamer:   multiHostNamer,
+		options:          options,
 	}
 
 	return s, nil
 }
 
 // EnsureMonitor implements Service.
-func (s *service) EnsureMonitor(source models.MonitorSource) error {
-	newMonitor, err := s.buildMonitorModel(source)
+func (s *service) EnsureMonitor(source models.MonitorSource) (*Result, error) {
+	providers, err := s.selectProviders(source)
 	if err != nil {
-		return err
-	}
-
-	oldMonitor, err := s.provider.Get(newMonitor.Name)
-	if err == models.ErrMonitorNotFound {
-		return s.createMonitor(newMonitor)
-	} else if err != nil {
-		return err
+		return nil, err
 	}
 
-	return s.updateMonitor(oldMonitor, newMonitor)
+	return s.fanOut(providers, func(p namedProvider) (*Result, error) {
+		// Each provider gets its own model, as providers may modify it.
+		newMonitor, err := s.buildMonitorModel(source)
+		if err != nil {
+			return nil, err
+		}
+
+		// Invalid annotations are rejected before calling the provider,
+		// instead of pushing a monitor with zero values.
+		err = p.validate(newMonitor)
+		if err != nil {
+			return nil, err
+		}
+
+		oldMonitor, err := p.Get(newMonitor.Name)
+		if err == models.ErrMonitorNotFound {
+			return createMonitor(p, newMonitor)
+		} else if err != nil {
+			return nil, err
+		}
+
+		return updateMonitor(p, oldMonitor, newMonitor)
+	})
 }
 
 // DeleteMonitor implements Service.
-func (s *service) DeleteMonitor(source models.MonitorSource) error {
-	name, err := s.namer.Name(source)
+func (s *service) DeleteMonitor(source models.MonitorSource) (*Result, error) {
+	name, err := s.monitorName(source)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if s.options.NoDelete {
 		log.V(1).Info("monitor deletion is disabled, not deleting", "monitor", name)
-		return nil
+		return &Result{Monitor: &models.Monitor{Name: name}, Operation: OperationNone}, nil
+	}
+
+	// The source of a resource that was already deleted does not carry the
+	// providers annotation anymore, so the monitor is deleted from all
+	// enabled providers unless the source selects valid providers
+	// explicitly.
+	providers := s.providers
+
+	if _, ok := source.Annotations[config.AnnotationProviders]; ok {
+		if selected, err := s.selectProviders(source); err == nil {
+			providers = selected
+		}
 	}
 
-	return s.deleteMonitor(name)
+	return s.fanOut(providers, func(p namedProvider) (*Result, error) {
+		return deleteMonitor(p, name)
+	})
 }
 
-func (s *service) createMonitor(monitor *models.Monitor) error {
-	err := s.provider.Create(monitor)
+// DeleteOrphanedMonitors implements Service.
+func (s *service) DeleteOrphanedMonitors(listSources func() ([]models.MonitorSource, error)) ([]string, error) {
+	var errs []error
+
+	monitors := make(map[string][]*models.Monitor, len(s.providers))
+
+	for _, p := range s.providers {
+		providerMonitors, err := p.List()
+		if err != nil {
+			errs = append(errs, s.providerError(p, len(s.providers), err))
+			continue
+		}
+
+		monitors[p.name] = providerMonitors
+	}
+
+	sources, err := listSources()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	expected := make(map[string]sets.Set[string], len(s.providers))
+
+	for _, p := range s.providers {
+		expected[p.name] = sets.New[string]()
+	}
+
+	for _, source := range sources {
+		name, err := s.monitorName(source)
+		if err != nil {
+			return nil, err
+		}
+
+		// Monitors of sources with an invalid provider selection are kept in
+		// all providers until the selection is fixed.
+		providers, err := s.selectProviders(source)
+		if err != nil {
+			providers = s.providers
+		}
+
+		for _, p := range providers {
+			expected[p.name].Insert(name)
+		}
+	}
+
+	var (
+		orphans     []string
+		orphanCount int
+	)
+
+	for _, p := range s.providers {
+		providerMonitors, ok := monitors[p.name]
+		if !ok {
+			continue
+		}
+
+		providerOrphans, err := s.deleteOrphanedMonitors(p, providerMonitors, expected[p.name])
+		if err != nil {
+			errs = append(errs, s.providerError(p, len(s.providers), err))
+		}
+
+		orphanCount += len(providerOrphans)
+
+		for _, name := range providerOrphans {
+			if !slices.Contains(orphans, name) {
+				orphans = append(orphans, name)
+			}
+		}
+	}
+
+	metrics.OrphanedMonitors.Set(float64(orphanCount))
+
+	return orphans, joinErrors(errs)
+}
+
+// deleteOrphanedMonitors deletes all monitors owned by the controller from
+// the monitors of p whose names are not expected. Returns the names of all
+// orphaned monitors.
+func (s *service) deleteOrphanedMonitors(p namedProvider, monitors []*models.Monitor, expected sets.Set[string]) ([]string, error) {
+	var orphans []string
+
+	for _, monitor := range monitors {
+		if strings.HasPrefix(monitor.Name, s.options.GCNamePrefix) && !expected.Has(monitor.Name) {
+			orphans = append(orphans, monitor.Name)
+		}
+	}
+
+	if s.options.GCDryRun || s.options.NoDelete {
+		for _, name := range orphans {
+			log.Info("found orphaned monitor, not deleting in dry run mode", "monitor", name, "provider", p.name)
+		}
+
+		return orphans, nil
+	}
+
+	for _, name := range orphans {
+		_, err := deleteMonitor(p, name)
+		if err != nil {
+			return orphans, err
+		}
+	}
+
+	return orphans, nil
+}
+
+// selectProviders returns the providers that manage the monitors of source.
+// These are the providers listed in the providers annotation of source or
+// the default providers if the annotation is absent. Returns an invalid
+// config error if source selects a provider that is not enabled or if the
+// annotation does not select any provider at all, e.g. because it only
+// contains commas.
+func (s *service) selectProviders(source models.MonitorSource) ([]namedProvider, error) {
+	annotations := config.Annotations(source.Annotations)
+
+	names := s.defaultProviders
+	if annotations.StringValue(config.AnnotationProviders) != "" {
+		names = annotations.StringSliceValue(config.AnnotationProviders)
+	}
+
+	providers := make([]namedProvider, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		i := slices.IndexFunc(s.providers, func(p namedProvider) bool { return p.name == name })
+		if i < 0 {
+			return nil, models.InvalidConfig(errors.Errorf("provider %q selected via annotation %q is not enabled", name, config.AnnotationProviders))
+		}
+
+		if !slices.ContainsFunc(providers, func(p namedProvider) bool { return p.name == name }) {
+			providers = append(providers, s.providers[i])
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, models.InvalidConfig(errors.Errorf("annotation %q does not select any provider", config.AnnotationProviders))
+	}
+
+	return providers, nil
+}
+
+// providerError annotates err with the name of p if the operation that
+// caused it involved more than one provider, so that single provider setups
+// keep their error messages unchanged.
+func (s *service) providerError(p namedProvider, numProviders int, err error) error {
+	metrics.ProviderErrorsTotal.WithLabelValues(p.name).Inc()
+
+	if numProviders > 1 {
+		return errors.Wrapf(err, "provider %q", p.name)
+	}
+
+	return err
+}
+
+// fanOut runs op for each of the providers and merges their results. A
+// failing provider does not prevent op from being run for the remaining
+// ones. If any provider fails, the merged result is returned together with
+// the errors of all failed providers.
+func (s *service) fanOut(providers []namedProvider, op func(namedProvider) (*Result, error)) (*Result, error) {
+	result := &Result{Operation: OperationNone}
+
+	var errs []error
+
+	for _, p := range providers {
+		r, err := op(p)
+		if err != nil {
+			errs = append(errs, s.providerError(p, len(providers), err))
+			result.Providers = append(result.Providers, ProviderResult{Provider: p.name, Err: err})
+			continue
+		}
+
+		if result.Monitor == nil {
+			result.Monitor = r.Monitor
+		}
+
+		if operationPriority[r.Operation] > operationPriority[result.Operation] {
+			result.Operation = r.Operation
+		}
+
+		result.Providers = append(result.Providers, ProviderResult{
+			Provider:  p.name,
+			Monitor:   r.Monitor,
+			Operation: r.Operation,
+		})
+	}
+
+	if len(errs) == 0 {
+		return result, nil
+	}
+
+	if result.Monitor == nil {
+		// All providers failed, there is nothing to report.
+		return nil, joinErrors(errs)
+	}
+
+	return result, joinErrors(errs)
+}
+
+// operationPriority defines which operation is reported in the merged
+// result if providers performed different operations on a monitor.
+var operationPriority = map[Operation]int{
+	OperationNone:    0,
+	OperationDeleted: 1,
+	OperationUpdated: 2,
+	OperationCreated: 3,
+}
+
+// joinErrors combines errs into a single error. A single error is returned
+// as is. Returns nil if errs is empty.
+func joinErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return stderrors.Join(errs...)
+}
+
+func createMonitor(p namedProvider, monitor *models.Monitor) (*Result, error) {
+	err := p.Create(monitor)
+	if err != nil {
+		return nil, err
 	}
 
 	metrics.MonitorsCreatedTotal.WithLabelValues(monitor.Name).Inc()
-	log.Info("monitor created", "monitor", monitor.Name)
+	log.Info("monitor created", "monitor", monitor.Name, "provider", p.name)
 
-	return nil
+	return &Result{Monitor: monitor, Operation: OperationCreated}, nil
 }
 
-func (s *service) updateMonitor(oldMonitor, newMonitor *models.Monitor) error {
+// updateMonitor updates oldMonitor to match newMonitor. The update is
+// skipped if the provider does not report any differences between them.
+func updateMonitor(p namedProvider, oldMonitor, newMonitor *models.Monitor) (*Result, error) {
 	newMonitor.ID = oldMonitor.ID
 
-	err := s.provider.Update(newMonitor)
+	diffs, err := p.Diff(oldMonitor, newMonitor)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if len(diffs) == 0 {
+		log.V(1).Info("monitor is up to date", "monitor", newMonitor.Name, "provider", p.name)
+		return &Result{Monitor: newMonitor, Operation: OperationNone}, nil
+	}
+
+	err = p.Update(newMonitor)
+	if err != nil {
+		return nil, err
 	}
 
 	metrics.MonitorsUpdatedTotal.WithLabelValues(newMonitor.Name).Inc()
-	log.Info("monitor updated", "monitor", newMonitor.Name)
+	log.Info("monitor updated", "monitor", newMonitor.Name, "provider", p.name, "diff", diffs.String())
 
-	return nil
+	return &Result{Monitor: newMonitor, Operation: OperationUpdated}, nil
 }
 
-func (s *service) deleteMonitor(name string) error {
-	err := s.provider.Delete(name)
+func deleteMonitor(p namedProvider, name string) (*Result, error) {
+	result := &Result{Monitor: &models.Monitor{Name: name}, Operation: OperationNone}
+
+	err := p.Delete(name)
 	if err == models.ErrMonitorNotFound {
-		log.V(1).Info("monitor is not present", "monitor", name)
-		return nil
+		log.V(1).Info("monitor is not present", "monitor", name, "provider", p.name)
+		return result, nil
 	} else if err != nil {
-		return err
+		return nil, err
 	}
 
 	metrics.MonitorsDeletedTotal.WithLabelValues(name).Inc()
-	log.Info("monitor deleted", "monitor", name)
+	log.Info("monitor deleted", "monitor", name, "provider", p.name)
 
-	return nil
+	result.Operation = OperationDeleted
+
+	return result, nil
 }
 
 func (s *service) buildMonitorModel(source models.MonitorSource) (*models.Monitor, error) {
-	name, err := s.namer.Name(source)
+	name, err := s.monitorName(source)
 	if err != nil {
 		return nil, err
 	}
 
 	monitor := &models.Monitor{
+		Type:        source.Type,
 		URL:         source.URL,
 		Name:        name,
+		Namespace:   source.Namespace,
 		Annotations: source.Annotations,
 	}
 
 	return monitor, nil
 }
 
-// GetProviderIPSourceRanges implements IngressService.
+// monitorName builds the monitor name for source. Sources that belong to a
+// resource with one monitor per host are named using the multi host name
+// template to avoid name collisions.
+func (s *service) monitorName(source models.MonitorSource) (string, error) {
+	if source.Host != "" {
+		return s.multiHostNamer.Name(source)
+	}
+
+	return s.namer.Name(source)
+}
+
+// GetProviderIPSourceRanges implements IngressService. If source selects
+// multiple providers, the union of their IP source ranges is returned.
 func (s *service) GetProviderIPSourceRanges(source models.MonitorSource) ([]string, error) {
-	monitor, err := s.buildMonitorModel(source)
+	providers, err := s.selectProviders(source)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.provider.GetIPSourceRanges(monitor)
+	var sourceRanges []string
+
+	for _, p := range providers {
+		monitor, err := s.buildMonitorModel(source)
+		if err != nil {
+			return nil, err
+		}
+
+		ranges, err := p.GetIPSourceRanges(monitor)
+		if err != nil {
+			return nil, s.providerError(p, len(providers), err)
+		}
+
+		for _, sourceRange := range ranges {
+			if !slices.Contains(sourceRanges, sourceRange) {
+				sourceRanges = append(sourceRanges, sourceRange)
+			}
+		}
+	}
+
+	return sourceRanges, nil
 }
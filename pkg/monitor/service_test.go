@@ -14,12 +14,14 @@ import (
 
 func TestService_EnsureMonitor(t *testing.T) {
 	tests := []struct {
-		name     string
-		source   models.MonitorSource
-		options  config.Options
-		setup    func(*fake.Provider)
-		validate func(*testing.T, *fake.Provider)
-		expected error
+		name       string
+		source     models.MonitorSource
+		options    config.Options
+		setup      func(*fake.Provider)
+		validate   func(*testing.T, *fake.Provider)
+		expectedOp Operation
+		expectedID string
+		expected   error
 	}{
 		{
 			name: "non-existent monitor is created",
@@ -34,13 +36,15 @@ func TestService_EnsureMonitor(t *testing.T) {
 			setup: func(p *fake.Provider) {
 				p.On("Get", "kube-system-foo").Return(nil, models.ErrMonitorNotFound)
 				p.On("Create", &models.Monitor{
-					URL:  "http://foo.bar.baz",
-					Name: "kube-system-foo",
+					URL:       "http://foo.bar.baz",
+					Name:      "kube-system-foo",
+					Namespace: "kube-system",
 					Annotations: config.Annotations{
 						config.AnnotationEnabled: "true",
 					},
 				}).Return(nil)
 			},
+			expectedOp: OperationCreated,
 		},
 		{
 			name: "existing monitor is updated",
@@ -58,15 +62,83 @@ func TestService_EnsureMonitor(t *testing.T) {
 					Name: "kube-system-foo",
 					URL:  "http://bar.baz",
 				}, nil)
+				p.On("Diff", mock.Anything, mock.Anything).Return(models.FieldDiffs{
+					{Field: "URL", Current: "http://bar.baz", Desired: "http://foo.bar.baz"},
+				}, nil)
 				p.On("Update", &models.Monitor{
-					ID:   "123",
-					URL:  "http://foo.bar.baz",
-					Name: "kube-system-foo",
+					ID:        "123",
+					URL:       "http://foo.bar.baz",
+					Name:      "kube-system-foo",
+					Namespace: "kube-system",
 					Annotations: config.Annotations{
 						config.AnnotationEnabled: "true",
 					},
 				}).Return(nil)
 			},
+			expectedOp: OperationUpdated,
+			expectedID: "123",
+		},
+		{
+			name: "existing monitor is not updated if it is up to date",
+			source: models.MonitorSource{
+				Name:      "foo",
+				Namespace: "kube-system",
+				URL:       "http://foo.bar.baz",
+			},
+			setup: func(p *fake.Provider) {
+				current := &models.Monitor{
+					ID:   "123",
+					Name: "kube-system-foo",
+					URL:  "http://foo.bar.baz",
+				}
+				p.On("Get", "kube-system-foo").Return(current, nil)
+				p.On("Diff", current, &models.Monitor{
+					ID:        "123",
+					URL:       "http://foo.bar.baz",
+					Name:      "kube-system-foo",
+					Namespace: "kube-system",
+				}).Return(nil, nil)
+			},
+			validate: func(t *testing.T, p *fake.Provider) {
+				p.AssertNotCalled(t, "Update", mock.Anything)
+			},
+			expectedOp: OperationNone,
+			expectedID: "123",
+		},
+		{
+			name: "does not update monitor if diff fails",
+			source: models.MonitorSource{
+				Name:      "foo",
+				Namespace: "kube-system",
+				URL:       "http://foo.bar.baz",
+			},
+			setup: func(p *fake.Provider) {
+				p.On("Get", "kube-system-foo").Return(&models.Monitor{ID: "123", Name: "kube-system-foo"}, nil)
+				p.On("Diff", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+			},
+			validate: func(t *testing.T, p *fake.Provider) {
+				p.AssertNotCalled(t, "Update", mock.Anything)
+			},
+			expected: errors.New("error"),
+		},
+		{
+			name: "uses multi host name template for sources with host",
+			source: models.MonitorSource{
+				Name:      "foo",
+				Namespace: "kube-system",
+				URL:       "http://foo.bar.baz/api",
+				Host:      "foo.bar.baz",
+				Path:      "/api",
+			},
+			setup: func(p *fake.Provider) {
+				p.On("Get", "kube-system-foo-foo.bar.baz/api").Return(nil, models.ErrMonitorNotFound)
+				p.On("Create", &models.Monitor{
+					URL:       "http://foo.bar.baz/api",
+					Name:      "kube-system-foo-foo.bar.baz/api",
+					Namespace: "kube-system",
+				}).Return(nil)
+			},
+			expectedOp: OperationCreated,
 		},
 		{
 			name: "does not create/update monitor if lookup fails",
@@ -97,12 +169,14 @@ func TestService_EnsureMonitor(t *testing.T) {
 				test.setup(provider)
 			}
 
-			err := svc.EnsureMonitor(test.source)
+			result, err := svc.EnsureMonitor(test.source)
 			if test.expected != nil {
 				require.Error(t, err)
 				assert.Equal(t, test.expected.Error(), err.Error())
 			} else {
-				assert.NoError(t, err)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOp, result.Operation)
+				assert.Equal(t, test.expectedID, result.Monitor.ID)
 			}
 
 			if test.validate != nil {
@@ -112,14 +186,88 @@ func TestService_EnsureMonitor(t *testing.T) {
 	}
 }
 
+// validatorFunc implements provider.Validator.
+type validatorFunc func(model *models.Monitor) error
+
+func (f validatorFunc) Validate(model *models.Monitor) error {
+	return f(model)
+}
+
+func TestService_EnsureMonitor_Validation(t *testing.T) {
+	svc, provider := newTestService(t, &config.Options{})
+
+	svc.providers[0].validator = validatorFunc(func(model *models.Monitor) error {
+		if model.Annotations["timeout"] == "abc" {
+			return errors.New("invalid timeout")
+		}
+
+		return nil
+	})
+
+	provider.On("Get", "kube-system-foo").Return(nil, models.ErrMonitorNotFound)
+	provider.On("Create", mock.Anything).Return(nil)
+
+	source := models.MonitorSource{
+		Name:        "foo",
+		Namespace:   "kube-system",
+		Annotations: map[string]string{"timeout": "abc"},
+		URL:         "http://foo.bar.baz",
+	}
+
+	_, err := svc.EnsureMonitor(source)
+	require.Error(t, err)
+	assert.Equal(t, "invalid timeout", err.Error())
+	assert.True(t, models.IsPermanent(err))
+	provider.AssertNotCalled(t, "Get", mock.Anything)
+
+	source.Annotations["timeout"] = "10"
+
+	result, err := svc.EnsureMonitor(source)
+	require.NoError(t, err)
+	assert.Equal(t, OperationCreated, result.Operation)
+}
+
+func TestService_EnsureMonitor_GlobalValidation(t *testing.T) {
+	svc, provider := newTestService(t, &config.Options{})
+
+	source := models.MonitorSource{
+		Name:      "foo",
+		Namespace: "kube-system",
+		Annotations: map[string]string{
+			config.AnnotationForceHTTPS:         "yes",
+			"ingress-monitor.bonial.com/timout": "10",
+		},
+		URL: "http://foo.bar.baz",
+	}
+
+	// Global annotations are validated even if the provider does not
+	// implement provider.Validator.
+	_, err := svc.EnsureMonitor(source)
+	require.Error(t, err)
+	assert.Equal(t, `annotation "ingress-monitor.bonial.com/force-https": invalid bool "yes"; annotation "ingress-monitor.bonial.com/timout": unknown annotation`, err.Error())
+	assert.True(t, models.IsPermanent(err))
+	provider.AssertNotCalled(t, "Get", mock.Anything)
+
+	svc.providers[0].validator = validatorFunc(func(model *models.Monitor) error {
+		return config.AnnotationErrors{{Name: "timeout", Reason: "invalid"}}
+	})
+
+	// Violations of the provider validator are reported together with the
+	// global ones.
+	_, err = svc.EnsureMonitor(source)
+	require.Error(t, err)
+	assert.Equal(t, `annotation "ingress-monitor.bonial.com/force-https": invalid bool "yes"; annotation "ingress-monitor.bonial.com/timout": unknown annotation; annotation "timeout": invalid`, err.Error())
+}
+
 func TestService_DeleteMonitor(t *testing.T) {
 	tests := []struct {
-		name     string
-		source   models.MonitorSource
-		options  config.Options
-		setup    func(*fake.Provider)
-		validate func(*testing.T, *fake.Provider)
-		expected error
+		name       string
+		source     models.MonitorSource
+		options    config.Options
+		setup      func(*fake.Provider)
+		validate   func(*testing.T, *fake.Provider)
+		expectedOp Operation
+		expected   error
 	}{
 		{
 			name: "delete monitor for source",
@@ -133,6 +281,7 @@ func TestService_DeleteMonitor(t *testing.T) {
 			validate: func(t *testing.T, p *fake.Provider) {
 				p.AssertCalled(t, "Delete", "kube-system-foo")
 			},
+			expectedOp: OperationDeleted,
 		},
 		{
 			name: "deletion of nonexistant monitor does not error",
@@ -146,6 +295,7 @@ func TestService_DeleteMonitor(t *testing.T) {
 			validate: func(t *testing.T, p *fake.Provider) {
 				p.AssertCalled(t, "Delete", "kube-system-foo")
 			},
+			expectedOp: OperationNone,
 		},
 		{
 			name:    "no deletions if NoDelete options is set",
@@ -157,6 +307,7 @@ func TestService_DeleteMonitor(t *testing.T) {
 			validate: func(t *testing.T, p *fake.Provider) {
 				p.AssertNotCalled(t, "Delete", mock.Anything)
 			},
+			expectedOp: OperationNone,
 		},
 	}
 
@@ -168,12 +319,117 @@ func TestService_DeleteMonitor(t *testing.T) {
 				test.setup(provider)
 			}
 
-			err := svc.DeleteMonitor(test.source)
+			result, err := svc.DeleteMonitor(test.source)
 			if test.expected != nil {
 				require.Error(t, err)
 				assert.Equal(t, test.expected.Error(), err.Error())
 			} else {
-				assert.NoError(t, err)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOp, result.Operation)
+				assert.Equal(t, "kube-system-foo", result.Monitor.Name)
+			}
+
+			if test.validate != nil {
+				test.validate(t, provider)
+			}
+		})
+	}
+}
+
+func TestService_DeleteOrphanedMonitors(t *testing.T) {
+	sources := []models.MonitorSource{
+		{Name: "foo", Namespace: "kube-system"},
+		{Name: "bar", Namespace: "default"},
+	}
+
+	monitors := []*models.Monitor{
+		{Name: "kube-system-foo"},
+		{Name: "default-bar"},
+		{Name: "default-baz"},
+		{Name: "other-qux"},
+	}
+
+	tests := []struct {
+		name     string
+		options  config.Options
+		setup    func(*fake.Provider)
+		validate func(*testing.T, *fake.Provider)
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:    "deletes monitors without source",
+			options: config.Options{GCNamePrefix: "default-"},
+			setup: func(p *fake.Provider) {
+				p.On("List").Return(monitors, nil)
+				p.On("Delete", "default-baz").Return(nil)
+			},
+			validate: func(t *testing.T, p *fake.Provider) {
+				p.AssertNumberOfCalls(t, "Delete", 1)
+			},
+			expected: []string{"default-baz"},
+		},
+		{
+			name: "empty prefix matches all monitors",
+			setup: func(p *fake.Provider) {
+				p.On("List").Return(monitors, nil)
+				p.On("Delete", "default-baz").Return(nil)
+				p.On("Delete", "other-qux").Return(nil)
+			},
+			validate: func(t *testing.T, p *fake.Provider) {
+				p.AssertNumberOfCalls(t, "Delete", 2)
+			},
+			expected: []string{"default-baz", "other-qux"},
+		},
+		{
+			name:    "dry run only reports orphaned monitors",
+			options: config.Options{GCNamePrefix: "default-", GCDryRun: true},
+			setup: func(p *fake.Provider) {
+				p.On("List").Return(monitors, nil)
+			},
+			validate: func(t *testing.T, p *fake.Provider) {
+				p.AssertNotCalled(t, "Delete", mock.Anything)
+			},
+			expected: []string{"default-baz"},
+		},
+		{
+			name:    "no deletions if NoDelete options is set",
+			options: config.Options{GCNamePrefix: "default-", NoDelete: true},
+			setup: func(p *fake.Provider) {
+				p.On("List").Return(monitors, nil)
+			},
+			validate: func(t *testing.T, p *fake.Provider) {
+				p.AssertNotCalled(t, "Delete", mock.Anything)
+			},
+			expected: []string{"default-baz"},
+		},
+		{
+			name:    "list errors are returned",
+			options: config.Options{GCNamePrefix: "default-"},
+			setup: func(p *fake.Provider) {
+				p.On("List").Return(nil, errors.New("whoops"))
+			},
+			validate: func(t *testing.T, p *fake.Provider) {
+				p.AssertNotCalled(t, "Delete", mock.Anything)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, provider := newTestService(t, &test.options)
+
+			if test.setup != nil {
+				test.setup(provider)
+			}
+
+			orphans, err := svc.DeleteOrphanedMonitors(func() ([]models.MonitorSource, error) { return sources, nil })
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, orphans)
 			}
 
 			if test.validate != nil {
@@ -202,8 +458,9 @@ func TestService_GetProviderIPSourceRanges(t *testing.T) {
 			expected: []string{"1.2.3.4/32", "1.3.3.7/32"},
 			setup: func(p *fake.Provider) {
 				p.On("GetIPSourceRanges", &models.Monitor{
-					Name: "kube-system-foo",
-					URL:  "http://foo.bar.baz",
+					Name:      "kube-system-foo",
+					Namespace: "kube-system",
+					URL:       "http://foo.bar.baz",
 				}).Return([]string{"1.2.3.4/32", "1.3.3.7/32"}, nil)
 			},
 		},
@@ -228,19 +485,274 @@ func TestService_GetProviderIPSourceRanges(t *testing.T) {
 	}
 }
 
+func TestService_EnsureMonitor_MultipleProviders(t *testing.T) {
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		setup             func(foo, bar *fake.Provider)
+		validate          func(t *testing.T, foo, bar *fake.Provider)
+		expectedOp        Operation
+		expectedProviders []string
+		expected          error
+	}{
+		{
+			name: "uses default providers without annotation",
+			setup: func(foo, bar *fake.Provider) {
+				foo.On("Get", "kube-system-foo").Return(nil, models.ErrMonitorNotFound)
+				foo.On("Create", mock.Anything).Return(nil)
+			},
+			validate: func(t *testing.T, foo, bar *fake.Provider) {
+				bar.AssertNotCalled(t, "Get", mock.Anything)
+			},
+			expectedOp:        OperationCreated,
+			expectedProviders: []string{"foo"},
+		},
+		{
+			name:        "fans out to providers selected via annotation",
+			annotations: map[string]string{config.AnnotationProviders: "foo, bar"},
+			setup: func(foo, bar *fake.Provider) {
+				current := &models.Monitor{ID: "123", Name: "kube-system-foo", URL: "http://foo.bar.baz"}
+				foo.On("Get", "kube-system-foo").Return(current, nil)
+				foo.On("Diff", current, mock.Anything).Return(nil, nil)
+				bar.On("Get", "kube-system-foo").Return(nil, models.ErrMonitorNotFound)
+				bar.On("Create", mock.Anything).Return(nil)
+			},
+			expectedOp:        OperationCreated,
+			expectedProviders: []string{"foo", "bar"},
+		},
+		{
+			name:        "non-default provider can be selected exclusively",
+			annotations: map[string]string{config.AnnotationProviders: "bar"},
+			setup: func(foo, bar *fake.Provider) {
+				bar.On("Get", "kube-system-foo").Return(nil, models.ErrMonitorNotFound)
+				bar.On("Create", mock.Anything).Return(nil)
+			},
+			validate: func(t *testing.T, foo, bar *fake.Provider) {
+				foo.AssertNotCalled(t, "Get", mock.Anything)
+			},
+			expectedOp:        OperationCreated,
+			expectedProviders: []string{"bar"},
+		},
+		{
+			name:        "selecting a provider that is not enabled is an error",
+			annotations: map[string]string{config.AnnotationProviders: "foo,baz"},
+			validate: func(t *testing.T, foo, bar *fake.Provider) {
+				foo.AssertNotCalled(t, "Get", mock.Anything)
+				bar.AssertNotCalled(t, "Get", mock.Anything)
+			},
+			expected: errors.New(`provider "baz" selected via annotation "ingress-monitor.bonial.com/providers" is not enabled`),
+		},
+		{
+			name:        "annotation without provider names is an error",
+			annotations: map[string]string{config.AnnotationProviders: ","},
+			validate: func(t *testing.T, foo, bar *fake.Provider) {
+				foo.AssertNotCalled(t, "Get", mock.Anything)
+				bar.AssertNotCalled(t, "Get", mock.Anything)
+			},
+			expected: errors.New(`annotation "ingress-monitor.bonial.com/providers" does not select any provider`),
+		},
+		{
+			name:        "failing provider does not prevent others from syncing",
+			annotations: map[string]string{config.AnnotationProviders: "foo,bar"},
+			setup: func(foo, bar *fake.Provider) {
+				foo.On("Get", "kube-system-foo").Return(nil, errors.New("whoops"))
+				bar.On("Get", "kube-system-foo").Return(nil, models.ErrMonitorNotFound)
+				bar.On("Create", mock.Anything).Return(nil)
+			},
+			expectedOp:        OperationCreated,
+			expectedProviders: []string{"foo", "bar"},
+			expected:          errors.New(`provider "foo": whoops`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, foo, bar := newMultiProviderTestService(t, &config.Options{})
+
+			if test.setup != nil {
+				test.setup(foo, bar)
+			}
+
+			result, err := svc.EnsureMonitor(models.MonitorSource{
+				Name:        "foo",
+				Namespace:   "kube-system",
+				URL:         "http://foo.bar.baz",
+				Annotations: test.annotations,
+			})
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			if test.expectedProviders != nil {
+				require.NotNil(t, result)
+				assert.Equal(t, test.expectedOp, result.Operation)
+				assert.Equal(t, "kube-system-foo", result.Monitor.Name)
+
+				providers := make([]string, 0, len(result.Providers))
+				for _, providerResult := range result.Providers {
+					providers = append(providers, providerResult.Provider)
+				}
+
+				assert.Equal(t, test.expectedProviders, providers)
+			}
+
+			if test.validate != nil {
+				test.validate(t, foo, bar)
+			}
+		})
+	}
+}
+
+func TestService_DeleteMonitor_MultipleProviders(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		setup       func(foo, bar *fake.Provider)
+		validate    func(t *testing.T, foo, bar *fake.Provider)
+		expectedOp  Operation
+	}{
+		{
+			name: "deletes from all enabled providers without annotation",
+			setup: func(foo, bar *fake.Provider) {
+				foo.On("Delete", "kube-system-foo").Return(models.ErrMonitorNotFound)
+				bar.On("Delete", "kube-system-foo").Return(nil)
+			},
+			expectedOp: OperationDeleted,
+		},
+		{
+			name:        "deletes from providers selected via annotation",
+			annotations: map[string]string{config.AnnotationProviders: "foo"},
+			setup: func(foo, bar *fake.Provider) {
+				foo.On("Delete", "kube-system-foo").Return(nil)
+			},
+			validate: func(t *testing.T, foo, bar *fake.Provider) {
+				bar.AssertNotCalled(t, "Delete", mock.Anything)
+			},
+			expectedOp: OperationDeleted,
+		},
+		{
+			name:        "deletes from all enabled providers if selection is invalid",
+			annotations: map[string]string{config.AnnotationProviders: "baz"},
+			setup: func(foo, bar *fake.Provider) {
+				foo.On("Delete", "kube-system-foo").Return(models.ErrMonitorNotFound)
+				bar.On("Delete", "kube-system-foo").Return(models.ErrMonitorNotFound)
+			},
+			expectedOp: OperationNone,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, foo, bar := newMultiProviderTestService(t, &config.Options{})
+
+			if test.setup != nil {
+				test.setup(foo, bar)
+			}
+
+			result, err := svc.DeleteMonitor(models.MonitorSource{
+				Name:        "foo",
+				Namespace:   "kube-system",
+				Annotations: test.annotations,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedOp, result.Operation)
+
+			foo.AssertExpectations(t)
+			bar.AssertExpectations(t)
+
+			if test.validate != nil {
+				test.validate(t, foo, bar)
+			}
+		})
+	}
+}
+
+func TestService_DeleteOrphanedMonitors_MultipleProviders(t *testing.T) {
+	svc, foo, bar := newMultiProviderTestService(t, &config.Options{})
+
+	sources := []models.MonitorSource{
+		{Name: "foo", Namespace: "kube-system"},
+		{Name: "bar", Namespace: "default", Annotations: map[string]string{config.AnnotationProviders: "bar"}},
+	}
+
+	foo.On("List").Return([]*models.Monitor{{Name: "kube-system-foo"}, {Name: "default-bar"}}, nil)
+	foo.On("Delete", "default-bar").Return(nil)
+	bar.On("List").Return([]*models.Monitor{{Name: "kube-system-foo"}, {Name: "default-bar"}}, nil)
+	bar.On("Delete", "kube-system-foo").Return(nil)
+
+	orphans, err := svc.DeleteOrphanedMonitors(func() ([]models.MonitorSource, error) { return sources, nil })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default-bar", "kube-system-foo"}, orphans)
+
+	foo.AssertExpectations(t)
+	bar.AssertExpectations(t)
+}
+
+func TestService_DeleteOrphanedMonitors_ListsMonitorsFirst(t *testing.T) {
+	svc, provider := newTestService(t, &config.Options{})
+
+	provider.On("List").Return([]*models.Monitor{{Name: "default-foo"}}, nil)
+
+	// A monitor that is created for a new source after the monitors were
+	// listed must not be deleted.
+	orphans, err := svc.DeleteOrphanedMonitors(func() ([]models.MonitorSource, error) {
+		provider.AssertCalled(t, "List")
+
+		return []models.MonitorSource{
+			{Name: "foo", Namespace: "default"},
+			{Name: "bar", Namespace: "default"},
+		}, nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+	provider.AssertNotCalled(t, "Delete", mock.Anything)
+
+	_, err = svc.DeleteOrphanedMonitors(func() ([]models.MonitorSource, error) {
+		return nil, errors.New("whoops")
+	})
+	require.EqualError(t, err, "whoops")
+	provider.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
 func newTestService(t *testing.T, options *config.Options) (*service, *fake.Provider) {
 	namer, err := NewNamer("{{.Namespace}}-{{.IngressName}}")
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	multiHostNamer, err := NewNamer("{{.Namespace}}-{{.IngressName}}-{{.Host}}{{.Path}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	provider := &fake.Provider{}
 
 	svc := &service{
-		provider: provider,
-		namer:    namer,
-		options:  options,
+		providers:        []namedProvider{{Interface: provider, name: "fake"}},
+		defaultProviders: []string{"fake"},
+		namer:            namer,
+		multiHostNamer:   multiHostNamer,
+		options:          options,
 	}
 
 	return svc, provider
 }
+
+// newMultiProviderTestService creates a service with the enabled providers
+// "foo" and "bar", of which only "foo" is a default provider.
+func newMultiProviderTestService(t *testing.T, options *config.Options) (*service, *fake.Provider, *fake.Provider) {
+	svc, foo := newTestService(t, options)
+
+	bar := &fake.Provider{}
+
+	svc.providers = []namedProvider{
+		{Interface: foo, name: "foo"},
+		{Interface: bar, name: "bar"},
+	}
+	svc.defaultProviders = []string{"foo"}
+
+	return svc, foo, bar
+}
@@ -0,0 +1,66 @@
+// Package monitorresource builds monitor sources from Monitor resources,
+// which describe monitors for endpoints that are not backed by an Ingress or
+// HTTPRoute.
+package monitorresource
+
+import (
+	"net/url"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/policy"
+	"github.com/pkg/errors"
+)
+
+// Kind is the kind of the Monitor resource.
+const Kind = "Monitor"
+
+// Validate checks if the monitor fulfills all criteria for monitoring and
+// returns an error on any violation. The URL of the monitor must be an
+// absolute http or https URL.
+func Validate(monitor *v1alpha1.Monitor) error {
+	u, err := url.Parse(monitor.Spec.URL)
+	if err != nil {
+		return errors.Wrapf(err, "monitor URL %q is invalid", monitor.Spec.URL)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("monitor URL %q must use the http or https scheme", monitor.Spec.URL)
+	}
+
+	if u.Host == "" {
+		return errors.Errorf("monitor URL %q does not have a host", monitor.Spec.URL)
+	}
+
+	return nil
+}
+
+// NewMonitorSource creates a MonitorSource from a Monitor resource. The
+// provider settings of the spec are passed to the monitor service as
+// annotations, just like the annotations of an Ingress or HTTPRoute. The
+// monitor must have been validated before calling this function.
+func NewMonitorSource(monitor *v1alpha1.Monitor) models.MonitorSource {
+	source := DeleteSource(monitor)
+	source.URL = monitor.Spec.URL
+
+	return source
+}
+
+// DeleteSource creates a MonitorSource without URL for a Monitor resource.
+// It is sufficient to derive the name of the monitor, e.g. to delete it, and
+// in contrast to NewMonitorSource, the monitor does not need to be valid.
+func DeleteSource(monitor *v1alpha1.Monitor) models.MonitorSource {
+	// MonitorSpec shares its provider settings with MonitorPolicySpec, so
+	// the conversion into annotations can be reused.
+	annotations := policy.Annotations(v1alpha1.MonitorPolicySpec{
+		Site24x7:    monitor.Spec.Site24x7,
+		Annotations: monitor.Spec.Annotations,
+	})
+
+	return models.MonitorSource{
+		Kind:        Kind,
+		Name:        monitor.Name,
+		Namespace:   monitor.Namespace,
+		Annotations: annotations,
+	}
+}
@@ -0,0 +1,96 @@
+package monitorresource
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected error
+	}{
+		{
+			name: "valid http URL",
+			url:  "http://foo.example.com/health",
+		},
+		{
+			name: "valid https URL",
+			url:  "https://foo.example.com:8443",
+		},
+		{
+			name:     "URL without scheme",
+			url:      "foo.example.com",
+			expected: errors.New(`monitor URL "foo.example.com" must use the http or https scheme`),
+		},
+		{
+			name:     "unsupported scheme",
+			url:      "tcp://foo.example.com:1234",
+			expected: errors.New(`monitor URL "tcp://foo.example.com:1234" must use the http or https scheme`),
+		},
+		{
+			name:     "URL without host",
+			url:      "https:///health",
+			expected: errors.New(`monitor URL "https:///health" does not have a host`),
+		},
+		{
+			name:     "unparsable URL",
+			url:      "http://foo example.com",
+			expected: errors.New(`monitor URL "http://foo example.com" is invalid: parse "http://foo example.com": invalid character " " in host name`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(&v1alpha1.Monitor{Spec: v1alpha1.MonitorSpec{URL: test.url}})
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewMonitorSource(t *testing.T) {
+	monitor := &v1alpha1.Monitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.MonitorSpec{
+			URL: "https://foo.example.com",
+			Site24x7: &v1alpha1.Site24x7Settings{
+				CheckFrequency: "5",
+				UserGroupIDs:   []string{"1", "2"},
+			},
+			Annotations: map[string]string{
+				config.AnnotationSite24x7CheckFrequency: "10",
+				config.AnnotationUptimeKumaInterval:     "60",
+			},
+		},
+	}
+
+	expected := models.MonitorSource{
+		Kind:      "Monitor",
+		Name:      "foo",
+		Namespace: "default",
+		Annotations: map[string]string{
+			config.AnnotationSite24x7CheckFrequency: "5",
+			config.AnnotationSite24x7UserGroupIDs:   "1,2",
+			config.AnnotationUptimeKumaInterval:     "60",
+		},
+		URL: "https://foo.example.com",
+	}
+
+	assert.Equal(t, expected, NewMonitorSource(monitor))
+}
@@ -0,0 +1,171 @@
+// Package policy resolves the MonitorPolicies and ClusterMonitorPolicies that
+// apply to a resource and merges their defaults into the resource's
+// annotations.
+package policy
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// annotationIngressClass is the legacy annotation for the class of an
+// Ingress, which is still widely used instead of spec.ingressClassName.
+const annotationIngressClass = "kubernetes.io/ingress.class"
+
+// Resolver resolves the policies that apply to a resource.
+type Resolver struct {
+	client client.Reader
+}
+
+// NewResolver creates a new *Resolver which reads policies using client.
+func NewResolver(client client.Reader) *Resolver {
+	return &Resolver{client: client}
+}
+
+// Apply returns a copy of obj whose annotations contain the defaults of all
+// policies that apply to obj. Annotations that are already present on obj
+// take precedence over the policy defaults. If r is nil, obj is returned
+// unchanged.
+func Apply[T client.Object](ctx context.Context, r *Resolver, obj T) (T, error) {
+	if r == nil {
+		return obj, nil
+	}
+
+	defaults, err := r.Defaults(ctx, obj)
+	if err != nil || len(defaults) == 0 {
+		return obj, err
+	}
+
+	annotations := defaults
+	maps.Copy(annotations, obj.GetAnnotations())
+
+	obj = obj.DeepCopyObject().(T)
+	obj.SetAnnotations(annotations)
+
+	return obj, nil
+}
+
+// Defaults returns the merged annotation defaults of all policies that apply
+// to obj. ClusterMonitorPolicies are applied before MonitorPolicies, so that
+// the latter override the former. Within each kind, policies without
+// IngressClassNames are applied before policies that select the class of
+// obj, and policies of equal specificity are applied in the alphabetical
+// order of their names.
+func (r *Resolver) Defaults(ctx context.Context, obj client.Object) (map[string]string, error) {
+	class := ingressClass(obj)
+
+	clusterPolicies := &v1alpha1.ClusterMonitorPolicyList{}
+
+	err := r.client.List(ctx, clusterPolicies)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list cluster monitor policies")
+	}
+
+	policies := &v1alpha1.MonitorPolicyList{}
+
+	err = r.client.List(ctx, policies, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list monitor policies in namespace %s", obj.GetNamespace())
+	}
+
+	var clusterSpecs, specs []namedSpec
+
+	for _, policy := range clusterPolicies.Items {
+		clusterSpecs = append(clusterSpecs, namedSpec{name: policy.Name, spec: policy.Spec})
+	}
+
+	for _, policy := range policies.Items {
+		specs = append(specs, namedSpec{name: policy.Name, spec: policy.Spec})
+	}
+
+	defaults := make(map[string]string)
+
+	for _, spec := range append(matching(clusterSpecs, class), matching(specs, class)...) {
+		maps.Copy(defaults, Annotations(spec))
+	}
+
+	return defaults, nil
+}
+
+// Annotations converts the defaults of spec into annotations.
+func Annotations(spec v1alpha1.MonitorPolicySpec) map[string]string {
+	annotations := make(map[string]string, len(spec.Annotations)+1)
+
+	maps.Copy(annotations, spec.Annotations)
+
+	setIfNotEmpty(annotations, config.AnnotationPathOverride, spec.PathOverride)
+
+	if s := spec.Site24x7; s != nil {
+		setIfNotEmpty(annotations, config.AnnotationSite24x7CheckFrequency, s.CheckFrequency)
+		setIfNotEmpty(annotations, config.AnnotationSite24x7LocationProfileID, s.LocationProfileID)
+		setIfNotEmpty(annotations, config.AnnotationSite24x7NotificationProfileID, s.NotificationProfileID)
+		setIfNotEmpty(annotations, config.AnnotationSite24x7ThresholdProfileID, s.ThresholdProfileID)
+		setIfNotEmpty(annotations, config.AnnotationSite24x7MonitorGroupIDs, strings.Join(s.MonitorGroupIDs, ","))
+		setIfNotEmpty(annotations, config.AnnotationSite24x7UserGroupIDs, strings.Join(s.UserGroupIDs, ","))
+	}
+
+	return annotations
+}
+
+type namedSpec struct {
+	name string
+	spec v1alpha1.MonitorPolicySpec
+}
+
+// matching returns the specs that apply to resources of class in the order
+// in which they have to be applied.
+func matching(specs []namedSpec, class string) []v1alpha1.MonitorPolicySpec {
+	var generic, specific []namedSpec
+
+	for _, s := range specs {
+		if len(s.spec.IngressClassNames) == 0 {
+			generic = append(generic, s)
+		} else if class != "" && slices.Contains(s.spec.IngressClassNames, class) {
+			specific = append(specific, s)
+		}
+	}
+
+	byName := func(s []namedSpec) func(i, j int) bool {
+		return func(i, j int) bool { return s[i].name < s[j].name }
+	}
+
+	sort.Slice(generic, byName(generic))
+	sort.Slice(specific, byName(specific))
+
+	result := make([]v1alpha1.MonitorPolicySpec, 0, len(generic)+len(specific))
+
+	for _, s := range append(generic, specific...) {
+		result = append(result, s.spec)
+	}
+
+	return result
+}
+
+// ingressClass returns the class of obj if it is an Ingress.
+func ingressClass(obj client.Object) string {
+	ing, ok := obj.(*networkingv1.Ingress)
+	if !ok {
+		return ""
+	}
+
+	if ing.Spec.IngressClassName != nil {
+		return *ing.Spec.IngressClassName
+	}
+
+	return ing.Annotations[annotationIngressClass]
+}
+
+func setIfNotEmpty(annotations map[string]string, key, value string) {
+	if value != "" {
+		annotations[key] = value
+	}
+}
@@ -0,0 +1,192 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/apis/v1alpha1"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := fakeclient.NewClientBuilder().Build().Scheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	return fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestApply(t *testing.T) {
+	internal := "internal"
+
+	clusterPolicies := []client.Object{
+		&v1alpha1.ClusterMonitorPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "b-global"},
+			Spec: v1alpha1.MonitorPolicySpec{
+				PathOverride: "/cluster-b",
+				Site24x7:     &v1alpha1.Site24x7Settings{CheckFrequency: "5"},
+			},
+		},
+		&v1alpha1.ClusterMonitorPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "a-global"},
+			Spec: v1alpha1.MonitorPolicySpec{
+				PathOverride: "/cluster-a",
+				Site24x7:     &v1alpha1.Site24x7Settings{LocationProfileID: "123"},
+			},
+		},
+		&v1alpha1.ClusterMonitorPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "0-internal"},
+			Spec: v1alpha1.MonitorPolicySpec{
+				IngressClassNames: []string{"internal"},
+				Site24x7:          &v1alpha1.Site24x7Settings{CheckFrequency: "1"},
+			},
+		},
+	}
+
+	namespacePolicy := &v1alpha1.MonitorPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "team"},
+		Spec: v1alpha1.MonitorPolicySpec{
+			PathOverride: "/health",
+			Annotations:  map[string]string{config.AnnotationForceHTTPS: "true"},
+		},
+	}
+
+	otherNamespacePolicy := &v1alpha1.MonitorPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "other"},
+		Spec:       v1alpha1.MonitorPolicySpec{PathOverride: "/other"},
+	}
+
+	tests := []struct {
+		name     string
+		objects  []client.Object
+		ingress  *networkingv1.Ingress
+		expected map[string]string
+	}{
+		{
+			name: "no policies",
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foo",
+					Namespace:   "team",
+					Annotations: map[string]string{config.AnnotationEnabled: "true"},
+				},
+			},
+			expected: map[string]string{config.AnnotationEnabled: "true"},
+		},
+		{
+			name:    "cluster policies are applied in alphabetical order",
+			objects: clusterPolicies,
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team"},
+			},
+			expected: map[string]string{
+				config.AnnotationPathOverride:              "/cluster-b",
+				config.AnnotationSite24x7CheckFrequency:    "5",
+				config.AnnotationSite24x7LocationProfileID: "123",
+			},
+		},
+		{
+			name:    "class specific policies override generic policies",
+			objects: clusterPolicies,
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team"},
+				Spec:       networkingv1.IngressSpec{IngressClassName: &internal},
+			},
+			expected: map[string]string{
+				config.AnnotationPathOverride:              "/cluster-b",
+				config.AnnotationSite24x7CheckFrequency:    "1",
+				config.AnnotationSite24x7LocationProfileID: "123",
+			},
+		},
+		{
+			name:    "ingress class annotation is respected",
+			objects: clusterPolicies,
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foo",
+					Namespace:   "team",
+					Annotations: map[string]string{annotationIngressClass: "internal"},
+				},
+			},
+			expected: map[string]string{
+				annotationIngressClass:                     "internal",
+				config.AnnotationPathOverride:              "/cluster-b",
+				config.AnnotationSite24x7CheckFrequency:    "1",
+				config.AnnotationSite24x7LocationProfileID: "123",
+			},
+		},
+		{
+			name:    "namespace policies override cluster policies and annotations override both",
+			objects: append([]client.Object{namespacePolicy, otherNamespacePolicy}, clusterPolicies...),
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foo",
+					Namespace:   "team",
+					Annotations: map[string]string{config.AnnotationSite24x7CheckFrequency: "10"},
+				},
+			},
+			expected: map[string]string{
+				config.AnnotationForceHTTPS:                "true",
+				config.AnnotationPathOverride:              "/health",
+				config.AnnotationSite24x7CheckFrequency:    "10",
+				config.AnnotationSite24x7LocationProfileID: "123",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewResolver(newFakeClient(test.objects...))
+
+			original := test.ingress.DeepCopy()
+
+			ing, err := Apply(context.Background(), r, test.ingress)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, ing.Annotations)
+			assert.Equal(t, original, test.ingress, "the original object must not be modified")
+		})
+	}
+}
+
+func TestApply_NilResolver(t *testing.T) {
+	ing := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "team"}}
+
+	result, err := Apply(context.Background(), nil, ing)
+	require.NoError(t, err)
+	assert.Same(t, ing, result)
+}
+
+func TestAnnotations(t *testing.T) {
+	spec := v1alpha1.MonitorPolicySpec{
+		PathOverride: "/health",
+		Site24x7: &v1alpha1.Site24x7Settings{
+			CheckFrequency:        "5",
+			NotificationProfileID: "456",
+			ThresholdProfileID:    "789",
+			MonitorGroupIDs:       []string{"1", "2"},
+			UserGroupIDs:          []string{"3"},
+		},
+		Annotations: map[string]string{
+			config.AnnotationPathOverride: "/ignored",
+			config.AnnotationMultiHost:    "true",
+		},
+	}
+
+	expected := map[string]string{
+		config.AnnotationPathOverride:                  "/health",
+		config.AnnotationMultiHost:                     "true",
+		config.AnnotationSite24x7CheckFrequency:        "5",
+		config.AnnotationSite24x7NotificationProfileID: "456",
+		config.AnnotationSite24x7ThresholdProfileID:    "789",
+		config.AnnotationSite24x7MonitorGroupIDs:       "1,2",
+		config.AnnotationSite24x7UserGroupIDs:          "3",
+	}
+
+	assert.Equal(t, expected, Annotations(spec))
+}
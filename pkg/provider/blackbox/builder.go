@@ -0,0 +1,118 @@
+package blackbox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxObjectNameLength leaves room for the ".json" extension of ConfigMap
+	// keys, which share the length limit of object names.
+	maxObjectNameLength = 253 - len(".json")
+
+	// objectNameHashLength is the number of hex characters of the name hash
+	// that is appended to altered object names.
+	objectNameHashLength = 8
+)
+
+var invalidObjectNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// target is the provider internal representation of a blackbox_exporter
+// probe target.
+type target struct {
+	Name     string
+	URL      string
+	Module   string
+	Interval string
+	Labels   map[string]string
+}
+
+type builder struct {
+	defaults config.BlackboxMonitorDefaults
+}
+
+func newBuilder(defaults config.BlackboxMonitorDefaults) *builder {
+	return &builder{
+		defaults: defaults,
+	}
+}
+
+func (b *builder) FromModel(model *models.Monitor) (*target, error) {
+	anno := model.Annotations
+	defaults := b.defaults
+
+	t := &target{
+		Name:     model.Name,
+		URL:      model.URL,
+		Module:   defaults.Module,
+		Interval: anno.StringValue(config.AnnotationBlackboxInterval, defaults.Interval),
+		Labels:   make(map[string]string, len(defaults.Labels)),
+	}
+
+	// The grpc and tcp probers of the blackbox_exporter expect host:port
+	// targets instead of URLs.
+	if monitorType := model.TypeOrDefault(); monitorType != models.MonitorTypeHTTP {
+		t.Module = defaults.TLSModule
+		if monitorType == models.MonitorTypeGRPC {
+			t.Module = defaults.GRPCModule
+		}
+
+		address, err := model.Address()
+		if err != nil {
+			return nil, models.InvalidConfig(errors.Wrapf(err, "invalid monitor url %q", model.URL))
+		}
+
+		t.URL = address
+	}
+
+	t.Module = anno.StringValue(config.AnnotationBlackboxModule, t.Module)
+
+	for key, value := range defaults.Labels {
+		t.Labels[key] = value
+	}
+
+	for _, pair := range anno.StringSliceValue(config.AnnotationBlackboxLabels) {
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, models.InvalidConfig(errors.Errorf("invalid label %q in annotation %q, expected key=value", pair, config.AnnotationBlackboxLabels))
+		}
+
+		t.Labels[key] = strings.TrimSpace(value)
+	}
+
+	return t, nil
+}
+
+// objectName converts a monitor name into a valid name for a Kubernetes
+// object. The result is also a valid ConfigMap key. If the name has to be
+// altered, a short hash of the original name is appended, so that distinct
+// monitor names like "foo_bar" and "foo-bar" do not map to the same object.
+func objectName(name string) string {
+	sanitized := invalidObjectNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	sanitized = strings.Trim(sanitized, ".-")
+
+	if sanitized == name && len(name) <= maxObjectNameLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:objectNameHashLength]
+
+	maxLength := maxObjectNameLength - objectNameHashLength - 1
+	if len(sanitized) > maxLength {
+		sanitized = strings.TrimRight(sanitized[:maxLength], ".-")
+	}
+
+	if sanitized == "" {
+		return hash
+	}
+
+	return sanitized + "-" + hash
+}
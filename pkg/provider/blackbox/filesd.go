@@ -0,0 +1,252 @@
+package blackbox
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// labelModule and labelScrapeInterval are special labels that are
+	// interpreted by Prometheus. The former is passed as module URL parameter
+	// to the blackbox_exporter, the latter overrides the scrape interval.
+	labelModule         = "__param_module"
+	labelScrapeInterval = "__scrape_interval__"
+
+	// labelMonitorName records the name of the monitor, as the ConfigMap key
+	// may not be a valid monitor name. Labels prefixed with "__" are dropped
+	// by Prometheus after relabeling.
+	labelMonitorName = "__monitor_name__"
+)
+
+// targetGroup is a single entry of a file_sd target file.
+type targetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// fileSDBackend manages file_sd target files in a single ConfigMap. Each
+// monitor is stored under its own key so that the ConfigMap can be mounted
+// into Prometheus and referenced via a glob pattern like
+// /etc/prometheus/blackbox/*.json.
+type fileSDBackend struct {
+	client client.Client
+	key    types.NamespacedName
+}
+
+func (b *fileSDBackend) create(ctx context.Context, t *target) (string, error) {
+	configMap, err := b.getConfigMap(ctx)
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{}
+		configMap.Namespace = b.key.Namespace
+		configMap.Name = b.key.Name
+		configMap.Labels = map[string]string{labelManagedBy: managedByValue}
+
+		err = b.setTarget(configMap, t)
+		if err != nil {
+			return "", err
+		}
+
+		return configMapKey(t.Name), b.client.Create(ctx, configMap)
+	} else if err != nil {
+		return "", err
+	}
+
+	err = b.setTarget(configMap, t)
+	if err != nil {
+		return "", err
+	}
+
+	return configMapKey(t.Name), b.client.Update(ctx, configMap)
+}
+
+func (b *fileSDBackend) get(ctx context.Context, name string) (*models.Monitor, error) {
+	configMap, err := b.getConfigMap(ctx)
+	if apierrors.IsNotFound(err) {
+		return nil, models.ErrMonitorNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	key := configMapKey(name)
+
+	data, ok := configMap.Data[key]
+	if !ok {
+		return nil, models.ErrMonitorNotFound
+	}
+
+	monitor, err := b.targetMonitor(key, data)
+	if err != nil {
+		return nil, err
+	}
+
+	monitor.Name = name
+
+	return monitor, nil
+}
+
+func (b *fileSDBackend) list(ctx context.Context) ([]*models.Monitor, error) {
+	configMap, err := b.getConfigMap(ctx)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	monitors := make([]*models.Monitor, 0, len(configMap.Data))
+
+	for key, data := range configMap.Data {
+		monitor, err := b.targetMonitor(key, data)
+		if err != nil {
+			return nil, err
+		}
+
+		monitors = append(monitors, monitor)
+	}
+
+	sort.Slice(monitors, func(i, j int) bool {
+		return monitors[i].ID < monitors[j].ID
+	})
+
+	return monitors, nil
+}
+
+// targetMonitor converts the file_sd target stored under key into a monitor.
+// The monitor name is taken from the labelMonitorName label and falls back
+// to the key without its file extension.
+func (b *fileSDBackend) targetMonitor(key, data string) (*models.Monitor, error) {
+	var groups []targetGroup
+
+	err := json.Unmarshal([]byte(data), &groups)
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed file_sd target %q in configmap %s", key, b.key)
+	}
+
+	monitor := &models.Monitor{
+		ID:     key,
+		Name:   strings.TrimSuffix(key, ".json"),
+		Config: groups,
+	}
+
+	if len(groups) > 0 {
+		if name := groups[0].Labels[labelMonitorName]; name != "" {
+			monitor.Name = name
+		}
+
+		if len(groups[0].Targets) > 0 {
+			monitor.URL = groups[0].Targets[0]
+		}
+	}
+
+	return monitor, nil
+}
+
+// diff compares the existing file_sd target with the target group that would
+// be written for t.
+func (b *fileSDBackend) diff(current *models.Monitor, t *target) (models.FieldDiffs, error) {
+	groups, ok := current.Config.([]targetGroup)
+	if !ok || len(groups) != 1 {
+		return models.UnknownConfig(), nil
+	}
+
+	desired := newTargetGroup(t)
+
+	var diffs models.FieldDiffs
+
+	diffs.Compare("targets", groups[0].Targets, desired.Targets)
+	diffs.Compare("labels", groups[0].Labels, desired.Labels)
+
+	return diffs, nil
+}
+
+func (b *fileSDBackend) update(ctx context.Context, t *target) error {
+	configMap, err := b.getConfigMap(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = b.setTarget(configMap, t)
+	if err != nil {
+		return err
+	}
+
+	return b.client.Update(ctx, configMap)
+}
+
+func (b *fileSDBackend) delete(ctx context.Context, name string) error {
+	configMap, err := b.getConfigMap(ctx)
+	if apierrors.IsNotFound(err) {
+		return models.ErrMonitorNotFound
+	} else if err != nil {
+		return err
+	}
+
+	key := configMapKey(name)
+
+	if _, ok := configMap.Data[key]; !ok {
+		return models.ErrMonitorNotFound
+	}
+
+	delete(configMap.Data, key)
+
+	return b.client.Update(ctx, configMap)
+}
+
+func (b *fileSDBackend) getConfigMap(ctx context.Context) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{}
+
+	err := b.client.Get(ctx, b.key, configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return configMap, nil
+}
+
+func (b *fileSDBackend) setTarget(configMap *corev1.ConfigMap, t *target) error {
+	buf, err := json.Marshal([]targetGroup{newTargetGroup(t)})
+	if err != nil {
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+
+	configMap.Data[configMapKey(t.Name)] = string(buf)
+
+	return nil
+}
+
+// newTargetGroup converts t into a file_sd target group. The module, scrape
+// interval and monitor name are passed as special labels.
+func newTargetGroup(t *target) targetGroup {
+	labels := make(map[string]string, len(t.Labels)+3)
+	for key, value := range t.Labels {
+		labels[key] = value
+	}
+
+	labels[labelModule] = t.Module
+	labels[labelMonitorName] = t.Name
+
+	if t.Interval != "" {
+		labels[labelScrapeInterval] = t.Interval
+	}
+
+	return targetGroup{
+		Targets: []string{t.URL},
+		Labels:  labels,
+	}
+}
+
+func configMapKey(name string) string {
+	return objectName(name) + ".json"
+}
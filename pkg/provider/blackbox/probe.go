@@ -0,0 +1,250 @@
+package blackbox
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	labelManagedBy = "app.kubernetes.io/managed-by"
+	managedByValue = "ingress-monitor-controller"
+
+	// annotationMonitorName is used to find the Probe for a monitor, as the
+	// monitor name may not be a valid Kubernetes object name.
+	annotationMonitorName = "blackbox.ingress-monitor.bonial.com/monitor-name"
+)
+
+var probeGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "Probe",
+}
+
+// probeBackend manages Prometheus Operator Probe resources in a single
+// namespace. The Probes are handled as unstructured objects to avoid a
+// dependency on the Prometheus Operator API types.
+type probeBackend struct {
+	client    client.Client
+	namespace string
+	proberURL string
+	jobName   string
+}
+
+func (b *probeBackend) create(ctx context.Context, t *target) (string, error) {
+	probe := NewProbe()
+	probe.SetNamespace(b.namespace)
+	probe.SetName(objectName(t.Name))
+
+	err := b.setSpec(probe, t)
+	if err != nil {
+		return "", err
+	}
+
+	err = b.client.Create(ctx, probe)
+	if err != nil {
+		return "", err
+	}
+
+	return probeID(probe), nil
+}
+
+func (b *probeBackend) get(ctx context.Context, name string) (*models.Monitor, error) {
+	probe, err := b.find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return probeMonitor(probe)
+}
+
+func (b *probeBackend) list(ctx context.Context) ([]*models.Monitor, error) {
+	probes, err := b.listProbes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	monitors := make([]*models.Monitor, 0, len(probes))
+
+	for i := range probes {
+		monitor, err := probeMonitor(&probes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		monitors = append(monitors, monitor)
+	}
+
+	return monitors, nil
+}
+
+// diff compares the spec of the existing Probe with the spec that would be
+// written for t.
+func (b *probeBackend) diff(current *models.Monitor, t *target) (models.FieldDiffs, error) {
+	probe, ok := current.Config.(*unstructured.Unstructured)
+	if !ok {
+		return models.UnknownConfig(), nil
+	}
+
+	desired := probe.DeepCopy()
+
+	err := b.setSpec(desired, t)
+	if err != nil {
+		return nil, err
+	}
+
+	actualSpec, _, err := unstructured.NestedMap(probe.Object, "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed probe %s/%s", probe.GetNamespace(), probe.GetName())
+	}
+
+	desiredSpec, _, err := unstructured.NestedMap(desired.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs models.FieldDiffs
+
+	for _, key := range sets.List(sets.KeySet(actualSpec).Union(sets.KeySet(desiredSpec))) {
+		diffs.Compare("spec."+key, actualSpec[key], desiredSpec[key])
+	}
+
+	return diffs, nil
+}
+
+func (b *probeBackend) update(ctx context.Context, t *target) error {
+	probe, err := b.find(ctx, t.Name)
+	if err != nil {
+		return err
+	}
+
+	err = b.setSpec(probe, t)
+	if err != nil {
+		return err
+	}
+
+	return b.client.Update(ctx, probe)
+}
+
+func (b *probeBackend) delete(ctx context.Context, name string) error {
+	probe, err := b.find(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return client.IgnoreNotFound(b.client.Delete(ctx, probe))
+}
+
+// find looks up the Probe that was created for the monitor with name.
+// Returns models.ErrMonitorNotFound if there is none.
+func (b *probeBackend) find(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	probes, err := b.listProbes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range probes {
+		if probes[i].GetAnnotations()[annotationMonitorName] == name {
+			return &probes[i], nil
+		}
+	}
+
+	return nil, models.ErrMonitorNotFound
+}
+
+// listProbes lists all Probes in the configured namespace that are managed
+// by the controller.
+func (b *probeBackend) listProbes(ctx context.Context) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(probeGVK.GroupVersion().WithKind(probeGVK.Kind + "List"))
+
+	err := b.client.List(ctx, list, client.InNamespace(b.namespace), client.MatchingLabels{labelManagedBy: managedByValue})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list probes")
+	}
+
+	return list.Items, nil
+}
+
+func probeMonitor(probe *unstructured.Unstructured) (*models.Monitor, error) {
+	static, _, err := unstructured.NestedStringSlice(probe.Object, "spec", "targets", "staticConfig", "static")
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed probe %s/%s", probe.GetNamespace(), probe.GetName())
+	}
+
+	monitor := &models.Monitor{
+		ID:        probeID(probe),
+		Name:      probe.GetAnnotations()[annotationMonitorName],
+		Namespace: probe.GetNamespace(),
+		Config:    probe,
+	}
+
+	if len(static) > 0 {
+		monitor.URL = static[0]
+	}
+
+	return monitor, nil
+}
+
+func (b *probeBackend) setSpec(probe *unstructured.Unstructured, t *target) error {
+	labels := probe.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	labels[labelManagedBy] = managedByValue
+	probe.SetLabels(labels)
+
+	annotations := probe.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[annotationMonitorName] = t.Name
+	probe.SetAnnotations(annotations)
+
+	targetLabels := make(map[string]interface{}, len(t.Labels))
+	for key, value := range t.Labels {
+		targetLabels[key] = value
+	}
+
+	spec := map[string]interface{}{
+		"module": t.Module,
+		"prober": map[string]interface{}{
+			"url": b.proberURL,
+		},
+		"targets": map[string]interface{}{
+			"staticConfig": map[string]interface{}{
+				"static": []interface{}{t.URL},
+				"labels": targetLabels,
+			},
+		},
+	}
+
+	if t.Interval != "" {
+		spec["interval"] = t.Interval
+	}
+
+	if b.jobName != "" {
+		spec["jobName"] = b.jobName
+	}
+
+	return unstructured.SetNestedMap(probe.Object, spec, "spec")
+}
+
+func probeID(probe *unstructured.Unstructured) string {
+	return probe.GetNamespace() + "/" + probe.GetName()
+}
+
+// NewProbe creates an empty unstructured Prometheus Operator Probe.
+func NewProbe() *unstructured.Unstructured {
+	probe := &unstructured.Unstructured{}
+	probe.SetGroupVersionKind(probeGVK)
+
+	return probe
+}
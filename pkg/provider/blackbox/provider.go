@@ -0,0 +1,198 @@
+package blackbox
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// backend manages the Kubernetes objects that represent the blackbox
+// targets.
+type backend interface {
+	create(ctx context.Context, t *target) (id string, err error)
+	get(ctx context.Context, name string) (*models.Monitor, error)
+	update(ctx context.Context, t *target) error
+	delete(ctx context.Context, name string) error
+	list(ctx context.Context) ([]*models.Monitor, error)
+	diff(current *models.Monitor, t *target) (models.FieldDiffs, error)
+}
+
+// Provider manages monitors for the Prometheus blackbox_exporter. Depending
+// on the configured mode, monitors are either represented as Prometheus
+// Operator Probe resources or as entries in a file_sd ConfigMap.
+type Provider struct {
+	backend backend
+	config  config.BlackboxConfig
+	builder *builder
+}
+
+// NewProvider creates a new blackbox provider with given BlackboxConfig. The
+// client is used to manage the Kubernetes objects. Returns an error if the
+// config is invalid.
+func NewProvider(config config.BlackboxConfig, client client.Client) (*Provider, error) {
+	backend, err := newBackend(config, client)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		backend: backend,
+		config:  config,
+		builder: newBuilder(config.MonitorDefaults),
+	}
+
+	return p, nil
+}
+
+func init() {
+	provider.Register(config.ProviderBlackbox, newFromConfig)
+}
+
+// newFromConfig is the provider.Factory of the blackbox provider. It decodes
+// rawConfig on top of the default config.
+func newFromConfig(rawConfig json.RawMessage, client client.Client) (provider.Interface, error) {
+	c := config.NewDefaultBlackboxConfig()
+
+	err := provider.DecodeConfig(rawConfig, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := NewProvider(c, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func newBackend(c config.BlackboxConfig, client client.Client) (backend, error) {
+	switch c.Mode {
+	case config.BlackboxModeProbe, "":
+		if c.ProberURL == "" {
+			return nil, errors.New("blackbox prober URL must not be empty")
+		}
+
+		if c.Namespace == "" {
+			return nil, errors.New("blackbox probe namespace must not be empty")
+		}
+
+		return &probeBackend{
+			client:    client,
+			namespace: c.Namespace,
+			proberURL: c.ProberURL,
+			jobName:   c.JobName,
+		}, nil
+	case config.BlackboxModeFileSD:
+		if c.FileSD.Namespace == "" || c.FileSD.Name == "" {
+			return nil, errors.New("blackbox file_sd configmap namespace and name must not be empty")
+		}
+
+		return &fileSDBackend{
+			client: client,
+			key:    types.NamespacedName{Namespace: c.FileSD.Namespace, Name: c.FileSD.Name},
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported blackbox mode %q", c.Mode)
+	}
+}
+
+// Create implements provider.Interface.
+func (p *Provider) Create(model *models.Monitor) error {
+	t, err := p.builder.FromModel(model)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build blackbox target from model: %#v", model)
+	}
+
+	id, err := p.backend.create(context.TODO(), t)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create blackbox target for monitor %q", model.Name)
+	}
+
+	model.ID = id
+
+	return nil
+}
+
+// Get implements provider.Interface.
+func (p *Provider) Get(name string) (*models.Monitor, error) {
+	monitor, err := p.backend.get(context.TODO(), name)
+	if err == models.ErrMonitorNotFound {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get blackbox target for monitor %q", name)
+	}
+
+	return monitor, nil
+}
+
+// Diff implements provider.Interface. The Kubernetes object that would be
+// written for the desired monitor is compared to the existing one.
+func (p *Provider) Diff(current, desired *models.Monitor) (models.FieldDiffs, error) {
+	t, err := p.builder.FromModel(desired)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build blackbox target from model: %#v", desired)
+	}
+
+	return p.backend.diff(current, t)
+}
+
+// Render implements provider.Renderer.
+func (p *Provider) Render(model *models.Monitor) (interface{}, error) {
+	t, err := p.builder.FromModel(model)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build blackbox target from model: %#v", model)
+	}
+
+	return t, nil
+}
+
+// Update implements provider.Interface.
+func (p *Provider) Update(model *models.Monitor) error {
+	t, err := p.builder.FromModel(model)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build blackbox target from model: %#v", model)
+	}
+
+	err = p.backend.update(context.TODO(), t)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update blackbox target for monitor %q", model.Name)
+	}
+
+	return nil
+}
+
+// List implements provider.Interface.
+func (p *Provider) List() ([]*models.Monitor, error) {
+	monitors, err := p.backend.list(context.TODO())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list blackbox targets")
+	}
+
+	return monitors, nil
+}
+
+// Delete implements provider.Interface.
+func (p *Provider) Delete(name string) error {
+	err := p.backend.delete(context.TODO(), name)
+	if err == models.ErrMonitorNotFound {
+		return err
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to delete blackbox target for monitor %q", name)
+	}
+
+	return nil
+}
+
+// GetIPSourceRanges implements provider.Interface. As the blackbox_exporter
+// is self-hosted, the source ranges cannot be discovered and are taken from
+// the provider config instead.
+func (p *Provider) GetIPSourceRanges(_ *models.Monitor) ([]string, error) {
+	return p.config.SourceRanges, nil
+}
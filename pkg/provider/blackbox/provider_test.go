@@ -0,0 +1,477 @@
+package blackbox
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.BlackboxConfig
+		expected error
+	}{
+		{
+			name:   "probe mode",
+			config: config.BlackboxConfig{Mode: config.BlackboxModeProbe, ProberURL: "blackbox:9115", Namespace: "monitoring"},
+		},
+		{
+			name:     "probe mode without prober URL",
+			config:   config.BlackboxConfig{Mode: config.BlackboxModeProbe, Namespace: "monitoring"},
+			expected: errors.New("blackbox prober URL must not be empty"),
+		},
+		{
+			name:     "probe mode without namespace",
+			config:   config.BlackboxConfig{Mode: config.BlackboxModeProbe, ProberURL: "blackbox:9115"},
+			expected: errors.New("blackbox probe namespace must not be empty"),
+		},
+		{
+			name: "file_sd mode",
+			config: config.BlackboxConfig{
+				Mode:   config.BlackboxModeFileSD,
+				FileSD: config.BlackboxFileSDConfig{Namespace: "monitoring", Name: "targets"},
+			},
+		},
+		{
+			name:     "file_sd mode without configmap",
+			config:   config.BlackboxConfig{Mode: config.BlackboxModeFileSD},
+			expected: errors.New("blackbox file_sd configmap namespace and name must not be empty"),
+		},
+		{
+			name:     "unsupported mode",
+			config:   config.BlackboxConfig{Mode: "foo"},
+			expected: errors.New(`unsupported blackbox mode "foo"`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewProvider(test.config, fake.NewClientBuilder().Build())
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProvider_Probe(t *testing.T) {
+	tests := []struct {
+		name     string
+		run      func(*Provider) error
+		validate func(*testing.T, client.Client)
+		expected error
+	}{
+		{
+			name: "creates probe in the configured namespace",
+			run: func(p *Provider) error {
+				return p.Create(&models.Monitor{
+					Name:      "kube-system-My_Monitor",
+					Namespace: "kube-system",
+					URL:       "http://my-monitor",
+				})
+			},
+			validate: func(t *testing.T, c client.Client) {
+				probe := getProbe(t, c, "monitoring", "kube-system-my-monitor-4eaa15fe")
+
+				assert.Equal(t, map[string]string{labelManagedBy: managedByValue}, probe.GetLabels())
+				assert.Equal(t, map[string]string{annotationMonitorName: "kube-system-My_Monitor"}, probe.GetAnnotations())
+				assert.Equal(t, map[string]interface{}{
+					"module": "http_2xx",
+					"prober": map[string]interface{}{
+						"url": "blackbox:9115",
+					},
+					"targets": map[string]interface{}{
+						"staticConfig": map[string]interface{}{
+							"static": []interface{}{"http://my-monitor"},
+							"labels": map[string]interface{}{
+								"team": "platform",
+							},
+						},
+					},
+				}, probe.Object["spec"])
+			},
+		},
+		{
+			name: "probes tls and grpc endpoints by address",
+			run: func(p *Provider) error {
+				err := p.Create(&models.Monitor{
+					Name:      "tls-monitor",
+					Namespace: "kube-system",
+					Type:      models.MonitorTypeTLS,
+					URL:       "https://my-monitor",
+				})
+				if err != nil {
+					return err
+				}
+
+				return p.Create(&models.Monitor{
+					Name:      "grpc-monitor",
+					Namespace: "kube-system",
+					Type:      models.MonitorTypeGRPC,
+					URL:       "http://my-monitor:9090",
+				})
+			},
+			validate: func(t *testing.T, c client.Client) {
+				probe := getProbe(t, c, "monitoring", "tls-monitor")
+
+				module, _, _ := unstructured.NestedString(probe.Object, "spec", "module")
+				assert.Equal(t, "tls_connect", module)
+
+				targets, _, _ := unstructured.NestedStringSlice(probe.Object, "spec", "targets", "staticConfig", "static")
+				assert.Equal(t, []string{"my-monitor:443"}, targets)
+
+				probe = getProbe(t, c, "monitoring", "grpc-monitor")
+
+				module, _, _ = unstructured.NestedString(probe.Object, "spec", "module")
+				assert.Equal(t, "grpc", module)
+
+				targets, _, _ = unstructured.NestedStringSlice(probe.Object, "spec", "targets", "staticConfig", "static")
+				assert.Equal(t, []string{"my-monitor:9090"}, targets)
+			},
+		},
+		{
+			name: "annotations override monitor defaults",
+			run: func(p *Provider) error {
+				return p.Create(&models.Monitor{
+					Name:      "my-monitor",
+					Namespace: "kube-system",
+					URL:       "http://my-monitor",
+					Annotations: config.Annotations{
+						config.AnnotationBlackboxModule:   "http_post_2xx",
+						config.AnnotationBlackboxInterval: "30s",
+						config.AnnotationBlackboxLabels:   "team=search, severity=critical",
+					},
+				})
+			},
+			validate: func(t *testing.T, c client.Client) {
+				probe := getProbe(t, c, "monitoring", "my-monitor")
+
+				module, _, _ := unstructured.NestedString(probe.Object, "spec", "module")
+				assert.Equal(t, "http_post_2xx", module)
+
+				interval, _, _ := unstructured.NestedString(probe.Object, "spec", "interval")
+				assert.Equal(t, "30s", interval)
+
+				labels, _, _ := unstructured.NestedStringMap(probe.Object, "spec", "targets", "staticConfig", "labels")
+				assert.Equal(t, map[string]string{"team": "search", "severity": "critical"}, labels)
+			},
+		},
+		{
+			name: "do not create probe if the labels annotation is invalid",
+			run: func(p *Provider) error {
+				return p.Create(&models.Monitor{
+					Name:      "my-monitor",
+					Namespace: "kube-system",
+					URL:       "http://my-monitor",
+					Annotations: config.Annotations{
+						config.AnnotationBlackboxLabels: "foo",
+					},
+				})
+			},
+			expected: errors.New(`failed to build blackbox target from model: &models.Monitor{ID:"", Name:"my-monitor", Namespace:"kube-system", Type:"", URL:"http://my-monitor", Annotations:config.Annotations{"blackbox.ingress-monitor.bonial.com/labels":"foo"}, Config:interface {}(nil)}: invalid label "foo" in annotation "blackbox.ingress-monitor.bonial.com/labels", expected key=value`),
+		},
+		{
+			name: "updates existing probe",
+			run: func(p *Provider) error {
+				err := p.Create(&models.Monitor{Name: "my-monitor", Namespace: "kube-system", URL: "http://my-monitor"})
+				if err != nil {
+					return err
+				}
+
+				return p.Update(&models.Monitor{Name: "my-monitor", Namespace: "kube-system", URL: "https://my-monitor"})
+			},
+			validate: func(t *testing.T, c client.Client) {
+				probe := getProbe(t, c, "monitoring", "my-monitor")
+
+				static, _, _ := unstructured.NestedStringSlice(probe.Object, "spec", "targets", "staticConfig", "static")
+				assert.Equal(t, []string{"https://my-monitor"}, static)
+			},
+		},
+		{
+			name: "deletes probe",
+			run: func(p *Provider) error {
+				err := p.Create(&models.Monitor{Name: "my-monitor", Namespace: "kube-system", URL: "http://my-monitor"})
+				if err != nil {
+					return err
+				}
+
+				return p.Delete("my-monitor")
+			},
+			validate: func(t *testing.T, c client.Client) {
+				list := &unstructured.UnstructuredList{}
+				list.SetGroupVersionKind(probeGVK.GroupVersion().WithKind("ProbeList"))
+				require.NoError(t, c.List(context.Background(), list))
+				assert.Len(t, list.Items, 0)
+			},
+		},
+		{
+			name: "delete returns models.ErrMonitorNotFound if probe is not found",
+			run: func(p *Provider) error {
+				return p.Delete("my-monitor")
+			},
+			expected: models.ErrMonitorNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, c := newTestProvider(t, config.BlackboxConfig{
+				Mode:      config.BlackboxModeProbe,
+				ProberURL: "blackbox:9115",
+				Namespace: "monitoring",
+			})
+
+			err := test.run(p)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			if test.validate != nil {
+				test.validate(t, c)
+			}
+		})
+	}
+}
+
+func TestProvider_ProbeGet(t *testing.T) {
+	p, c := newTestProvider(t, config.BlackboxConfig{
+		Mode:      config.BlackboxModeProbe,
+		ProberURL: "blackbox:9115",
+		Namespace: "monitoring",
+	})
+
+	// Managed probes outside of the configured namespace are ignored.
+	foreign := NewProbe()
+	foreign.SetNamespace("default")
+	foreign.SetName("my-monitor")
+	foreign.SetLabels(map[string]string{labelManagedBy: managedByValue})
+	foreign.SetAnnotations(map[string]string{annotationMonitorName: "my-monitor"})
+	require.NoError(t, c.Create(context.Background(), foreign))
+
+	_, err := p.Get("my-monitor")
+	require.Equal(t, models.ErrMonitorNotFound, err)
+
+	require.NoError(t, p.Create(&models.Monitor{Name: "some-other-monitor", Namespace: "default", URL: "http://other"}))
+
+	model := &models.Monitor{Name: "my-monitor", Namespace: "kube-system", URL: "http://my-monitor"}
+	require.NoError(t, p.Create(model))
+	assert.Equal(t, "monitoring/my-monitor", model.ID)
+
+	monitor, err := p.Get("my-monitor")
+	require.NoError(t, err)
+
+	diffs, err := p.Diff(monitor, model)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	diffs, err = p.Diff(monitor, &models.Monitor{Name: "my-monitor", Namespace: "kube-system", URL: "https://my-monitor"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spec.targets"}, diffs.Fields())
+
+	assert.Equal(t, &models.Monitor{
+		ID:        "monitoring/my-monitor",
+		Name:      "my-monitor",
+		Namespace: "monitoring",
+		URL:       "http://my-monitor",
+	}, withoutConfig(monitor)[0])
+
+	monitors, err := p.List()
+	require.NoError(t, err)
+	monitors = withoutConfig(monitors...)
+	assert.ElementsMatch(t, []*models.Monitor{
+		{ID: "monitoring/some-other-monitor", Name: "some-other-monitor", Namespace: "monitoring", URL: "http://other"},
+		{ID: "monitoring/my-monitor", Name: "my-monitor", Namespace: "monitoring", URL: "http://my-monitor"},
+	}, monitors)
+}
+
+func TestProvider_FileSD(t *testing.T) {
+	p, c := newTestProvider(t, config.BlackboxConfig{
+		Mode:   config.BlackboxModeFileSD,
+		FileSD: config.BlackboxFileSDConfig{Namespace: "monitoring", Name: "blackbox-targets"},
+	})
+
+	_, err := p.Get("my-monitor")
+	require.Equal(t, models.ErrMonitorNotFound, err)
+
+	model := &models.Monitor{
+		Name:      "my-monitor",
+		Namespace: "kube-system",
+		URL:       "http://my-monitor",
+		Annotations: config.Annotations{
+			config.AnnotationBlackboxInterval: "30s",
+		},
+	}
+	require.NoError(t, p.Create(model))
+	assert.Equal(t, "my-monitor.json", model.ID)
+
+	require.NoError(t, p.Create(&models.Monitor{Name: "other-monitor", Namespace: "default", URL: "http://other"}))
+
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "monitoring", Name: "blackbox-targets"}, configMap))
+	assert.Equal(t, map[string]string{
+		"my-monitor.json":    `[{"targets":["http://my-monitor"],"labels":{"__monitor_name__":"my-monitor","__param_module":"http_2xx","__scrape_interval__":"30s","team":"platform"}}]`,
+		"other-monitor.json": `[{"targets":["http://other"],"labels":{"__monitor_name__":"other-monitor","__param_module":"http_2xx","team":"platform"}}]`,
+	}, configMap.Data)
+
+	monitors, err := p.List()
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Monitor{
+		{ID: "my-monitor.json", Name: "my-monitor", URL: "http://my-monitor"},
+		{ID: "other-monitor.json", Name: "other-monitor", URL: "http://other"},
+	}, withoutConfig(monitors...))
+
+	monitor, err := p.Get("my-monitor")
+	require.NoError(t, err)
+
+	diffs, err := p.Diff(monitor, model)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	updated := &models.Monitor{Name: "my-monitor", Namespace: "kube-system", URL: "https://my-monitor"}
+
+	diffs, err = p.Diff(monitor, updated)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"targets", "labels"}, diffs.Fields())
+
+	require.NoError(t, p.Update(updated))
+
+	monitor, err = p.Get("my-monitor")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Monitor{ID: "my-monitor.json", Name: "my-monitor", URL: "https://my-monitor"}, withoutConfig(monitor)[0])
+
+	require.NoError(t, p.Delete("my-monitor"))
+	require.Equal(t, models.ErrMonitorNotFound, p.Delete("my-monitor"))
+
+	_, err = p.Get("my-monitor")
+	require.Equal(t, models.ErrMonitorNotFound, err)
+}
+
+func TestProvider_GetIPSourceRanges(t *testing.T) {
+	p, _ := newTestProvider(t, config.BlackboxConfig{
+		ProberURL:    "blackbox:9115",
+		Namespace:    "monitoring",
+		SourceRanges: []string{"10.0.0.0/8"},
+	})
+
+	ips, err := p.GetIPSourceRanges(&models.Monitor{Name: "my-monitor"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8"}, ips)
+}
+
+func TestObjectName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "my-monitor", expected: "my-monitor"},
+		{name: "kube-system-My_Monitor", expected: "kube-system-my-monitor-4eaa15fe"},
+		{name: "foo_bar", expected: "foo-bar-4928cae8"},
+		{name: "Foo-Bar", expected: "foo-bar-e32b2ca1"},
+		{name: "___", expected: "bda25155"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, objectName(test.name))
+		})
+	}
+
+	long := strings.Repeat("a", 300)
+	assert.Len(t, objectName(long), maxObjectNameLength)
+	assert.NotEqual(t, objectName(long), objectName(long+"b"))
+}
+
+func newTestProvider(t *testing.T, cfg config.BlackboxConfig) (*Provider, client.Client) {
+	cfg.MonitorDefaults = config.BlackboxMonitorDefaults{
+		Module:     "http_2xx",
+		GRPCModule: "grpc",
+		TLSModule:  "tls_connect",
+		Labels:     map[string]string{"team": "platform"},
+	}
+
+	c := fake.NewClientBuilder().Build()
+
+	p, err := NewProvider(cfg, c)
+	require.NoError(t, err)
+
+	return p, c
+}
+
+func getProbe(t *testing.T, c client.Client, namespace, name string) *unstructured.Unstructured {
+	probe := NewProbe()
+
+	err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, probe)
+	require.NoError(t, err)
+
+	return probe
+}
+
+// withoutConfig removes the provider specific config from monitors to
+// simplify assertions.
+func withoutConfig(monitors ...*models.Monitor) []*models.Monitor {
+	for _, monitor := range monitors {
+		monitor.Config = nil
+	}
+
+	return monitors
+}
+
+func TestProvider_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations config.Annotations
+		expected    string
+	}{
+		{
+			name: "valid annotations",
+			annotations: config.Annotations{
+				config.AnnotationBlackboxInterval: "1m30s",
+				config.AnnotationBlackboxModule:   "http_post_2xx",
+				config.AnnotationBlackboxLabels:   "team=platform,severity=critical",
+			},
+		},
+		{
+			name: "multiple violations",
+			annotations: config.Annotations{
+				config.AnnotationBlackboxInterval:             "30",
+				config.AnnotationBlackboxLabels:               "team",
+				"blackbox.ingress-monitor.bonial.com/module2": "http_2xx",
+			},
+			expected: `annotation "blackbox.ingress-monitor.bonial.com/module2": unknown annotation; ` +
+				`annotation "blackbox.ingress-monitor.bonial.com/interval": invalid duration "30"; ` +
+				`annotation "blackbox.ingress-monitor.bonial.com/labels": invalid label "team", expected key=value`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &Provider{}
+
+			err := p.Validate(&models.Monitor{Annotations: test.annotations})
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, test.expected, err.Error())
+			}
+		})
+	}
+}
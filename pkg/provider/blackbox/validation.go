@@ -0,0 +1,49 @@
+package blackbox
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+)
+
+var (
+	// prometheusDuration matches durations in the format used by
+	// Prometheus, e.g. "30s" or "1m30s".
+	prometheusDuration = regexp.MustCompile(`^([0-9]+(ms|s|m|h|d|w|y))+$`)
+
+	knownAnnotations = []string{
+		config.AnnotationBlackboxInterval,
+		config.AnnotationBlackboxLabels,
+		config.AnnotationBlackboxModule,
+	}
+)
+
+// Validate implements provider.Validator.
+func (p *Provider) Validate(model *models.Monitor) error {
+	return validateAnnotations(model.Annotations)
+}
+
+func validateAnnotations(anno config.Annotations) error {
+	v := config.NewAnnotationValidator(anno)
+
+	v.UnknownKeys(config.AnnotationPrefixBlackbox, knownAnnotations...)
+
+	if interval, ok := anno[config.AnnotationBlackboxInterval]; ok && !prometheusDuration.MatchString(interval) {
+		v.Errorf(config.AnnotationBlackboxInterval, "invalid duration %q", interval)
+	}
+
+	if module, ok := anno[config.AnnotationBlackboxModule]; ok && strings.TrimSpace(module) == "" {
+		v.Errorf(config.AnnotationBlackboxModule, "module must not be empty")
+	}
+
+	for _, pair := range anno.StringSliceValue(config.AnnotationBlackboxLabels) {
+		key, _, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			v.Errorf(config.AnnotationBlackboxLabels, "invalid label %q, expected key=value", pair)
+		}
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,16 @@
+// Package builtin registers all monitor providers that are part of this
+// repository. Import it for its side effects:
+//
+//	import _ "github.com/bonial-oss/ingress-monitor-controller/pkg/provider/builtin"
+//
+// Out-of-tree providers are compiled in the same way by blank importing
+// their package, which has to call provider.Register in its init function.
+package builtin
+
+import (
+	_ "github.com/bonial-oss/ingress-monitor-controller/pkg/provider/blackbox"
+	_ "github.com/bonial-oss/ingress-monitor-controller/pkg/provider/null"
+	_ "github.com/bonial-oss/ingress-monitor-controller/pkg/provider/site24x7"
+	_ "github.com/bonial-oss/ingress-monitor-controller/pkg/provider/uptimekuma"
+	_ "github.com/bonial-oss/ingress-monitor-controller/pkg/provider/webhook"
+)
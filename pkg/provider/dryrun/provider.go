@@ -0,0 +1,88 @@
+package dryrun
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/pkg/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("dry-run-provider")
+
+// Provider wraps another provider and turns all write operations into
+// no-ops. Reads are passed through to the wrapped provider, while creations,
+// updates and deletions are only logged together with the payload that would
+// have been sent. This allows to preview the effect of configuration changes
+// against the real monitors.
+type Provider struct {
+	provider provider.Interface
+}
+
+// NewProvider creates a new dry run *Provider which wraps p.
+func NewProvider(p provider.Interface) *Provider {
+	return &Provider{provider: p}
+}
+
+// Create implements provider.Interface.
+func (p *Provider) Create(model *models.Monitor) error {
+	return p.plan("create", model)
+}
+
+// Get implements provider.Interface.
+func (p *Provider) Get(name string) (*models.Monitor, error) {
+	return p.provider.Get(name)
+}
+
+// Diff implements provider.Interface.
+func (p *Provider) Diff(current, desired *models.Monitor) (models.FieldDiffs, error) {
+	return p.provider.Diff(current, desired)
+}
+
+// Update implements provider.Interface.
+func (p *Provider) Update(model *models.Monitor) error {
+	return p.plan("update", model)
+}
+
+// Delete implements provider.Interface. Returns models.ErrMonitorNotFound if
+// the wrapped provider does not know the monitor, just like a real deletion
+// would.
+func (p *Provider) Delete(name string) error {
+	monitor, err := p.provider.Get(name)
+	if err != nil {
+		return err
+	}
+
+	return p.plan("delete", monitor)
+}
+
+// List implements provider.Interface.
+func (p *Provider) List() ([]*models.Monitor, error) {
+	return p.provider.List()
+}
+
+// GetIPSourceRanges implements provider.Interface.
+func (p *Provider) GetIPSourceRanges(model *models.Monitor) ([]string, error) {
+	return p.provider.GetIPSourceRanges(model)
+}
+
+// plan logs the planned operation for model and counts it. If the wrapped
+// provider implements provider.Renderer, the rendered payload is logged,
+// otherwise the model itself.
+func (p *Provider) plan(operation string, model *models.Monitor) error {
+	var payload interface{} = model
+
+	if renderer, ok := p.provider.(provider.Renderer); ok && operation != "delete" {
+		rendered, err := renderer.Render(model)
+		if err != nil {
+			return errors.Wrapf(err, "failed to render payload for monitor %q", model.Name)
+		}
+
+		payload = rendered
+	}
+
+	metrics.DryRunOperationsTotal.WithLabelValues(operation, model.Name).Inc()
+	log.Info("dry run: not sending monitor change to provider", "operation", operation, "monitor", model.Name, "payload", payload)
+
+	return nil
+}
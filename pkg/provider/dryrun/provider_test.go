@@ -0,0 +1,140 @@
+package dryrun
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// renderingProvider is a fake provider that implements provider.Renderer.
+type renderingProvider struct {
+	fake.Provider
+}
+
+func (p *renderingProvider) Render(model *models.Monitor) (interface{}, error) {
+	args := p.Called(model)
+
+	return args.Get(0), args.Error(1)
+}
+
+func TestProvider(t *testing.T) {
+	model := &models.Monitor{Name: "my-monitor", URL: "http://my-monitor"}
+
+	tests := []struct {
+		name        string
+		setup       func(*renderingProvider)
+		call        func(*Provider) error
+		validate    func(*testing.T, *renderingProvider)
+		expectedErr error
+	}{
+		{
+			name: "create is not passed through",
+			setup: func(p *renderingProvider) {
+				p.On("Render", model).Return(map[string]string{"name": "my-monitor"}, nil)
+			},
+			call: func(p *Provider) error {
+				return p.Create(model)
+			},
+			validate: func(t *testing.T, p *renderingProvider) {
+				p.AssertCalled(t, "Render", model)
+				p.AssertNotCalled(t, "Create", mock.Anything)
+			},
+		},
+		{
+			name: "update is not passed through",
+			setup: func(p *renderingProvider) {
+				p.On("Render", model).Return(map[string]string{"name": "my-monitor"}, nil)
+			},
+			call: func(p *Provider) error {
+				return p.Update(model)
+			},
+			validate: func(t *testing.T, p *renderingProvider) {
+				p.AssertNotCalled(t, "Update", mock.Anything)
+			},
+		},
+		{
+			name: "render errors are returned",
+			setup: func(p *renderingProvider) {
+				p.On("Render", model).Return(nil, errors.New("whoops"))
+			},
+			call: func(p *Provider) error {
+				return p.Create(model)
+			},
+			expectedErr: errors.New(`failed to render payload for monitor "my-monitor": whoops`),
+		},
+		{
+			name: "delete of existing monitor is not passed through",
+			setup: func(p *renderingProvider) {
+				p.On("Get", "my-monitor").Return(&models.Monitor{ID: "123", Name: "my-monitor"}, nil)
+			},
+			call: func(p *Provider) error {
+				return p.Delete("my-monitor")
+			},
+			validate: func(t *testing.T, p *renderingProvider) {
+				p.AssertNotCalled(t, "Delete", mock.Anything)
+				p.AssertNotCalled(t, "Render", mock.Anything)
+			},
+		},
+		{
+			name: "delete of nonexistent monitor returns models.ErrMonitorNotFound",
+			setup: func(p *renderingProvider) {
+				p.On("Get", "my-monitor").Return(nil, models.ErrMonitorNotFound)
+			},
+			call: func(p *Provider) error {
+				return p.Delete("my-monitor")
+			},
+			expectedErr: models.ErrMonitorNotFound,
+		},
+		{
+			name: "reads are passed through",
+			setup: func(p *renderingProvider) {
+				p.On("Get", "my-monitor").Return(model, nil)
+				p.On("List").Return([]*models.Monitor{model}, nil)
+				p.On("GetIPSourceRanges", model).Return([]string{"127.0.0.1/32"}, nil)
+			},
+			call: func(p *Provider) error {
+				monitor, err := p.Get("my-monitor")
+				if err != nil {
+					return err
+				}
+
+				if _, err := p.List(); err != nil {
+					return err
+				}
+
+				_, err = p.GetIPSourceRanges(monitor)
+				return err
+			},
+			validate: func(t *testing.T, p *renderingProvider) {
+				p.AssertExpectations(t)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wrapped := &renderingProvider{}
+
+			if test.setup != nil {
+				test.setup(wrapped)
+			}
+
+			err := test.call(NewProvider(wrapped))
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			if test.validate != nil {
+				test.validate(t, wrapped)
+			}
+		})
+	}
+}
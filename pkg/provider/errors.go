@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+)
+
+// ClassifyHTTPError classifies err, which was caused by an HTTP response
+// with statusCode, using the error types of pkg/models. Responses with
+// status 429 are rate limited and honor the Retry-After header, 5xx
+// responses are retryable, 400 and 422 responses indicate an invalid
+// monitor config and 403 responses are permanent. Errors for other status
+// codes are returned as is.
+func ClassifyHTTPError(statusCode int, header http.Header, err error) error {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return models.RateLimited(err, parseRetryAfter(header.Get("Retry-After")))
+	case statusCode >= http.StatusInternalServerError:
+		return models.Retryable(err)
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return models.InvalidConfig(err)
+	case statusCode == http.StatusForbidden:
+		return models.Permanent(err)
+	default:
+		return err
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date. Returns zero if value is empty or
+// invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyHTTPError(t *testing.T) {
+	errStatus := errors.New("unexpected status code")
+
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		validate   func(t *testing.T, err error)
+	}{
+		{
+			name:       "rate limited with retry after",
+			statusCode: http.StatusTooManyRequests,
+			header:     http.Header{"Retry-After": []string{"120"}},
+			validate: func(t *testing.T, err error) {
+				retryAfter, ok := models.RetryAfter(err)
+				assert.True(t, ok)
+				assert.Equal(t, 2*time.Minute, retryAfter)
+			},
+		},
+		{
+			name:       "rate limited without retry after",
+			statusCode: http.StatusTooManyRequests,
+			validate: func(t *testing.T, err error) {
+				retryAfter, ok := models.RetryAfter(err)
+				assert.True(t, ok)
+				assert.Zero(t, retryAfter)
+			},
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusBadGateway,
+			validate: func(t *testing.T, err error) {
+				assert.True(t, models.IsRetryable(err))
+				assert.False(t, models.IsPermanent(err))
+			},
+		},
+		{
+			name:       "bad request",
+			statusCode: http.StatusBadRequest,
+			validate: func(t *testing.T, err error) {
+				var invalidConfigErr *models.InvalidConfigError
+				assert.ErrorAs(t, err, &invalidConfigErr)
+			},
+		},
+		{
+			name:       "unprocessable entity",
+			statusCode: http.StatusUnprocessableEntity,
+			validate: func(t *testing.T, err error) {
+				assert.True(t, models.IsPermanent(err))
+			},
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			validate: func(t *testing.T, err error) {
+				var permanentErr *models.PermanentError
+				assert.ErrorAs(t, err, &permanentErr)
+			},
+		},
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			validate: func(t *testing.T, err error) {
+				assert.Equal(t, errStatus, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ClassifyHTTPError(test.statusCode, test.header, errStatus)
+			assert.ErrorIs(t, err, errStatus)
+			test.validate(t, err)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Zero(t, parseRetryAfter(""))
+	assert.Zero(t, parseRetryAfter("soon"))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30"))
+
+	d := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.InDelta(t, time.Hour, d, float64(5*time.Second))
+}
@@ -41,6 +41,26 @@ func (p *Provider) Delete(name string) error {
 	return args.Error(0)
 }
 
+// Diff implements provider.Interface.
+func (p *Provider) Diff(current, desired *models.Monitor) (models.FieldDiffs, error) {
+	args := p.Called(current, desired)
+	if obj, ok := args.Get(0).(models.FieldDiffs); ok {
+		return obj, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// List implements provider.Interface.
+func (p *Provider) List() ([]*models.Monitor, error) {
+	args := p.Called()
+	if obj, ok := args.Get(0).([]*models.Monitor); ok {
+		return obj, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 // GetIPSourceRanges implements provider.Interface.
 func (p *Provider) GetIPSourceRanges(model *models.Monitor) ([]string, error) {
 	args := p.Called(model)
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/pkg/errors"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed is the state of a healthy provider. All calls are
+	// passed through.
+	BreakerClosed BreakerState = iota
+
+	// BreakerHalfOpen is the state after the cooldown of an open breaker
+	// elapsed. A single trial call is passed through to probe whether the
+	// provider recovered.
+	BreakerHalfOpen
+
+	// BreakerOpen is the state of an unhealthy provider. All calls fail
+	// immediately with ErrCircuitOpen.
+	BreakerOpen
+)
+
+// String implements fmt.Stringer.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// ErrCircuitOpen is returned for calls that are short-circuited because the
+// provider is unhealthy.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Breaker is a circuit breaker for a single provider. It opens after
+// threshold consecutive calls failed with a retryable error (see
+// models.IsRetryable) and short-circuits all calls until cooldown elapsed. Other
+// errors, e.g. for monitors that do not exist, do not indicate an unhealthy
+// provider and are not counted. The state is exported in the provider
+// circuit breaker state metric.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker creates a new closed *Breaker for the provider with name.
+func NewBreaker(name string, threshold int, cooldown time.Duration) *Breaker {
+	b := &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+
+	b.setState(BreakerClosed)
+
+	return b
+}
+
+// Name returns the name of the provider the breaker belongs to.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkCooldown()
+
+	return b.state
+}
+
+// Middleware returns a Middleware which passes calls through the breaker.
+func (b *Breaker) Middleware() Middleware {
+	return func(p provider.Interface) provider.Interface {
+		return wrap(p, func(call func() error) error {
+			err := b.allow()
+			if err != nil {
+				return err
+			}
+
+			err = call()
+
+			b.record(err)
+
+			return err
+		})
+	}
+}
+
+// HealthCheck reports an error while the breaker is open. It has the
+// signature of a healthz.Checker.
+func (b *Breaker) HealthCheck(_ *http.Request) error {
+	if b.State() == BreakerOpen {
+		return errors.Errorf("circuit breaker of provider %q is open", b.name)
+	}
+
+	return nil
+}
+
+// allow returns ErrCircuitOpen if the call must be short-circuited. In the
+// half-open state only a single trial call is allowed at a time.
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkCooldown()
+
+	switch b.state {
+	case BreakerOpen:
+		return errors.Wrapf(ErrCircuitOpen, "provider %q is unavailable", b.name)
+	case BreakerHalfOpen:
+		if b.probing {
+			return errors.Wrapf(ErrCircuitOpen, "provider %q is unavailable", b.name)
+		}
+
+		b.probing = true
+	}
+
+	return nil
+}
+
+// record updates the breaker with the outcome of a call.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if !models.IsRetryable(err) {
+		b.failures = 0
+		b.setState(BreakerClosed)
+		return
+	}
+
+	b.failures++
+
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.setState(BreakerOpen)
+	}
+}
+
+// checkCooldown moves an open breaker to the half-open state once the
+// cooldown elapsed. Must be called with the lock held.
+func (b *Breaker) checkCooldown() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+// setState sets the state of the breaker. Must be called with the lock
+// held.
+func (b *Breaker) setState(state BreakerState) {
+	if state != b.state {
+		log.Info("circuit breaker state changed", "provider", b.name, "from", b.state.String(), "to", state.String())
+	}
+
+	b.state = state
+
+	metrics.ProviderCircuitBreakerState.WithLabelValues(b.name).Set(float64(state))
+}
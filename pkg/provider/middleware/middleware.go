@@ -0,0 +1,100 @@
+// Package middleware provides wrappers around provider.Interface which
+// protect monitor providers from being overloaded: a token-bucket rate
+// limit, retries with backoff for retryable errors and a circuit breaker
+// which short-circuits calls while a provider is unhealthy.
+package middleware
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("provider-middleware")
+
+// Middleware wraps a provider.Interface to add behaviour around its calls.
+type Middleware func(provider.Interface) provider.Interface
+
+// Wrap wraps p with middlewares. The first middleware is the outermost one,
+// i.e. it sees each call first.
+func Wrap(p provider.Interface, middlewares ...Middleware) provider.Interface {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		p = middlewares[i](p)
+	}
+
+	return p
+}
+
+// wrapper implements provider.Interface by passing each call to the wrapped
+// provider through around.
+type wrapper struct {
+	provider provider.Interface
+	around   func(call func() error) error
+}
+
+func wrap(p provider.Interface, around func(call func() error) error) *wrapper {
+	return &wrapper{provider: p, around: around}
+}
+
+// Create implements provider.Interface.
+func (w *wrapper) Create(model *models.Monitor) error {
+	return w.around(func() error {
+		return w.provider.Create(model)
+	})
+}
+
+// Get implements provider.Interface.
+func (w *wrapper) Get(name string) (monitor *models.Monitor, err error) {
+	err = w.around(func() (err error) {
+		monitor, err = w.provider.Get(name)
+		return err
+	})
+
+	return monitor, err
+}
+
+// Diff implements provider.Interface. Some providers call their API to
+// build the desired monitor, so diffs are passed through the middleware as
+// well.
+func (w *wrapper) Diff(current, desired *models.Monitor) (diffs models.FieldDiffs, err error) {
+	err = w.around(func() (err error) {
+		diffs, err = w.provider.Diff(current, desired)
+		return err
+	})
+
+	return diffs, err
+}
+
+// Update implements provider.Interface.
+func (w *wrapper) Update(model *models.Monitor) error {
+	return w.around(func() error {
+		return w.provider.Update(model)
+	})
+}
+
+// Delete implements provider.Interface.
+func (w *wrapper) Delete(name string) error {
+	return w.around(func() error {
+		return w.provider.Delete(name)
+	})
+}
+
+// List implements provider.Interface.
+func (w *wrapper) List() (monitors []*models.Monitor, err error) {
+	err = w.around(func() (err error) {
+		monitors, err = w.provider.List()
+		return err
+	})
+
+	return monitors, err
+}
+
+// GetIPSourceRanges implements provider.Interface.
+func (w *wrapper) GetIPSourceRanges(model *models.Monitor) (sourceRanges []string, err error) {
+	err = w.around(func() (err error) {
+		sourceRanges, err = w.provider.GetIPSourceRanges(model)
+		return err
+	})
+
+	return sourceRanges, err
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var errTransient = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+
+func TestRetry(t *testing.T) {
+	defer func(s func(time.Duration)) { sleep = s }(sleep)
+
+	var delays []time.Duration
+
+	sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	backoff := wait.Backoff{Duration: time.Second, Factor: 2, Steps: 3}
+
+	t.Run("retryable errors are retried with backoff", func(t *testing.T) {
+		delays = nil
+
+		p := &fake.Provider{}
+		p.On("Delete", "foo").Return(errTransient).Twice()
+		p.On("Delete", "foo").Return(nil).Once()
+
+		err := Wrap(p, Retry("fake", 3, backoff)).Delete("foo")
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+		p.AssertNumberOfCalls(t, "Delete", 3)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		delays = nil
+
+		p := &fake.Provider{}
+		p.On("Delete", "foo").Return(errTransient)
+
+		err := Wrap(p, Retry("fake", 2, backoff)).Delete("foo")
+		require.Error(t, err)
+		p.AssertNumberOfCalls(t, "Delete", 3)
+	})
+
+	t.Run("non-retryable errors are not retried", func(t *testing.T) {
+		delays = nil
+
+		p := &fake.Provider{}
+		p.On("Get", "foo").Return(nil, models.ErrMonitorNotFound)
+
+		_, err := Wrap(p, Retry("fake", 3, backoff)).Get("foo")
+		assert.Equal(t, models.ErrMonitorNotFound, err)
+		assert.Empty(t, delays)
+		p.AssertNumberOfCalls(t, "Get", 1)
+	})
+
+	t.Run("create is retried if monitor does not exist", func(t *testing.T) {
+		delays = nil
+
+		model := &models.Monitor{Name: "foo"}
+
+		p := &fake.Provider{}
+		p.On("Create", model).Return(errTransient).Once()
+		p.On("Get", "foo").Return(nil, models.ErrMonitorNotFound).Once()
+		p.On("Create", model).Return(nil).Once()
+
+		err := Wrap(p, Retry("fake", 3, backoff)).Create(model)
+		require.NoError(t, err)
+		p.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("create is not retried if monitor was created despite error", func(t *testing.T) {
+		delays = nil
+
+		model := &models.Monitor{Name: "foo"}
+
+		p := &fake.Provider{}
+		p.On("Create", model).Return(errTransient).Once()
+		p.On("Get", "foo").Return(&models.Monitor{ID: "123", Name: "foo"}, nil).Once()
+
+		err := Wrap(p, Retry("fake", 3, backoff)).Create(model)
+		require.NoError(t, err)
+		assert.Equal(t, "123", model.ID)
+		p.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("create is not retried if monitor lookup fails", func(t *testing.T) {
+		delays = nil
+
+		model := &models.Monitor{Name: "foo"}
+
+		p := &fake.Provider{}
+		p.On("Create", model).Return(errTransient).Once()
+		p.On("Get", "foo").Return(nil, errors.New("whoops")).Once()
+
+		err := Wrap(p, Retry("fake", 3, backoff)).Create(model)
+		assert.Equal(t, errTransient, err)
+		p.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("rate limited errors are retried if retry after is short", func(t *testing.T) {
+		delays = nil
+
+		p := &fake.Provider{}
+		p.On("Delete", "foo").Return(models.RateLimited(errors.New("too many requests"), 500*time.Millisecond)).Once()
+		p.On("Delete", "foo").Return(nil).Once()
+
+		err := Wrap(p, Retry("fake", 3, backoff)).Delete("foo")
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second}, delays)
+	})
+
+	t.Run("rate limited errors with long retry after are returned", func(t *testing.T) {
+		delays = nil
+
+		p := &fake.Provider{}
+		p.On("Delete", "foo").Return(models.RateLimited(errors.New("too many requests"), time.Minute))
+
+		err := Wrap(p, Retry("fake", 3, backoff)).Delete("foo")
+		retryAfter, ok := models.RetryAfter(err)
+		require.True(t, ok)
+		assert.Equal(t, time.Minute, retryAfter)
+		assert.Empty(t, delays)
+		p.AssertNumberOfCalls(t, "Delete", 1)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	p := &fake.Provider{}
+	p.On("List").Return([]*models.Monitor{{Name: "foo"}}, nil)
+
+	limiter := rate.NewLimiter(rate.Limit(1), 2)
+
+	wrapped := Wrap(p, RateLimit(limiter))
+
+	for i := 0; i < 2; i++ {
+		monitors, err := wrapped.List()
+		require.NoError(t, err)
+		assert.Len(t, monitors, 1)
+	}
+
+	// The burst is exhausted, so the next call would have to wait.
+	assert.False(t, limiter.Allow())
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+
+	breaker := NewBreaker("fake", 2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	p := &fake.Provider{}
+	wrapped := Wrap(p, breaker.Middleware())
+
+	p.On("Delete", "bar").Return(models.ErrMonitorNotFound).Once()
+	p.On("Delete", "foo").Return(errTransient).Times(4)
+	p.On("Delete", "foo").Return(nil).Once()
+
+	require.Error(t, wrapped.Delete("foo"))
+	require.Equal(t, BreakerClosed, breaker.State())
+
+	// Errors that do not indicate an unhealthy provider reset the count.
+	require.Equal(t, models.ErrMonitorNotFound, wrapped.Delete("bar"))
+	require.Error(t, wrapped.Delete("foo"))
+	require.Equal(t, BreakerClosed, breaker.State())
+
+	require.Error(t, wrapped.Delete("foo"))
+	require.Equal(t, BreakerOpen, breaker.State())
+	require.Error(t, breaker.HealthCheck(nil))
+
+	err := wrapped.Delete("foo")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	p.AssertNumberOfCalls(t, "Delete", 4)
+
+	// After the cooldown a failing trial call opens the breaker again.
+	now = now.Add(time.Minute)
+	require.Equal(t, BreakerHalfOpen, breaker.State())
+	require.Error(t, wrapped.Delete("foo"))
+	require.Equal(t, BreakerOpen, breaker.State())
+
+	// A successful trial call closes it.
+	now = now.Add(time.Minute)
+	require.NoError(t, wrapped.Delete("foo"))
+	require.Equal(t, BreakerClosed, breaker.State())
+	require.NoError(t, breaker.HealthCheck(nil))
+}
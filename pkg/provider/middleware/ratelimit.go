@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit returns a Middleware which limits the calls to the provider
+// using limiter. Calls block until the limiter permits them, so that all
+// reconciler workers together do not exceed the rate of the limiter.
+func RateLimit(limiter *rate.Limiter) Middleware {
+	return func(p provider.Interface) provider.Interface {
+		return wrap(p, func(call func() error) error {
+			err := limiter.Wait(context.Background())
+			if err != nil {
+				return err
+			}
+
+			return call()
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor/metrics"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// sleep is replaced in tests.
+var sleep = time.Sleep
+
+// Retry returns a Middleware which retries calls that fail with a retryable
+// error (see models.IsRetryable) up to maxRetries times. The delay between
+// the attempts is determined by backoff. Rate limited errors which request a
+// longer delay than the backoff are returned right away, so that the
+// reconciler can requeue the resource after the requested duration instead
+// of blocking a worker. Retries are counted per provider in the provider
+// retries metric.
+//
+// Create is not idempotent: a call that timed out may still have created
+// the monitor. Before a Create is retried, the monitor is therefore looked
+// up by name and the retry is skipped if it exists, so that no duplicate
+// monitors are created.
+func Retry(name string, maxRetries int, backoff wait.Backoff) Middleware {
+	return func(p provider.Interface) provider.Interface {
+		r := &retrier{name: name, maxRetries: maxRetries, backoff: backoff}
+
+		return &retryingProvider{
+			wrapper: wrap(p, func(call func() error) error {
+				return r.do(call, nil)
+			}),
+			retrier: r,
+		}
+	}
+}
+
+type retrier struct {
+	name       string
+	maxRetries int
+	backoff    wait.Backoff
+}
+
+// do runs call and retries it while it fails with a retryable error. If
+// shouldRetry is not nil, it is consulted before each retry. The retries
+// stop if it returns false and the returned error is returned instead.
+func (r *retrier) do(call func() error, shouldRetry func() (bool, error)) error {
+	// Each call gets its own copy of the backoff, as Step mutates it.
+	backoff := r.backoff
+
+	err := call()
+
+	for attempt := 0; attempt < r.maxRetries && models.IsRetryable(err); attempt++ {
+		delay := backoff.Step()
+
+		if retryAfter, ok := models.RetryAfter(err); ok && retryAfter > delay {
+			return err
+		}
+
+		log.V(1).Info("retrying provider call", "provider", r.name, "attempt", attempt+1, "delay", delay, "error", err.Error())
+		metrics.ProviderRetriesTotal.WithLabelValues(r.name).Inc()
+
+		sleep(delay)
+
+		if shouldRetry != nil {
+			retry, checkErr := shouldRetry()
+			if !retry {
+				return checkErr
+			}
+		}
+
+		err = call()
+	}
+
+	return err
+}
+
+// retryingProvider retries all calls via the embedded wrapper, except for
+// Create, which needs special care.
+type retryingProvider struct {
+	*wrapper
+
+	retrier *retrier
+}
+
+// Create implements provider.Interface. A Create is only retried if the
+// monitor does not exist. If it does, the previous attempt succeeded
+// despite the error and the ID of the existing monitor is set on model.
+func (p *retryingProvider) Create(model *models.Monitor) error {
+	var createErr error
+
+	return p.retrier.do(func() error {
+		createErr = p.provider.Create(model)
+		return createErr
+	}, func() (bool, error) {
+		existing, err := p.provider.Get(model.Name)
+		switch {
+		case err == models.ErrMonitorNotFound:
+			return true, nil
+		case err != nil:
+			// The outcome of the previous attempt is unknown, so it is
+			// not safe to create the monitor again.
+			return false, createErr
+		default:
+			log.Info("monitor was created despite error, not retrying", "provider", p.retrier.name, "monitor", model.Name, "error", createErr.Error())
+			model.ID = existing.ID
+			return false, nil
+		}
+	})
+}
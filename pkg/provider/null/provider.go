@@ -1,9 +1,20 @@
 package null
 
 import (
+	"encoding/json"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+func init() {
+	provider.Register(config.ProviderNull, func(_ json.RawMessage, _ client.Client) (provider.Interface, error) {
+		return &Provider{}, nil
+	})
+}
+
 // Provider does not perform any monitor actions. This is useful for testing.
 type Provider struct{}
 
@@ -27,6 +38,16 @@ func (p *Provider) Delete(_ string) error {
 	return nil
 }
 
+// Diff implements provider.Interface.
+func (p *Provider) Diff(_, _ *models.Monitor) (models.FieldDiffs, error) {
+	return nil, nil
+}
+
+// List implements provider.Interface.
+func (p *Provider) List() ([]*models.Monitor, error) {
+	return nil, nil
+}
+
 // GetIPSourceRanges implements provider.Interface.
 func (p *Provider) GetIPSourceRanges(model *models.Monitor) ([]string, error) {
 	// We just whitelist localhost for testing here.
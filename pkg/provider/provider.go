@@ -1,23 +1,31 @@
+// Package provider defines the interface of monitor providers and a registry
+// of the available provider implementations. Providers register themselves
+// in the init function of their package, so the built-in providers and
+// out-of-tree providers alike are compiled in via blank imports.
 package provider
 
 import (
-	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
-	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/null"
-	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/site24x7"
-	"github.com/pkg/errors"
 )
 
 // Interface is the interface for a monitor provider.
 type Interface interface {
 	// Create creates a monitor based on the given model. Must return an error
-	// if the monitor creation fails.
+	// if the monitor creation fails. Providers should set the ID of the model
+	// to the ID of the created monitor.
 	Create(model *models.Monitor) error
 
 	// Get retrieves a monitor by its name. Must return
-	// models.ErrMonitorNotFound if the monitor does not exist.
+	// models.ErrMonitorNotFound if the monitor does not exist. Providers
+	// should set the Config of the returned monitor to its full current
+	// configuration, so that Diff can detect all changes.
 	Get(name string) (*models.Monitor, error)
 
+	// Diff returns the fields in which the existing monitor returned by Get
+	// differs from the desired monitor. The monitor is only updated if the
+	// returned diffs are not empty.
+	Diff(current, desired *models.Monitor) (models.FieldDiffs, error)
+
 	// Update updates a monitor based on the given model. Must return an error
 	// if the monitor update fails.
 	Update(model *models.Monitor) error
@@ -26,6 +34,11 @@ type Interface interface {
 	// deletion fails.
 	Delete(name string) error
 
+	// List lists all monitors of the provider. Only the ID, name and URL of
+	// the returned monitors are required to be set. This is used to find
+	// orphaned monitors.
+	List() ([]*models.Monitor, error)
+
 	// GetIPSourceRanges returns a list of CIDR blocks that the provider is
 	// performing the monitoring checks from. The source ranges are
 	// automatically added to the source range whitelist of the
@@ -33,15 +46,19 @@ type Interface interface {
 	GetIPSourceRanges(model *models.Monitor) ([]string, error)
 }
 
-// New creates a new monitor provider by name. Returns an error if the named
-// provider is not supported.
-func New(name string, c config.ProviderConfig) (Interface, error) {
-	switch name {
-	case config.ProviderSite24x7:
-		return site24x7.NewProvider(c.Site24x7), nil
-	case config.ProviderNull:
-		return &null.Provider{}, nil
-	default:
-		return nil, errors.Errorf("unsupported provider %q", name)
-	}
+// Renderer is implemented by providers that can render the provider specific
+// payload that would be sent for a monitor. It is used to log planned changes
+// in dry run mode. Implementations must redact credentials.
+type Renderer interface {
+	// Render renders the payload for model.
+	Render(model *models.Monitor) (interface{}, error)
+}
+
+// Validator is implemented by providers that validate their provider
+// specific annotations before a monitor is created or updated, so that
+// invalid values are rejected instead of being replaced with zero values.
+type Validator interface {
+	// Validate validates the annotations of model. It must return all
+	// violations at once, preferably as config.AnnotationErrors.
+	Validate(model *models.Monitor) error
 }
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Factory creates a monitor provider from its raw config section. The
+// section is the JSON representation of the value below the provider's name
+// in the provider config file and is empty if the file does not contain it.
+// Factories are responsible for applying their own defaults. The client is
+// passed to providers which manage Kubernetes objects instead of calling an
+// external API.
+type Factory func(rawConfig json.RawMessage, client client.Client) (Interface, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a provider available under name. It is intended to be
+// called from the init function of the package implementing the provider,
+// so that the provider can be compiled in via a blank import. Register
+// panics if factory is nil or if a provider with the same name is already
+// registered.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("provider: Register factory is nil")
+	}
+
+	if _, dup := factories[name]; dup {
+		panic("provider: Register called twice for provider " + name)
+	}
+
+	factories[name] = factory
+}
+
+// Registered returns the sorted names of all registered providers.
+func Registered() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// New creates a new monitor provider by name using the factory registered
+// for it. The config section of the provider is looked up in c and passed
+// to the factory together with the client. Returns an error if no provider
+// is registered under name.
+func New(name string, c config.ProviderConfig, client client.Client) (Interface, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, errors.Errorf("unsupported provider %q", name)
+	}
+
+	p, err := factory(c[name], client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create provider %q", name)
+	}
+
+	return p, nil
+}
+
+// DecodeConfig decodes the raw config section of a provider into v. The
+// fields of v which are not present in rawConfig are left untouched, so v
+// should be initialized with the provider's defaults. It is a no-op if
+// rawConfig is empty.
+func DecodeConfig(rawConfig json.RawMessage, v interface{}) error {
+	if len(rawConfig) == 0 {
+		return nil
+	}
+
+	err := json.Unmarshal(rawConfig, v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode provider config")
+	}
+
+	return nil
+}
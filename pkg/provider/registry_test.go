@@ -0,0 +1,82 @@
+package provider_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type testConfig struct {
+	URL      string `json:"url"`
+	Interval int    `json:"interval"`
+}
+
+func TestRegistry(t *testing.T) {
+	var decoded testConfig
+
+	provider.Register("registry-test", func(rawConfig json.RawMessage, _ client.Client) (provider.Interface, error) {
+		decoded = testConfig{Interval: 60}
+
+		err := provider.DecodeConfig(rawConfig, &decoded)
+		if err != nil {
+			return nil, err
+		}
+
+		return &fake.Provider{}, nil
+	})
+
+	provider.Register("registry-test-failing", func(_ json.RawMessage, _ client.Client) (provider.Interface, error) {
+		return nil, errors.New("whoops")
+	})
+
+	assert.Subset(t, provider.Registered(), []string{"registry-test", "registry-test-failing"})
+
+	t.Run("config section is decoded on top of defaults", func(t *testing.T) {
+		p, err := provider.New("registry-test", config.ProviderConfig{
+			"registry-test": json.RawMessage(`{"url":"http://example.com"}`),
+		}, nil)
+		require.NoError(t, err)
+		assert.NotNil(t, p)
+		assert.Equal(t, testConfig{URL: "http://example.com", Interval: 60}, decoded)
+	})
+
+	t.Run("missing config section yields defaults", func(t *testing.T) {
+		_, err := provider.New("registry-test", nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, testConfig{Interval: 60}, decoded)
+	})
+
+	t.Run("invalid config section", func(t *testing.T) {
+		_, err := provider.New("registry-test", config.ProviderConfig{
+			"registry-test": json.RawMessage(`{"interval":"60s"}`),
+		}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("factory errors are returned", func(t *testing.T) {
+		_, err := provider.New("registry-test-failing", nil, nil)
+		require.Error(t, err)
+		assert.Equal(t, `failed to create provider "registry-test-failing": whoops`, err.Error())
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		_, err := provider.New("nonexistent", nil, nil)
+		require.Error(t, err)
+		assert.Equal(t, `unsupported provider "nonexistent"`, err.Error())
+	})
+
+	t.Run("duplicate registration panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			provider.Register("registry-test", func(_ json.RawMessage, _ client.Client) (provider.Interface, error) {
+				return nil, nil
+			})
+		})
+	})
+}
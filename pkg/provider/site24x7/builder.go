@@ -5,18 +5,22 @@ import (
 	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/cache"
 )
 
 type builder struct {
 	client     site24x7.Client
 	defaults   config.Site24x7MonitorDefaults
 	finalizers []finalizer
+	defaultIDs *cache.Expiring
 }
 
 func newBuilder(client site24x7.Client, defaults config.Site24x7MonitorDefaults) *builder {
 	b := &builder{
-		client:   client,
-		defaults: defaults,
+		client:     client,
+		defaults:   defaults,
+		defaultIDs: cache.NewExpiring(),
 	}
 
 	b.finalizers = []finalizer{
@@ -34,8 +38,13 @@ func (b *builder) FromModel(model *models.Monitor) (*site24x7api.Monitor, error)
 	anno := model.Annotations
 	defaults := b.defaults
 
+	typ, err := monitorType(model.TypeOrDefault())
+	if err != nil {
+		return nil, models.InvalidConfig(err)
+	}
+
 	monitor := &site24x7api.Monitor{
-		Type:        "URL",
+		Type:        typ,
 		MonitorID:   model.ID,
 		DisplayName: model.Name,
 		Website:     model.URL,
@@ -55,9 +64,9 @@ func (b *builder) FromModel(model *models.Monitor) (*site24x7api.Monitor, error)
 	monitor.NotificationProfileID = anno.StringValue(config.AnnotationSite24x7NotificationProfileID, defaults.NotificationProfileID)
 	monitor.ThresholdProfileID = anno.StringValue(config.AnnotationSite24x7ThresholdProfileID, defaults.ThresholdProfileID)
 
-	err := anno.ParseJSON(config.AnnotationSite24x7CustomHeaders, &monitor.CustomHeaders)
+	err = anno.ParseJSON(config.AnnotationSite24x7CustomHeaders, &monitor.CustomHeaders)
 	if err != nil {
-		return nil, err
+		return nil, models.InvalidConfig(err)
 	}
 
 	if monitor.CustomHeaders == nil {
@@ -66,7 +75,7 @@ func (b *builder) FromModel(model *models.Monitor) (*site24x7api.Monitor, error)
 
 	err = anno.ParseJSON(config.AnnotationSite24x7Actions, &monitor.ActionIDs)
 	if err != nil {
-		return nil, err
+		return nil, models.InvalidConfig(err)
 	}
 
 	if monitor.ActionIDs == nil {
@@ -76,6 +85,18 @@ func (b *builder) FromModel(model *models.Monitor) (*site24x7api.Monitor, error)
 	return b.finalizeMonitor(monitor)
 }
 
+// monitorType maps a model monitor type to a Site24x7 monitor type. Only
+// HTTP checks can be represented as website monitors. Site24x7 has no gRPC
+// health check, and its SSL certificate monitors require fields that the
+// API client does not support, so all other types are rejected.
+func monitorType(t models.MonitorType) (string, error) {
+	if t != models.MonitorTypeHTTP {
+		return "", errors.Errorf("site24x7 does not support %s monitors", t)
+	}
+
+	return "URL", nil
+}
+
 func (b *builder) finalizeMonitor(monitor *site24x7api.Monitor) (*site24x7api.Monitor, error) {
 	for _, f := range b.finalizers {
 		if err := f(monitor); err != nil {
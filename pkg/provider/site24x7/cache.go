@@ -0,0 +1,232 @@
+package site24x7
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+)
+
+// monitorCache caches the list of Site24x7 monitors and indexes it by
+// display name and monitor ID. The Site24x7 API does not support looking up monitors by
+// name, so without the cache every Get and Delete would fetch the full
+// monitor list. The list is refreshed once the TTL has passed and after each
+// invalidation. Successful writes patch the cached list in place instead of
+// invalidating it, so that bulk reconciles do not refetch the list after
+// every write. A TTL of zero disables caching.
+type monitorCache struct {
+	sync.Mutex
+
+	ttl       time.Duration
+	load      func() ([]*models.Monitor, error)
+	now       func() time.Time
+	expiresAt time.Time
+	monitors  []*models.Monitor
+	byName    map[string]*models.Monitor
+	byID      map[string]*models.Monitor
+}
+
+// newMonitorCache creates a new *monitorCache which fetches the monitor
+// list using load.
+func newMonitorCache(ttl time.Duration, load func() ([]*models.Monitor, error)) *monitorCache {
+	return &monitorCache{
+		ttl:  ttl,
+		load: load,
+		now:  time.Now,
+	}
+}
+
+// List returns all cached monitors. The cache is refreshed if it is expired.
+func (c *monitorCache) List() ([]*models.Monitor, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	err := c.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	monitors := make([]*models.Monitor, 0, len(c.monitors))
+	for _, monitor := range c.monitors {
+		monitors = append(monitors, cloneMonitor(monitor))
+	}
+
+	return monitors, nil
+}
+
+// GetByName returns the monitor with given display name. Returns
+// models.ErrMonitorNotFound if there is no such monitor.
+func (c *monitorCache) GetByName(name string) (*models.Monitor, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	err := c.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	monitor, ok := c.byName[name]
+	if !ok {
+		return nil, models.ErrMonitorNotFound
+	}
+
+	return cloneMonitor(monitor), nil
+}
+
+// GetByID returns the monitor with given ID. Returns
+// models.ErrMonitorNotFound if there is no such monitor.
+func (c *monitorCache) GetByID(id string) (*models.Monitor, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	err := c.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	monitor, ok := c.byID[id]
+	if !ok {
+		return nil, models.ErrMonitorNotFound
+	}
+
+	return cloneMonitor(monitor), nil
+}
+
+// Invalidate marks the cache as expired, so that the monitor list is fetched
+// again on the next access.
+func (c *monitorCache) Invalidate() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.expiresAt = time.Time{}
+}
+
+// Put adds monitor to the cache or replaces the cached monitor with the
+// same name or ID. A monitor whose display name was changed is thus not
+// cached twice. It is a no-op if the list was not fetched yet, as it will
+// be fetched completely on the next access.
+func (c *monitorCache) Put(monitor *models.Monitor) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.monitors == nil {
+		return
+	}
+
+	monitor = cloneMonitor(monitor)
+
+	if previous, ok := c.byID[monitor.ID]; ok && monitor.ID != "" {
+		c.remove(previous.Name)
+	}
+
+	if _, ok := c.byName[monitor.Name]; ok {
+		c.remove(monitor.Name)
+	}
+
+	c.monitors = append(c.monitors, monitor)
+	c.byName[monitor.Name] = monitor
+
+	if monitor.ID != "" {
+		c.byID[monitor.ID] = monitor
+	}
+}
+
+// Remove removes the monitor with given name from the cache.
+func (c *monitorCache) Remove(name string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.monitors != nil {
+		c.remove(name)
+	}
+}
+
+// remove removes the monitor with given name. Must be called with the lock
+// held.
+func (c *monitorCache) remove(name string) {
+	c.monitors = slices.DeleteFunc(c.monitors, func(monitor *models.Monitor) bool {
+		if monitor.Name != name {
+			return false
+		}
+
+		delete(c.byID, monitor.ID)
+
+		return true
+	})
+
+	delete(c.byName, name)
+}
+
+// refresh fetches the monitor list and rebuilds the indexes if the cache is
+// expired. Must be called with the lock held.
+func (c *monitorCache) refresh() error {
+	if c.monitors != nil && c.now().Before(c.expiresAt) {
+		return nil
+	}
+
+	monitors, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	if monitors == nil {
+		monitors = []*models.Monitor{}
+	}
+
+	c.monitors = monitors
+	c.byName = make(map[string]*models.Monitor, len(monitors))
+	c.byID = make(map[string]*models.Monitor, len(monitors))
+
+	for _, monitor := range monitors {
+		c.byName[monitor.Name] = monitor
+
+		if monitor.ID != "" {
+			c.byID[monitor.ID] = monitor
+		}
+	}
+
+	c.expiresAt = c.now().Add(c.ttl)
+
+	return nil
+}
+
+// cloneMonitor returns a deep copy of monitor, so that callers cannot modify
+// the cached monitors.
+func cloneMonitor(monitor *models.Monitor) *models.Monitor {
+	clone := *monitor
+	clone.Annotations = maps.Clone(monitor.Annotations)
+
+	if config, ok := monitor.Config.(*site24x7api.Monitor); ok && config != nil {
+		clone.Config = cloneAPIMonitor(config)
+	}
+
+	return &clone
+}
+
+// cloneAPIMonitor returns a deep copy of the Site24x7 monitor, which is kept
+// as the Config of cached monitors.
+func cloneAPIMonitor(monitor *site24x7api.Monitor) *site24x7api.Monitor {
+	clone := *monitor
+	clone.MatchingKeyword = clonePointer(monitor.MatchingKeyword)
+	clone.UnmatchingKeyword = clonePointer(monitor.UnmatchingKeyword)
+	clone.MatchRegex = clonePointer(monitor.MatchRegex)
+	clone.CustomHeaders = slices.Clone(monitor.CustomHeaders)
+	clone.MonitorGroups = slices.Clone(monitor.MonitorGroups)
+	clone.UserGroupIDs = slices.Clone(monitor.UserGroupIDs)
+	clone.ActionIDs = slices.Clone(monitor.ActionIDs)
+
+	return &clone
+}
+
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	clone := *p
+
+	return &clone
+}
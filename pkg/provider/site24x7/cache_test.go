@@ -0,0 +1,155 @@
+package site24x7
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorCache(t *testing.T) {
+	loads := 0
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := newMonitorCache(time.Minute, func() ([]*models.Monitor, error) {
+		loads++
+		return []*models.Monitor{
+			{ID: "123", Name: "my-monitor"},
+			{ID: "456", Name: "other-monitor"},
+		}, nil
+	})
+	c.now = func() time.Time { return now }
+
+	monitor, err := c.GetByName("my-monitor")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Monitor{ID: "123", Name: "my-monitor"}, monitor)
+
+	monitor, err = c.GetByName("other-monitor")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Monitor{ID: "456", Name: "other-monitor"}, monitor)
+
+	_, err = c.GetByName("nonexistent")
+	assert.Equal(t, models.ErrMonitorNotFound, err)
+
+	monitor, err = c.GetByID("456")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Monitor{ID: "456", Name: "other-monitor"}, monitor)
+
+	_, err = c.GetByID("nonexistent")
+	assert.Equal(t, models.ErrMonitorNotFound, err)
+
+	monitors, err := c.List()
+	require.NoError(t, err)
+	assert.Len(t, monitors, 2)
+	assert.Equal(t, 1, loads)
+
+	// Modifying returned monitors must not modify the cache.
+	monitors[0].Name = "modified"
+
+	monitor, err = c.GetByName("my-monitor")
+	require.NoError(t, err)
+	assert.Equal(t, "123", monitor.ID)
+	assert.Equal(t, 1, loads)
+
+	// Writes patch the cache in place. Monitors are replaced by name or ID.
+	c.Put(&models.Monitor{ID: "789", Name: "new-monitor"})
+	c.Put(&models.Monitor{ID: "123", Name: "my-monitor", URL: "http://foo"})
+	c.Put(&models.Monitor{ID: "789", Name: "renamed-monitor"})
+	c.Remove("other-monitor")
+
+	monitors, err = c.List()
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Monitor{
+		{ID: "123", Name: "my-monitor", URL: "http://foo"},
+		{ID: "789", Name: "renamed-monitor"},
+	}, monitors)
+
+	_, err = c.GetByName("other-monitor")
+	assert.Equal(t, models.ErrMonitorNotFound, err)
+
+	_, err = c.GetByName("new-monitor")
+	assert.Equal(t, models.ErrMonitorNotFound, err)
+
+	_, err = c.GetByID("456")
+	assert.Equal(t, models.ErrMonitorNotFound, err)
+	assert.Equal(t, 1, loads)
+
+	now = now.Add(time.Minute)
+
+	_, err = c.List()
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "expected refresh after TTL expired")
+
+	c.Invalidate()
+
+	_, err = c.List()
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads, "expected refresh after invalidation")
+}
+
+func TestMonitorCache_DeepCopy(t *testing.T) {
+	c := newMonitorCache(time.Minute, func() ([]*models.Monitor, error) {
+		return []*models.Monitor{newMonitorModel(&site24x7api.Monitor{
+			MonitorID:     "123",
+			DisplayName:   "my-monitor",
+			MonitorGroups: []string{"456"},
+		})}, nil
+	})
+
+	monitor, err := c.GetByName("my-monitor")
+	require.NoError(t, err)
+
+	// Modifying the config of returned monitors must not modify the cache.
+	monitor.Config.(*site24x7api.Monitor).MonitorGroups[0] = "modified"
+	monitor.Config.(*site24x7api.Monitor).Website = "http://modified"
+
+	monitor, err = c.GetByID("123")
+	require.NoError(t, err)
+	assert.Equal(t, &site24x7api.Monitor{
+		MonitorID:     "123",
+		DisplayName:   "my-monitor",
+		MonitorGroups: []string{"456"},
+	}, monitor.Config)
+
+	c.Put(&models.Monitor{ID: "789", Name: "other-monitor", Annotations: map[string]string{"foo": "bar"}})
+
+	monitor, err = c.GetByName("other-monitor")
+	require.NoError(t, err)
+
+	monitor.Annotations["foo"] = "modified"
+
+	monitor, err = c.GetByName("other-monitor")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", monitor.Annotations["foo"])
+}
+
+func TestMonitorCache_ZeroTTL(t *testing.T) {
+	loads := 0
+
+	c := newMonitorCache(0, func() ([]*models.Monitor, error) {
+		loads++
+		return nil, nil
+	})
+
+	for range 3 {
+		monitors, err := c.List()
+		require.NoError(t, err)
+		assert.Empty(t, monitors)
+	}
+
+	assert.Equal(t, 3, loads)
+}
+
+func TestMonitorCache_LoadError(t *testing.T) {
+	c := newMonitorCache(time.Minute, func() ([]*models.Monitor, error) {
+		return nil, errors.New("whoops")
+	})
+
+	_, err := c.GetByName("my-monitor")
+	require.Error(t, err)
+	assert.Equal(t, "whoops", err.Error())
+}
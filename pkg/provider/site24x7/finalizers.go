@@ -2,28 +2,59 @@ package site24x7
 
 import (
 	"errors"
+	"time"
 
 	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
 )
 
+// defaultIDCacheTTL is the duration for which the automatically selected
+// profile and group IDs are cached. Without caching, building a monitor,
+// e.g. to diff it against an up to date monitor, would call the Site24x7
+// API on every reconcile.
+const defaultIDCacheTTL = time.Hour
+
 // finalizer finalizes the configuration of a Site24x7 website monitor.
 type finalizer func(*site24x7api.Monitor) error
 
+// defaultID returns the ID that list selects for key. The result is cached
+// for defaultIDCacheTTL.
+func (b *builder) defaultID(key string, list func() (string, error)) (string, error) {
+	if id, ok := b.defaultIDs.Get(key); ok {
+		return id.(string), nil
+	}
+
+	id, err := list()
+	if err != nil {
+		return "", err
+	}
+
+	b.defaultIDs.Set(key, id, defaultIDCacheTTL)
+
+	return id, nil
+}
+
 func (b *builder) finalizeLocationProfile(monitor *site24x7api.Monitor) error {
 	if monitor.LocationProfileID != "" || !b.defaults.AutoLocationProfile {
 		return nil
 	}
 
-	profiles, err := b.client.LocationProfiles().List()
+	id, err := b.defaultID("location-profile", func() (string, error) {
+		profiles, err := b.client.LocationProfiles().List()
+		if err != nil {
+			return "", err
+		}
+
+		if len(profiles) == 0 {
+			return "", errors.New("no location profiles configured")
+		}
+
+		return profiles[0].ProfileID, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(profiles) == 0 {
-		return errors.New("no location profiles configured")
-	}
-
-	monitor.LocationProfileID = profiles[0].ProfileID
+	monitor.LocationProfileID = id
 
 	return nil
 }
@@ -33,16 +64,23 @@ func (b *builder) finalizeNotificationProfile(monitor *site24x7api.Monitor) erro
 		return nil
 	}
 
-	profiles, err := b.client.NotificationProfiles().List()
+	id, err := b.defaultID("notification-profile", func() (string, error) {
+		profiles, err := b.client.NotificationProfiles().List()
+		if err != nil {
+			return "", err
+		}
+
+		if len(profiles) == 0 {
+			return "", errors.New("no notification profiles configured")
+		}
+
+		return profiles[0].ProfileID, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(profiles) == 0 {
-		return errors.New("no notification profiles configured")
-	}
-
-	monitor.NotificationProfileID = profiles[0].ProfileID
+	monitor.NotificationProfileID = id
 
 	return nil
 }
@@ -52,16 +90,23 @@ func (b *builder) finalizeThresholdProfile(monitor *site24x7api.Monitor) error {
 		return nil
 	}
 
-	profiles, err := b.client.ThresholdProfiles().List()
+	id, err := b.defaultID("threshold-profile", func() (string, error) {
+		profiles, err := b.client.ThresholdProfiles().List()
+		if err != nil {
+			return "", err
+		}
+
+		if len(profiles) == 0 {
+			return "", errors.New("no threshold profiles configured")
+		}
+
+		return profiles[0].ProfileID, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(profiles) == 0 {
-		return errors.New("no threshold profiles configured")
-	}
-
-	monitor.ThresholdProfileID = profiles[0].ProfileID
+	monitor.ThresholdProfileID = id
 
 	return nil
 }
@@ -71,16 +116,23 @@ func (b *builder) finalizeMonitorGroup(monitor *site24x7api.Monitor) error {
 		return nil
 	}
 
-	groups, err := b.client.MonitorGroups().List()
+	id, err := b.defaultID("monitor-group", func() (string, error) {
+		groups, err := b.client.MonitorGroups().List()
+		if err != nil {
+			return "", err
+		}
+
+		if len(groups) == 0 {
+			return "", errors.New("no monitor groups configured")
+		}
+
+		return groups[0].GroupID, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(groups) == 0 {
-		return errors.New("no monitor groups configured")
-	}
-
-	monitor.MonitorGroups = []string{groups[0].GroupID}
+	monitor.MonitorGroups = []string{id}
 
 	return nil
 }
@@ -90,16 +142,23 @@ func (b *builder) finalizeUserGroup(monitor *site24x7api.Monitor) error {
 		return nil
 	}
 
-	groups, err := b.client.UserGroups().List()
+	id, err := b.defaultID("user-group", func() (string, error) {
+		groups, err := b.client.UserGroups().List()
+		if err != nil {
+			return "", err
+		}
+
+		if len(groups) == 0 {
+			return "", errors.New("no user groups configured")
+		}
+
+		return groups[0].UserGroupID, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(groups) == 0 {
-		return errors.New("no user groups configured")
-	}
-
-	monitor.UserGroupIDs = []string{groups[0].UserGroupID}
+	monitor.UserGroupIDs = []string{id}
 
 	return nil
 }
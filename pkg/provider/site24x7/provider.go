@@ -1,14 +1,18 @@
 package site24x7
 
 import (
+	"encoding/json"
 	"time"
 
 	site24x7 "github.com/Bonial-International-GmbH/site24x7-go"
+	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
 	"github.com/Bonial-International-GmbH/site24x7-go/location"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -21,6 +25,7 @@ type Provider struct {
 	ipProvider       *location.ProfileIPProvider
 	builder          *builder
 	sourceRangeCache *cache.Expiring
+	monitorCache     *monitorCache
 }
 
 // NewProvider creates a new Site24x7 provider with given Site24x7Config.
@@ -31,12 +36,33 @@ func NewProvider(config config.Site24x7Config) *Provider {
 		RefreshToken: config.RefreshToken,
 	})
 
-	return &Provider{
+	p := &Provider{
 		client:           client,
 		config:           config,
 		builder:          newBuilder(client, config.MonitorDefaults),
 		sourceRangeCache: cache.NewExpiring(),
 	}
+
+	p.monitorCache = newMonitorCache(config.MonitorCacheTTL.Duration, p.listMonitors)
+
+	return p
+}
+
+func init() {
+	provider.Register(config.ProviderSite24x7, newFromConfig)
+}
+
+// newFromConfig is the provider.Factory of the Site24x7 provider. It decodes
+// rawConfig on top of the default config.
+func newFromConfig(rawConfig json.RawMessage, _ client.Client) (provider.Interface, error) {
+	c := config.NewDefaultSite24x7Config()
+
+	err := provider.DecodeConfig(rawConfig, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProvider(c), nil
 }
 
 // Create implements provider.Interface.
@@ -46,36 +72,112 @@ func (p *Provider) Create(model *models.Monitor) error {
 		return errors.Wrapf(err, "failed to build site24x7 monitor from model: %#v", model)
 	}
 
-	_, err = p.client.Monitors().Create(monitor)
+	created, err := p.client.Monitors().Create(monitor)
 	if err != nil {
+		// The monitor may have been created nevertheless.
+		p.monitorCache.Invalidate()
 		return errors.Wrapf(err, "failed to create site24x7 monitor: %#v", monitor)
 	}
 
+	if created == nil {
+		p.monitorCache.Invalidate()
+		return nil
+	}
+
+	model.ID = created.MonitorID
+	p.monitorCache.Put(newMonitorModel(created))
+
 	return nil
 }
 
-// Create implements provider.Interface.
+// Get implements provider.Interface. The monitor is looked up in the monitor
+// cache.
 func (p *Provider) Get(name string) (*models.Monitor, error) {
+	return p.monitorCache.GetByName(name)
+}
+
+// List implements provider.Interface. The monitors are served from the
+// monitor cache.
+func (p *Provider) List() ([]*models.Monitor, error) {
+	return p.monitorCache.List()
+}
+
+// listMonitors fetches all monitors from the Site24x7 API.
+func (p *Provider) listMonitors() ([]*models.Monitor, error) {
 	monitors, err := p.client.Monitors().List()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to list site24x7 monitors")
 	}
 
+	result := make([]*models.Monitor, 0, len(monitors))
+
 	for _, monitor := range monitors {
-		if monitor.DisplayName != name {
-			continue
-		}
+		result = append(result, newMonitorModel(monitor))
+	}
 
-		m := &models.Monitor{
-			ID:   monitor.MonitorID,
-			Name: monitor.DisplayName,
-			URL:  monitor.Website,
-		}
+	return result, nil
+}
+
+// newMonitorModel converts a Site24x7 monitor into a model. The full
+// monitor is kept as the Config of the model, so that Diff can compare all
+// fields.
+func newMonitorModel(monitor *site24x7api.Monitor) *models.Monitor {
+	return &models.Monitor{
+		ID:     monitor.MonitorID,
+		Name:   monitor.DisplayName,
+		URL:    monitor.Website,
+		Config: monitor,
+	}
+}
 
-		return m, nil
+// Diff implements provider.Interface. It compares all fields that are set by
+// the builder.
+func (p *Provider) Diff(current, desired *models.Monitor) (models.FieldDiffs, error) {
+	actual, ok := current.Config.(*site24x7api.Monitor)
+	if !ok {
+		return models.UnknownConfig(), nil
 	}
 
-	return nil, models.ErrMonitorNotFound
+	monitor, err := p.builder.FromModel(desired)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build site24x7 monitor from model: %#v", desired)
+	}
+
+	var diffs models.FieldDiffs
+
+	diffs.Compare("Type", actual.Type, monitor.Type)
+	diffs.Compare("Website", actual.Website, monitor.Website)
+	diffs.Compare("CheckFrequency", actual.CheckFrequency, monitor.CheckFrequency)
+	diffs.Compare("HTTPMethod", actual.HTTPMethod, monitor.HTTPMethod)
+	diffs.Compare("AuthUser", actual.AuthUser, monitor.AuthUser)
+	diffs.CompareSensitive("AuthPass", actual.AuthPass, monitor.AuthPass)
+	diffs.Compare("MatchCase", actual.MatchCase, monitor.MatchCase)
+	diffs.Compare("UserAgent", actual.UserAgent, monitor.UserAgent)
+	diffs.Compare("Timeout", actual.Timeout, monitor.Timeout)
+	diffs.Compare("UseNameServer", actual.UseNameServer, monitor.UseNameServer)
+	diffs.Compare("UserGroupIDs", actual.UserGroupIDs, monitor.UserGroupIDs)
+	diffs.Compare("MonitorGroups", actual.MonitorGroups, monitor.MonitorGroups)
+	diffs.Compare("LocationProfileID", actual.LocationProfileID, monitor.LocationProfileID)
+	diffs.Compare("NotificationProfileID", actual.NotificationProfileID, monitor.NotificationProfileID)
+	diffs.Compare("ThresholdProfileID", actual.ThresholdProfileID, monitor.ThresholdProfileID)
+	diffs.Compare("CustomHeaders", actual.CustomHeaders, monitor.CustomHeaders)
+	diffs.Compare("ActionIDs", actual.ActionIDs, monitor.ActionIDs)
+
+	return diffs, nil
+}
+
+// Render implements provider.Renderer. The basic auth password is redacted.
+func (p *Provider) Render(model *models.Monitor) (interface{}, error) {
+	monitor, err := p.builder.FromModel(model)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build site24x7 monitor from model: %#v", model)
+	}
+
+	if monitor.AuthPass != "" {
+		monitor.AuthPass = "<redacted>"
+	}
+
+	return monitor, nil
 }
 
 // Create implements provider.Interface.
@@ -85,11 +187,18 @@ func (p *Provider) Update(model *models.Monitor) error {
 		return errors.Wrapf(err, "failed to build site24x7 monitor from model: %#v", model)
 	}
 
-	_, err = p.client.Monitors().Update(monitor)
+	updated, err := p.client.Monitors().Update(monitor)
 	if err != nil {
+		p.monitorCache.Invalidate()
 		return errors.Wrapf(err, "failed to update site24x7 monitor: %#v", monitor)
 	}
 
+	if updated == nil {
+		updated = monitor
+	}
+
+	p.monitorCache.Put(newMonitorModel(updated))
+
 	return nil
 }
 
@@ -102,9 +211,12 @@ func (p *Provider) Delete(name string) error {
 
 	err = p.client.Monitors().Delete(monitor.ID)
 	if err != nil {
+		p.monitorCache.Invalidate()
 		return errors.Wrapf(err, "failed to delete site24x7 monitor with ID %s", monitor.ID)
 	}
 
+	p.monitorCache.Remove(name)
+
 	return nil
 }
 
@@ -3,6 +3,7 @@ package site24x7
 import (
 	"errors"
 	"testing"
+	"time"
 
 	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
 	"github.com/Bonial-International-GmbH/site24x7-go/fake"
@@ -10,7 +11,9 @@ import (
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
 	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/cache"
 )
 
@@ -35,9 +38,38 @@ func TestProvider_Create(t *testing.T) {
 					Website:     "http://my-monitor",
 					Type:        "URL",
 				}
-				c.FakeMonitors.On("Create", monitor).Return(monitor, nil)
+				c.FakeMonitors.On("Create", monitor).Return(&site24x7api.Monitor{
+					MonitorID:   "123",
+					DisplayName: "my-monitor",
+					Website:     "http://my-monitor",
+					Type:        "URL",
+				}, nil)
 			},
 		},
+		{
+			name: "do not create monitor for tls endpoints",
+			model: &models.Monitor{
+				Name: "my-monitor",
+				Type: models.MonitorTypeTLS,
+				URL:  "https://my-monitor:6443",
+			},
+			validate: func(t *testing.T, c *fake.Client) {
+				assert.Len(t, c.FakeMonitors.Calls, 0)
+			},
+			expected: errors.New(`failed to build site24x7 monitor from model: &models.Monitor{ID:"", Name:"my-monitor", Namespace:"", Type:"TLS", URL:"https://my-monitor:6443", Annotations:config.Annotations(nil), Config:interface {}(nil)}: site24x7 does not support TLS monitors`),
+		},
+		{
+			name: "do not create monitor for grpc endpoints",
+			model: &models.Monitor{
+				Name: "my-monitor",
+				Type: models.MonitorTypeGRPC,
+				URL:  "https://my-monitor",
+			},
+			validate: func(t *testing.T, c *fake.Client) {
+				assert.Len(t, c.FakeMonitors.Calls, 0)
+			},
+			expected: errors.New(`failed to build site24x7 monitor from model: &models.Monitor{ID:"", Name:"my-monitor", Namespace:"", Type:"GRPC", URL:"https://my-monitor", Annotations:config.Annotations(nil), Config:interface {}(nil)}: site24x7 does not support GRPC monitors`),
+		},
 		{
 			name: "do not create monitor if the ingress annotations are invalid",
 			model: &models.Monitor{
@@ -50,7 +82,7 @@ func TestProvider_Create(t *testing.T) {
 			validate: func(t *testing.T, c *fake.Client) {
 				assert.Len(t, c.FakeMonitors.Calls, 0)
 			},
-			expected: errors.New(`failed to build site24x7 monitor from model: &models.Monitor{ID:"", Name:"my-monitor", URL:"http://my-monitor", Annotations:config.Annotations{"site24x7.ingress-monitor.bonial.com/actions":"{invalidjson"}}: invalid json in annotation "site24x7.ingress-monitor.bonial.com/actions": {invalidjson: invalid character 'i' looking for beginning of object key string`),
+			expected: errors.New(`failed to build site24x7 monitor from model: &models.Monitor{ID:"", Name:"my-monitor", Namespace:"", Type:"", URL:"http://my-monitor", Annotations:config.Annotations{"site24x7.ingress-monitor.bonial.com/actions":"{invalidjson"}, Config:interface {}(nil)}: invalid json in annotation "site24x7.ingress-monitor.bonial.com/actions": {invalidjson: invalid character 'i' looking for beginning of object key string`),
 		},
 	}
 
@@ -68,6 +100,7 @@ func TestProvider_Create(t *testing.T) {
 				assert.Equal(t, test.expected.Error(), err.Error())
 			} else {
 				require.NoError(t, err)
+				assert.Equal(t, "123", test.model.ID)
 			}
 
 			if test.validate != nil {
@@ -177,7 +210,7 @@ func TestProvider_Update(t *testing.T) {
 			setup: func(c *fake.Client) {
 				c.FakeLocationProfiles.On("List").Return(nil, nil)
 			},
-			expected: errors.New(`failed to build site24x7 monitor from model: &models.Monitor{ID:"", Name:"my-monitor", URL:"http://my-monitor", Annotations:config.Annotations(nil)}: no location profiles configured`),
+			expected: errors.New(`failed to build site24x7 monitor from model: &models.Monitor{ID:"", Name:"my-monitor", Namespace:"", Type:"", URL:"http://my-monitor", Annotations:config.Annotations(nil), Config:interface {}(nil)}: no location profiles configured`),
 		},
 	}
 
@@ -242,6 +275,11 @@ func TestProvider_Get(t *testing.T) {
 				ID:   "123",
 				Name: "my-monitor",
 				URL:  "http://my-monitor",
+				Config: &site24x7api.Monitor{
+					MonitorID:   "123",
+					DisplayName: "my-monitor",
+					Website:     "http://my-monitor",
+				},
 			},
 		},
 	}
@@ -270,6 +308,138 @@ func TestProvider_Get(t *testing.T) {
 	}
 }
 
+func TestProvider_Diff(t *testing.T) {
+	p, _ := newTestProvider(config.Site24x7Config{
+		MonitorDefaults: config.Site24x7MonitorDefaults{
+			CheckFrequency: "1",
+			HTTPMethod:     "G",
+			Timeout:        10,
+		},
+	})
+
+	current := &models.Monitor{
+		ID:   "123",
+		Name: "my-monitor",
+		Config: &site24x7api.Monitor{
+			MonitorID:      "123",
+			DisplayName:    "my-monitor",
+			Type:           "URL",
+			Website:        "http://my-monitor",
+			CheckFrequency: "1",
+			HTTPMethod:     "G",
+			Timeout:        10,
+			AuthPass:       "secret",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		current  *models.Monitor
+		desired  *models.Monitor
+		expected models.FieldDiffs
+	}{
+		{
+			name:    "up to date monitor",
+			current: current,
+			desired: &models.Monitor{
+				ID:   "123",
+				Name: "my-monitor",
+				URL:  "http://my-monitor",
+				Annotations: config.Annotations{
+					config.AnnotationSite24x7AuthPass: "secret",
+				},
+			},
+		},
+		{
+			name:    "changed fields",
+			current: current,
+			desired: &models.Monitor{
+				ID:   "123",
+				Name: "my-monitor",
+				URL:  "https://my-monitor",
+				Annotations: config.Annotations{
+					config.AnnotationSite24x7CheckFrequency: "5",
+				},
+			},
+			expected: models.FieldDiffs{
+				{Field: "Website", Current: "http://my-monitor", Desired: "https://my-monitor"},
+				{Field: "CheckFrequency", Current: "1", Desired: "5"},
+				{Field: "AuthPass", Current: "<redacted>", Desired: "<redacted>"},
+			},
+		},
+		{
+			name:     "monitor without config",
+			current:  &models.Monitor{ID: "123", Name: "my-monitor"},
+			desired:  &models.Monitor{ID: "123", Name: "my-monitor"},
+			expected: models.UnknownConfig(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diffs, err := p.Diff(test.current, test.desired)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, diffs)
+		})
+	}
+}
+
+func TestProvider_Diff_CachesDefaultProfiles(t *testing.T) {
+	p, c := newTestProvider(config.Site24x7Config{
+		MonitorDefaults: config.Site24x7MonitorDefaults{
+			CheckFrequency:      "1",
+			HTTPMethod:          "G",
+			Timeout:             10,
+			AutoLocationProfile: true,
+		},
+	})
+
+	c.FakeLocationProfiles.On("List").Return([]*site24x7api.LocationProfile{
+		{ProfileID: "456"},
+	}, nil)
+
+	current := &models.Monitor{
+		ID:   "123",
+		Name: "my-monitor",
+		Config: &site24x7api.Monitor{
+			MonitorID:         "123",
+			DisplayName:       "my-monitor",
+			Type:              "URL",
+			Website:           "http://my-monitor",
+			CheckFrequency:    "1",
+			HTTPMethod:        "G",
+			Timeout:           10,
+			LocationProfileID: "456",
+		},
+	}
+
+	desired := &models.Monitor{ID: "123", Name: "my-monitor", URL: "http://my-monitor"}
+
+	for range 3 {
+		diffs, err := p.Diff(current, desired)
+		require.NoError(t, err)
+		assert.Empty(t, diffs)
+	}
+
+	c.FakeLocationProfiles.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestProvider_List(t *testing.T) {
+	p, c := newTestProvider(config.Site24x7Config{})
+
+	c.FakeMonitors.On("List").Return([]*site24x7api.Monitor{
+		{MonitorID: "123", DisplayName: "my-monitor", Website: "http://my-monitor"},
+		{MonitorID: "456", DisplayName: "other-monitor", Website: "http://other"},
+	}, nil)
+
+	monitors, err := p.List()
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Monitor{
+		{ID: "123", Name: "my-monitor", URL: "http://my-monitor", Config: &site24x7api.Monitor{MonitorID: "123", DisplayName: "my-monitor", Website: "http://my-monitor"}},
+		{ID: "456", Name: "other-monitor", URL: "http://other", Config: &site24x7api.Monitor{MonitorID: "456", DisplayName: "other-monitor", Website: "http://other"}},
+	}, monitors)
+}
+
 func TestProvider_Delete(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -524,5 +694,105 @@ func newTestProvider(config config.Site24x7Config) (*Provider, *fake.Client) {
 		sourceRangeCache: cache.NewExpiring(),
 	}
 
+	provider.monitorCache = newMonitorCache(config.MonitorCacheTTL.Duration, provider.listMonitors)
+
 	return provider, client
 }
+
+func TestProvider_MonitorCache(t *testing.T) {
+	p, c := newTestProvider(config.Site24x7Config{
+		MonitorCacheTTL: metav1.Duration{Duration: time.Minute},
+	})
+
+	c.FakeMonitors.On("List").Return([]*site24x7api.Monitor{
+		{MonitorID: "123", DisplayName: "my-monitor"},
+	}, nil)
+	c.FakeMonitors.On("Delete", "123").Return(nil)
+
+	_, err := p.Get("my-monitor")
+	require.NoError(t, err)
+
+	_, err = p.Get("my-monitor")
+	require.NoError(t, err)
+
+	err = p.Delete("my-monitor")
+	require.NoError(t, err)
+
+	// Delete removes the monitor from the cache instead of invalidating it.
+	_, err = p.Get("my-monitor")
+	assert.Equal(t, models.ErrMonitorNotFound, err)
+
+	// Create adds the monitor to the cache.
+	c.FakeMonitors.On("Create", mock.Anything).Return(&site24x7api.Monitor{MonitorID: "456", DisplayName: "my-monitor"}, nil)
+
+	err = p.Create(&models.Monitor{Name: "my-monitor", URL: "http://foo.bar.baz"})
+	require.NoError(t, err)
+
+	monitor, err := p.Get("my-monitor")
+	require.NoError(t, err)
+	assert.Equal(t, "456", monitor.ID)
+
+	c.FakeMonitors.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestProvider_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		monitorType models.MonitorType
+		annotations config.Annotations
+		expected    string
+	}{
+		{
+			name: "valid annotations",
+			annotations: config.Annotations{
+				config.AnnotationSite24x7CheckFrequency: "5",
+				config.AnnotationSite24x7HTTPMethod:     "H",
+				config.AnnotationSite24x7Timeout:        "45",
+				config.AnnotationSite24x7MatchCase:      "true",
+				config.AnnotationSite24x7CustomHeaders:  `[{"name":"Content-Type","value":"application/json"}]`,
+				config.AnnotationSite24x7Actions:        `[{"action_id":"123","alert_type":0}]`,
+			},
+		},
+		{
+			name:        "unsupported monitor type",
+			monitorType: models.MonitorTypeTLS,
+			expected:    "site24x7 does not support TLS monitors",
+		},
+		{
+			name:        "invalid timeout",
+			annotations: config.Annotations{config.AnnotationSite24x7Timeout: "abc"},
+			expected:    `annotation "site24x7.ingress-monitor.bonial.com/timeout": invalid integer "abc"`,
+		},
+		{
+			name: "multiple violations",
+			annotations: config.Annotations{
+				config.AnnotationSite24x7CheckFrequency:          "2",
+				config.AnnotationSite24x7HTTPMethod:              "GET",
+				config.AnnotationSite24x7Timeout:                 "60",
+				config.AnnotationSite24x7CustomHeaders:           `[{"value":"application/json"}]`,
+				config.AnnotationSite24x7Actions:                 `{"action_id":"123"}`,
+				"site24x7.ingress-monitor.bonial.com/check-freq": "5",
+			},
+			expected: `annotation "site24x7.ingress-monitor.bonial.com/check-freq": unknown annotation; ` +
+				`annotation "site24x7.ingress-monitor.bonial.com/check-frequency": "2" is not one of 1, 5, 10, 15, 20, 30, 60, 120, 1440; ` +
+				`annotation "site24x7.ingress-monitor.bonial.com/http-method": "GET" is not one of G, P, H, U, A, D; ` +
+				`annotation "site24x7.ingress-monitor.bonial.com/timeout": 60 is not in range 1-45; ` +
+				`annotation "site24x7.ingress-monitor.bonial.com/custom-headers": header 0 has no name; ` +
+				`annotation "site24x7.ingress-monitor.bonial.com/actions": invalid json: json: cannot unmarshal object into Go value of type []api.ActionRef`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &Provider{}
+
+			err := p.Validate(&models.Monitor{Type: test.monitorType, Annotations: test.annotations})
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, test.expected, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package site24x7
+
+import (
+	site24x7api "github.com/Bonial-International-GmbH/site24x7-go/api"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+)
+
+var (
+	// checkFrequencies are the check intervals in minutes supported by
+	// Site24x7, see https://www.site24x7.com/help/api/#check_interval.
+	checkFrequencies = []string{"1", "5", "10", "15", "20", "30", "60", "120", "1440"}
+
+	// httpMethods are the HTTP method codes supported by Site24x7, see
+	// https://www.site24x7.com/help/api/#http_methods.
+	httpMethods = []string{"G", "P", "H", "U", "A", "D"}
+
+	knownAnnotations = []string{
+		config.AnnotationSite24x7Actions,
+		config.AnnotationSite24x7AuthPass,
+		config.AnnotationSite24x7AuthUser,
+		config.AnnotationSite24x7CheckFrequency,
+		config.AnnotationSite24x7CustomHeaders,
+		config.AnnotationSite24x7HTTPMethod,
+		config.AnnotationSite24x7LocationProfileID,
+		config.AnnotationSite24x7MatchCase,
+		config.AnnotationSite24x7MonitorGroupIDs,
+		config.AnnotationSite24x7NotificationProfileID,
+		config.AnnotationSite24x7ThresholdProfileID,
+		config.AnnotationSite24x7Timeout,
+		config.AnnotationSite24x7UseNameServer,
+		config.AnnotationSite24x7UserAgent,
+		config.AnnotationSite24x7UserGroupIDs,
+	}
+)
+
+// Validate implements provider.Validator. Monitors of types that Site24x7
+// cannot represent are rejected, see monitorType.
+func (p *Provider) Validate(model *models.Monitor) error {
+	_, err := monitorType(model.TypeOrDefault())
+	if err != nil {
+		return err
+	}
+
+	return validateAnnotations(model.Annotations)
+}
+
+func validateAnnotations(anno config.Annotations) error {
+	v := config.NewAnnotationValidator(anno)
+
+	v.UnknownKeys(config.AnnotationPrefixSite24x7, knownAnnotations...)
+	v.OneOf(config.AnnotationSite24x7CheckFrequency, checkFrequencies...)
+	v.OneOf(config.AnnotationSite24x7HTTPMethod, httpMethods...)
+	v.Int(config.AnnotationSite24x7Timeout, 1, 45)
+	v.Bool(config.AnnotationSite24x7MatchCase)
+	v.Bool(config.AnnotationSite24x7UseNameServer)
+
+	var headers []site24x7api.Header
+	if v.JSON(config.AnnotationSite24x7CustomHeaders, &headers) {
+		for i, header := range headers {
+			if header.Name == "" {
+				v.Errorf(config.AnnotationSite24x7CustomHeaders, "header %d has no name", i)
+			}
+		}
+	}
+
+	var actions []site24x7api.ActionRef
+	if v.JSON(config.AnnotationSite24x7Actions, &actions) {
+		for i, action := range actions {
+			if action.ActionID == "" {
+				v.Errorf(config.AnnotationSite24x7Actions, "action %d has no action_id", i)
+			}
+		}
+	}
+
+	return v.Err()
+}
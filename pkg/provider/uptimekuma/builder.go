@@ -0,0 +1,146 @@
+package uptimekuma
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+)
+
+// healthProtobuf is the definition of the gRPC health checking protocol
+// which is used by gRPC monitors.
+const healthProtobuf = `syntax = "proto3";
+
+package grpc.health.v1;
+
+message HealthCheckRequest {
+  string service = 1;
+}
+
+message HealthCheckResponse {
+  enum ServingStatus {
+    UNKNOWN = 0;
+    SERVING = 1;
+    NOT_SERVING = 2;
+    SERVICE_UNKNOWN = 3;
+  }
+  ServingStatus status = 1;
+}
+
+service Health {
+  rpc Check(HealthCheckRequest) returns (HealthCheckResponse);
+}
+`
+
+type builder struct {
+	defaults config.UptimeKumaMonitorDefaults
+}
+
+func newBuilder(defaults config.UptimeKumaMonitorDefaults) *builder {
+	return &builder{
+		defaults: defaults,
+	}
+}
+
+func (b *builder) FromModel(model *models.Monitor) (*monitor, error) {
+	anno := model.Annotations
+	defaults := b.defaults
+
+	m := &monitor{
+		Type: "http",
+		Name: model.Name,
+		URL:  model.URL,
+	}
+
+	if model.ID != "" {
+		id, err := strconv.Atoi(model.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid uptime kuma monitor ID %q", model.ID)
+		}
+
+		m.ID = id
+	}
+
+	m.Method = anno.StringValue(config.AnnotationUptimeKumaMethod, defaults.Method)
+	m.Interval = anno.IntValue(config.AnnotationUptimeKumaInterval, defaults.Interval)
+	m.RetryInterval = anno.IntValue(config.AnnotationUptimeKumaRetryInterval, defaults.RetryInterval)
+	m.MaxRetries = anno.IntValue(config.AnnotationUptimeKumaMaxRetries, defaults.MaxRetries)
+	m.MaxRedirects = anno.IntValue(config.AnnotationUptimeKumaMaxRedirects, defaults.MaxRedirects)
+	m.AcceptedStatusCodes = anno.StringSliceValue(config.AnnotationUptimeKumaAcceptedStatusCodes, defaults.AcceptedStatusCodes)
+	m.IgnoreTLS = anno.BoolValue(config.AnnotationUptimeKumaIgnoreTLS, defaults.IgnoreTLS)
+	m.NotificationIDs = defaults.NotificationIDs
+
+	err := setMonitorType(m, model)
+	if err != nil {
+		return nil, models.InvalidConfig(err)
+	}
+
+	if ids := anno.StringSliceValue(config.AnnotationUptimeKumaNotificationIDs); ids != nil {
+		notificationIDs, err := parseIDs(ids)
+		if err != nil {
+			return nil, models.InvalidConfig(errors.Wrapf(err, "invalid value in annotation %q", config.AnnotationUptimeKumaNotificationIDs))
+		}
+
+		m.NotificationIDs = notificationIDs
+	}
+
+	return m, nil
+}
+
+// setMonitorType configures m for the type of the model. gRPC endpoints are
+// checked using the gRPC health checking protocol, TLS endpoints using a TCP
+// port monitor. Uptime Kuma only checks certificates of HTTP monitors, so
+// the port monitor merely checks that the endpoint accepts connections. The
+// TLS handshake and the certificate are not verified.
+func setMonitorType(m *monitor, model *models.Monitor) error {
+	monitorType := model.TypeOrDefault()
+	if monitorType == models.MonitorTypeHTTP {
+		return nil
+	}
+
+	host, port, err := model.HostPort()
+	if err != nil {
+		return errors.Wrapf(err, "invalid monitor url %q", model.URL)
+	}
+
+	switch monitorType {
+	case models.MonitorTypeGRPC:
+		m.Type = "grpc-keyword"
+		m.GRPCURL = net.JoinHostPort(host, port)
+		m.GRPCServiceName = "grpc.health.v1.Health"
+		m.GRPCMethod = "Check"
+		m.GRPCProtobuf = healthProtobuf
+		m.GRPCBody = "{}"
+		m.GRPCEnableTLS = strings.HasPrefix(model.URL, "https://")
+		m.Keyword = "SERVING"
+	case models.MonitorTypeTLS:
+		m.Type = "port"
+		m.Hostname = host
+		m.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return errors.Wrapf(err, "invalid port in monitor url %q", model.URL)
+		}
+	default:
+		return errors.Errorf("unsupported monitor type %q", monitorType)
+	}
+
+	return nil
+}
+
+func parseIDs(values []string) ([]int, error) {
+	ids := make([]int, len(values))
+
+	for i, value := range values {
+		id, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+
+		ids[i] = id
+	}
+
+	return ids, nil
+}
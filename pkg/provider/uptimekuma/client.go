@@ -0,0 +1,223 @@
+package uptimekuma
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/pkg/errors"
+)
+
+// monitor is the representation of a monitor in the Uptime Kuma REST API.
+type monitor struct {
+	ID                  int      `json:"id,omitempty"`
+	Type                string   `json:"type"`
+	Name                string   `json:"name"`
+	URL                 string   `json:"url"`
+	Method              string   `json:"method"`
+	Interval            int      `json:"interval"`
+	RetryInterval       int      `json:"retryInterval"`
+	MaxRetries          int      `json:"maxretries"`
+	MaxRedirects        int      `json:"maxredirects"`
+	AcceptedStatusCodes []string `json:"accepted_statuscodes"`
+	IgnoreTLS           bool     `json:"ignoreTls"`
+	NotificationIDs     []int    `json:"notificationIDList"`
+	Hostname            string   `json:"hostname,omitempty"`
+	Port                int      `json:"port,omitempty"`
+	GRPCURL             string   `json:"grpcUrl,omitempty"`
+	GRPCServiceName     string   `json:"grpcServiceName,omitempty"`
+	GRPCMethod          string   `json:"grpcMethod,omitempty"`
+	GRPCProtobuf        string   `json:"grpcProtobuf,omitempty"`
+	GRPCBody            string   `json:"grpcBody,omitempty"`
+	GRPCEnableTLS       bool     `json:"grpcEnableTls,omitempty"`
+	Keyword             string   `json:"keyword,omitempty"`
+}
+
+// statusError is returned by the client if the API responds with an
+// unexpected status code.
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// client is a minimal client for the Uptime Kuma REST API.
+type client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+func newClient(baseURL, username, password string, httpClient *http.Client) *client {
+	return &client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: httpClient,
+	}
+}
+
+// ListMonitors lists all monitors.
+func (c *client) ListMonitors() ([]*monitor, error) {
+	var resp struct {
+		Monitors []*monitor `json:"monitors"`
+	}
+
+	err := c.do(http.MethodGet, "/monitors", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Monitors, nil
+}
+
+// CreateMonitor creates a monitor and returns its ID.
+func (c *client) CreateMonitor(m *monitor) (int, error) {
+	var resp struct {
+		MonitorID int `json:"monitorID"`
+	}
+
+	err := c.do(http.MethodPost, "/monitors", m, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.MonitorID, nil
+}
+
+// UpdateMonitor updates the monitor with the ID of m.
+func (c *client) UpdateMonitor(m *monitor) error {
+	return c.do(http.MethodPatch, fmt.Sprintf("/monitors/%d", m.ID), m, nil)
+}
+
+// DeleteMonitor deletes the monitor with given ID.
+func (c *client) DeleteMonitor(id int) error {
+	return c.do(http.MethodDelete, fmt.Sprintf("/monitors/%d", id), nil, nil)
+}
+
+// do performs an authenticated API request. If the API rejects the access
+// token, a new token is requested and the request is retried once.
+func (c *client) do(method, path string, in, out interface{}) error {
+	err := c.doAuthenticated(method, path, in, out)
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized && c.username != "" {
+		c.setToken("")
+
+		return c.doAuthenticated(method, path, in, out)
+	}
+
+	return err
+}
+
+func (c *client) doAuthenticated(method, path string, in, out interface{}) error {
+	var body io.Reader
+
+	if in != nil {
+		buf, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	token, err := c.getToken()
+	if err != nil {
+		return errors.Wrap(err, "failed to obtain uptime kuma access token")
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.send(req, out)
+}
+
+func (c *client) send(req *http.Request, out interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return provider.ClassifyHTTPError(resp.StatusCode, resp.Header, &statusError{StatusCode: resp.StatusCode, Body: string(buf)})
+	}
+
+	if out == nil || len(buf) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(buf, out)
+}
+
+func (c *client) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = token
+}
+
+// getToken returns the current access token. If no token is present yet, a
+// new one is obtained from the API. Returns an empty token if no credentials
+// are configured.
+func (c *client) getToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" || c.username == "" {
+		return c.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("username", c.username)
+	form.Set("password", c.password)
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/login/access-token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	err = c.send(req, &resp)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = resp.AccessToken
+
+	return c.token, nil
+}
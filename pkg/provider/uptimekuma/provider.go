@@ -0,0 +1,193 @@
+package uptimekuma
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/pkg/errors"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultRequestTimeout = 30 * time.Second
+
+// Provider manages Uptime Kuma monitors.
+type Provider struct {
+	client  *client
+	config  config.UptimeKumaConfig
+	builder *builder
+}
+
+// NewProvider creates a new Uptime Kuma provider with given
+// UptimeKumaConfig.
+func NewProvider(config config.UptimeKumaConfig) *Provider {
+	httpClient := &http.Client{
+		Timeout: defaultRequestTimeout,
+	}
+
+	return &Provider{
+		client:  newClient(config.URL, config.Username, config.Password, httpClient),
+		config:  config,
+		builder: newBuilder(config.MonitorDefaults),
+	}
+}
+
+func init() {
+	provider.Register(config.ProviderUptimeKuma, newFromConfig)
+}
+
+// newFromConfig is the provider.Factory of the Uptime Kuma provider. It decodes
+// rawConfig on top of the default config.
+func newFromConfig(rawConfig json.RawMessage, _ ctrlclient.Client) (provider.Interface, error) {
+	c := config.NewDefaultUptimeKumaConfig()
+
+	err := provider.DecodeConfig(rawConfig, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProvider(c), nil
+}
+
+// Create implements provider.Interface.
+func (p *Provider) Create(model *models.Monitor) error {
+	monitor, err := p.builder.FromModel(model)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build uptime kuma monitor from model: %#v", model)
+	}
+
+	id, err := p.client.CreateMonitor(monitor)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create uptime kuma monitor: %#v", monitor)
+	}
+
+	model.ID = strconv.Itoa(id)
+
+	return nil
+}
+
+// Get implements provider.Interface.
+func (p *Provider) Get(name string) (*models.Monitor, error) {
+	monitors, err := p.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, monitor := range monitors {
+		if monitor.Name == name {
+			return monitor, nil
+		}
+	}
+
+	return nil, models.ErrMonitorNotFound
+}
+
+// List implements provider.Interface.
+func (p *Provider) List() ([]*models.Monitor, error) {
+	monitors, err := p.client.ListMonitors()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list uptime kuma monitors")
+	}
+
+	result := make([]*models.Monitor, 0, len(monitors))
+
+	for _, monitor := range monitors {
+		result = append(result, &models.Monitor{
+			ID:     strconv.Itoa(monitor.ID),
+			Name:   monitor.Name,
+			URL:    monitor.URL,
+			Config: monitor,
+		})
+	}
+
+	return result, nil
+}
+
+// Diff implements provider.Interface. It compares all fields that are set by
+// the builder.
+func (p *Provider) Diff(current, desired *models.Monitor) (models.FieldDiffs, error) {
+	actual, ok := current.Config.(*monitor)
+	if !ok {
+		return models.UnknownConfig(), nil
+	}
+
+	m, err := p.builder.FromModel(desired)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build uptime kuma monitor from model: %#v", desired)
+	}
+
+	var diffs models.FieldDiffs
+
+	diffs.Compare("Type", actual.Type, m.Type)
+	diffs.Compare("URL", actual.URL, m.URL)
+	diffs.Compare("Method", actual.Method, m.Method)
+	diffs.Compare("Interval", actual.Interval, m.Interval)
+	diffs.Compare("RetryInterval", actual.RetryInterval, m.RetryInterval)
+	diffs.Compare("MaxRetries", actual.MaxRetries, m.MaxRetries)
+	diffs.Compare("MaxRedirects", actual.MaxRedirects, m.MaxRedirects)
+	diffs.Compare("AcceptedStatusCodes", actual.AcceptedStatusCodes, m.AcceptedStatusCodes)
+	diffs.Compare("IgnoreTLS", actual.IgnoreTLS, m.IgnoreTLS)
+	diffs.Compare("NotificationIDs", actual.NotificationIDs, m.NotificationIDs)
+	diffs.Compare("Hostname", actual.Hostname, m.Hostname)
+	diffs.Compare("Port", actual.Port, m.Port)
+	diffs.Compare("GRPCURL", actual.GRPCURL, m.GRPCURL)
+	diffs.Compare("GRPCEnableTLS", actual.GRPCEnableTLS, m.GRPCEnableTLS)
+
+	return diffs, nil
+}
+
+// Render implements provider.Renderer.
+func (p *Provider) Render(model *models.Monitor) (interface{}, error) {
+	m, err := p.builder.FromModel(model)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build uptime kuma monitor from model: %#v", model)
+	}
+
+	return m, nil
+}
+
+// Update implements provider.Interface.
+func (p *Provider) Update(model *models.Monitor) error {
+	monitor, err := p.builder.FromModel(model)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build uptime kuma monitor from model: %#v", model)
+	}
+
+	err = p.client.UpdateMonitor(monitor)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update uptime kuma monitor: %#v", monitor)
+	}
+
+	return nil
+}
+
+// Delete implements provider.Interface.
+func (p *Provider) Delete(name string) error {
+	monitor, err := p.Get(name)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.Atoi(monitor.ID)
+	if err != nil {
+		return errors.Wrapf(err, "invalid uptime kuma monitor ID %q", monitor.ID)
+	}
+
+	err = p.client.DeleteMonitor(id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete uptime kuma monitor with ID %s", monitor.ID)
+	}
+
+	return nil
+}
+
+// GetIPSourceRanges implements provider.Interface. As Uptime Kuma is
+// self-hosted, the source ranges cannot be discovered and are taken from the
+// provider config instead.
+func (p *Provider) GetIPSourceRanges(_ *models.Monitor) ([]string, error) {
+	return p.config.SourceRanges, nil
+}
@@ -0,0 +1,517 @@
+package uptimekuma
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is an in-memory stand-in for the Uptime Kuma REST API.
+type fakeServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	monitors map[int]*monitor
+	logins   int
+	fail     bool
+}
+
+func newFakeServer() *fakeServer {
+	s := &fakeServer{
+		nextID:   1,
+		monitors: make(map[int]*monitor),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+func (s *fakeServer) add(m *monitor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m.ID = s.nextID
+	s.monitors[m.ID] = m
+	s.nextID++
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/login/access-token" {
+		s.logins++
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "the-token"})
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer the-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if s.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/monitors":
+		monitors := make([]*monitor, 0, len(s.monitors))
+		for id := 1; id < s.nextID; id++ {
+			if m, ok := s.monitors[id]; ok {
+				monitors = append(monitors, m)
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"monitors": monitors})
+	case r.Method == http.MethodPost && r.URL.Path == "/monitors":
+		var m monitor
+		_ = json.NewDecoder(r.Body).Decode(&m)
+		m.ID = s.nextID
+		s.monitors[m.ID] = &m
+		s.nextID++
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"monitorID": m.ID})
+	case strings.HasPrefix(r.URL.Path, "/monitors/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/monitors/"))
+		if _, ok := s.monitors[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPatch:
+			var m monitor
+			_ = json.NewDecoder(r.Body).Decode(&m)
+			s.monitors[id] = &m
+		case http.MethodDelete:
+			delete(s.monitors, id)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestProvider_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    *models.Monitor
+		config   config.UptimeKumaConfig
+		setup    func(*fakeServer)
+		validate func(*testing.T, *fakeServer)
+		expected error
+	}{
+		{
+			name: "creates monitor",
+			model: &models.Monitor{
+				Name: "my-monitor",
+				URL:  "http://my-monitor",
+			},
+			config: config.UptimeKumaConfig{
+				MonitorDefaults: config.UptimeKumaMonitorDefaults{
+					Interval:            60,
+					Method:              "GET",
+					AcceptedStatusCodes: []string{"200-299"},
+				},
+			},
+			validate: func(t *testing.T, s *fakeServer) {
+				require.Len(t, s.monitors, 1)
+				assert.Equal(t, &monitor{
+					ID:                  1,
+					Type:                "http",
+					Name:                "my-monitor",
+					URL:                 "http://my-monitor",
+					Method:              "GET",
+					Interval:            60,
+					AcceptedStatusCodes: []string{"200-299"},
+				}, s.monitors[1])
+			},
+		},
+		{
+			name: "annotations override monitor defaults",
+			model: &models.Monitor{
+				Name: "my-monitor",
+				URL:  "http://my-monitor",
+				Annotations: config.Annotations{
+					config.AnnotationUptimeKumaInterval:            "300",
+					config.AnnotationUptimeKumaMethod:              "HEAD",
+					config.AnnotationUptimeKumaNotificationIDs:     "1,2",
+					config.AnnotationUptimeKumaAcceptedStatusCodes: "200-299,301",
+				},
+			},
+			config: config.UptimeKumaConfig{
+				MonitorDefaults: config.UptimeKumaMonitorDefaults{
+					Interval: 60,
+					Method:   "GET",
+				},
+			},
+			validate: func(t *testing.T, s *fakeServer) {
+				require.Len(t, s.monitors, 1)
+				assert.Equal(t, 300, s.monitors[1].Interval)
+				assert.Equal(t, "HEAD", s.monitors[1].Method)
+				assert.Equal(t, []int{1, 2}, s.monitors[1].NotificationIDs)
+				assert.Equal(t, []string{"200-299", "301"}, s.monitors[1].AcceptedStatusCodes)
+			},
+		},
+		{
+			name: "creates grpc health check monitor for grpc endpoints",
+			model: &models.Monitor{
+				Name: "my-monitor",
+				Type: models.MonitorTypeGRPC,
+				URL:  "https://my-monitor:8443",
+			},
+			config: config.UptimeKumaConfig{
+				MonitorDefaults: config.UptimeKumaMonitorDefaults{
+					Interval: 60,
+				},
+			},
+			validate: func(t *testing.T, s *fakeServer) {
+				require.Len(t, s.monitors, 1)
+				assert.Equal(t, "grpc-keyword", s.monitors[1].Type)
+				assert.Equal(t, "my-monitor:8443", s.monitors[1].GRPCURL)
+				assert.Equal(t, "grpc.health.v1.Health", s.monitors[1].GRPCServiceName)
+				assert.Equal(t, "Check", s.monitors[1].GRPCMethod)
+				assert.Equal(t, "SERVING", s.monitors[1].Keyword)
+				assert.True(t, s.monitors[1].GRPCEnableTLS)
+			},
+		},
+		{
+			name: "creates port monitor for tls endpoints",
+			model: &models.Monitor{
+				Name: "my-monitor",
+				Type: models.MonitorTypeTLS,
+				URL:  "https://my-monitor",
+			},
+			validate: func(t *testing.T, s *fakeServer) {
+				require.Len(t, s.monitors, 1)
+				assert.Equal(t, "port", s.monitors[1].Type)
+				assert.Equal(t, "my-monitor", s.monitors[1].Hostname)
+				assert.Equal(t, 443, s.monitors[1].Port)
+			},
+		},
+		{
+			name: "do not create monitor if the ingress annotations are invalid",
+			model: &models.Monitor{
+				Name: "my-monitor",
+				URL:  "http://my-monitor",
+				Annotations: config.Annotations{
+					config.AnnotationUptimeKumaNotificationIDs: "foo",
+				},
+			},
+			validate: func(t *testing.T, s *fakeServer) {
+				assert.Len(t, s.monitors, 0)
+			},
+			expected: errors.New(`failed to build uptime kuma monitor from model: &models.Monitor{ID:"", Name:"my-monitor", Namespace:"", Type:"", URL:"http://my-monitor", Annotations:config.Annotations{"uptimekuma.ingress-monitor.bonial.com/notification-ids":"foo"}, Config:interface {}(nil)}: invalid value in annotation "uptimekuma.ingress-monitor.bonial.com/notification-ids": strconv.Atoi: parsing "foo": invalid syntax`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, s := newTestProvider(test.config)
+			defer s.Close()
+
+			if test.setup != nil {
+				test.setup(s)
+			}
+
+			err := p.Create(test.model)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "1", test.model.ID)
+			}
+
+			if test.validate != nil {
+				test.validate(t, s)
+			}
+		})
+	}
+}
+
+func TestProvider_Update(t *testing.T) {
+	p, s := newTestProvider(config.UptimeKumaConfig{})
+	defer s.Close()
+
+	s.add(&monitor{Name: "my-monitor", URL: "http://my-monitor"})
+
+	err := p.Update(&models.Monitor{
+		ID:   "1",
+		Name: "my-monitor",
+		URL:  "https://my-monitor",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://my-monitor", s.monitors[1].URL)
+}
+
+func TestProvider_Get(t *testing.T) {
+	tests := []struct {
+		name        string
+		monitorName string
+		setup       func(*fakeServer)
+		expected    *models.Monitor
+		expectedErr error
+	}{
+		{
+			name:        "returns models.ErrMonitorNotFound if monitor is not found",
+			monitorName: "my-monitor",
+			setup: func(s *fakeServer) {
+				s.add(&monitor{Name: "some-other-monitor"})
+			},
+			expectedErr: models.ErrMonitorNotFound,
+		},
+		{
+			name:        "returns monitor with name",
+			monitorName: "my-monitor",
+			setup: func(s *fakeServer) {
+				s.add(&monitor{Name: "some-other-monitor"})
+				s.add(&monitor{Name: "my-monitor", URL: "http://my-monitor"})
+			},
+			expected: &models.Monitor{
+				ID:     "2",
+				Name:   "my-monitor",
+				URL:    "http://my-monitor",
+				Config: &monitor{ID: 2, Name: "my-monitor", URL: "http://my-monitor"},
+			},
+		},
+		{
+			name:        "returns error if the api request fails",
+			monitorName: "my-monitor",
+			setup: func(s *fakeServer) {
+				s.fail = true
+			},
+			expectedErr: errors.New("failed to list uptime kuma monitors: unexpected status code 500: "),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, s := newTestProvider(config.UptimeKumaConfig{})
+			defer s.Close()
+
+			if test.setup != nil {
+				test.setup(s)
+			}
+
+			monitor, err := p.Get(test.monitorName)
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, monitor)
+			}
+		})
+	}
+}
+
+func TestProvider_Diff(t *testing.T) {
+	p, s := newTestProvider(config.UptimeKumaConfig{
+		MonitorDefaults: config.UptimeKumaMonitorDefaults{
+			Method:   "GET",
+			Interval: 60,
+		},
+	})
+	defer s.Close()
+
+	current := &models.Monitor{
+		ID:   "1",
+		Name: "my-monitor",
+		Config: &monitor{
+			ID:       1,
+			Type:     "http",
+			Name:     "my-monitor",
+			URL:      "http://my-monitor",
+			Method:   "GET",
+			Interval: 60,
+		},
+	}
+
+	diffs, err := p.Diff(current, &models.Monitor{ID: "1", Name: "my-monitor", URL: "http://my-monitor"})
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	diffs, err = p.Diff(current, &models.Monitor{
+		ID:   "1",
+		Name: "my-monitor",
+		URL:  "http://my-monitor",
+		Annotations: config.Annotations{
+			config.AnnotationUptimeKumaInterval: "30",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.FieldDiffs{{Field: "Interval", Current: "60", Desired: "30"}}, diffs)
+}
+
+func TestProvider_List(t *testing.T) {
+	p, s := newTestProvider(config.UptimeKumaConfig{})
+	defer s.Close()
+
+	s.add(&monitor{Name: "my-monitor", URL: "http://my-monitor"})
+	s.add(&monitor{Name: "other-monitor", URL: "http://other"})
+
+	monitors, err := p.List()
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Monitor{
+		{ID: "1", Name: "my-monitor", URL: "http://my-monitor", Config: &monitor{ID: 1, Name: "my-monitor", URL: "http://my-monitor"}},
+		{ID: "2", Name: "other-monitor", URL: "http://other", Config: &monitor{ID: 2, Name: "other-monitor", URL: "http://other"}},
+	}, monitors)
+}
+
+func TestProvider_Delete(t *testing.T) {
+	tests := []struct {
+		name        string
+		monitorName string
+		setup       func(*fakeServer)
+		validate    func(*testing.T, *fakeServer)
+		expected    error
+	}{
+		{
+			name:        "returns if monitor is not found",
+			monitorName: "my-monitor",
+			expected:    models.ErrMonitorNotFound,
+		},
+		{
+			name:        "deletes monitor",
+			monitorName: "my-monitor",
+			setup: func(s *fakeServer) {
+				s.add(&monitor{Name: "some-other-monitor"})
+				s.add(&monitor{Name: "my-monitor"})
+			},
+			validate: func(t *testing.T, s *fakeServer) {
+				assert.Len(t, s.monitors, 1)
+				assert.Contains(t, s.monitors, 1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, s := newTestProvider(config.UptimeKumaConfig{})
+			defer s.Close()
+
+			if test.setup != nil {
+				test.setup(s)
+			}
+
+			err := p.Delete(test.monitorName)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			if test.validate != nil {
+				test.validate(t, s)
+			}
+		})
+	}
+}
+
+func TestProvider_ReauthenticatesOnExpiredToken(t *testing.T) {
+	p, s := newTestProvider(config.UptimeKumaConfig{})
+	defer s.Close()
+
+	p.client.setToken("expired-token")
+
+	_, err := p.Get("my-monitor")
+	require.Equal(t, models.ErrMonitorNotFound, err)
+
+	assert.Equal(t, 1, s.logins)
+}
+
+func TestProvider_GetIPSourceRanges(t *testing.T) {
+	p, s := newTestProvider(config.UptimeKumaConfig{
+		SourceRanges: []string{"10.0.0.0/8"},
+	})
+	defer s.Close()
+
+	ips, err := p.GetIPSourceRanges(&models.Monitor{Name: "my-monitor"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8"}, ips)
+}
+
+func newTestProvider(cfg config.UptimeKumaConfig) (*Provider, *fakeServer) {
+	server := newFakeServer()
+
+	cfg.URL = server.URL
+	cfg.Username = "admin"
+	cfg.Password = "secret"
+
+	provider := &Provider{
+		client:  newClient(cfg.URL, cfg.Username, cfg.Password, server.Client()),
+		config:  cfg,
+		builder: newBuilder(cfg.MonitorDefaults),
+	}
+
+	return provider, server
+}
+
+func TestProvider_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations config.Annotations
+		expected    string
+	}{
+		{
+			name: "valid annotations",
+			annotations: config.Annotations{
+				config.AnnotationUptimeKumaInterval:            "30",
+				config.AnnotationUptimeKumaMethod:              "HEAD",
+				config.AnnotationUptimeKumaMaxRetries:          "0",
+				config.AnnotationUptimeKumaIgnoreTLS:           "false",
+				config.AnnotationUptimeKumaNotificationIDs:     "1, 2",
+				config.AnnotationUptimeKumaAcceptedStatusCodes: "200-299,301",
+			},
+		},
+		{
+			name: "multiple violations",
+			annotations: config.Annotations{
+				config.AnnotationUptimeKumaInterval:             "10",
+				config.AnnotationUptimeKumaMethod:               "get",
+				config.AnnotationUptimeKumaIgnoreTLS:            "nope",
+				config.AnnotationUptimeKumaNotificationIDs:      "1,foo",
+				config.AnnotationUptimeKumaAcceptedStatusCodes:  "2xx",
+				"uptimekuma.ingress-monitor.bonial.com/timeout": "10",
+			},
+			expected: `annotation "uptimekuma.ingress-monitor.bonial.com/timeout": unknown annotation; ` +
+				`annotation "uptimekuma.ingress-monitor.bonial.com/interval": 10 is less than 20; ` +
+				`annotation "uptimekuma.ingress-monitor.bonial.com/method": "get" is not one of GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS; ` +
+				`annotation "uptimekuma.ingress-monitor.bonial.com/ignore-tls": invalid bool "nope"; ` +
+				`annotation "uptimekuma.ingress-monitor.bonial.com/notification-ids": invalid notification ID "foo"; ` +
+				`annotation "uptimekuma.ingress-monitor.bonial.com/accepted-status-codes": invalid status code or range "2xx"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &Provider{}
+
+			err := p.Validate(&models.Monitor{Annotations: test.annotations})
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, test.expected, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package uptimekuma
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+)
+
+// minInterval is the shortest check interval in seconds that Uptime Kuma
+// accepts.
+const minInterval = 20
+
+var (
+	httpMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+	statusCodeRange = regexp.MustCompile(`^[1-5][0-9]{2}(-[1-5][0-9]{2})?$`)
+
+	knownAnnotations = []string{
+		config.AnnotationUptimeKumaAcceptedStatusCodes,
+		config.AnnotationUptimeKumaIgnoreTLS,
+		config.AnnotationUptimeKumaInterval,
+		config.AnnotationUptimeKumaMaxRedirects,
+		config.AnnotationUptimeKumaMaxRetries,
+		config.AnnotationUptimeKumaMethod,
+		config.AnnotationUptimeKumaNotificationIDs,
+		config.AnnotationUptimeKumaRetryInterval,
+	}
+)
+
+// Validate implements provider.Validator.
+func (p *Provider) Validate(model *models.Monitor) error {
+	return validateAnnotations(model.Annotations)
+}
+
+func validateAnnotations(anno config.Annotations) error {
+	v := config.NewAnnotationValidator(anno)
+
+	v.UnknownKeys(config.AnnotationPrefixUptimeKuma, knownAnnotations...)
+	v.Int(config.AnnotationUptimeKumaInterval, minInterval, math.MaxInt)
+	v.Int(config.AnnotationUptimeKumaRetryInterval, minInterval, math.MaxInt)
+	v.Int(config.AnnotationUptimeKumaMaxRetries, 0, math.MaxInt)
+	v.Int(config.AnnotationUptimeKumaMaxRedirects, 0, math.MaxInt)
+	v.OneOf(config.AnnotationUptimeKumaMethod, httpMethods...)
+	v.Bool(config.AnnotationUptimeKumaIgnoreTLS)
+
+	for _, value := range anno.StringSliceValue(config.AnnotationUptimeKumaNotificationIDs) {
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			v.Errorf(config.AnnotationUptimeKumaNotificationIDs, "invalid notification ID %q", value)
+		}
+	}
+
+	for _, value := range anno.StringSliceValue(config.AnnotationUptimeKumaAcceptedStatusCodes) {
+		if !statusCodeRange.MatchString(value) {
+			v.Errorf(config.AnnotationUptimeKumaAcceptedStatusCodes, "invalid status code or range %q", value)
+		}
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/provider"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statusError is returned if the webhook responds with an unexpected status
+// code.
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// Provider manages monitors by sending JSON HTTP requests to a configurable
+// webhook endpoint. See config.WebhookConfig for a description of the
+// requests.
+type Provider struct {
+	client *http.Client
+	config config.WebhookConfig
+}
+
+// NewProvider creates a new webhook provider with given WebhookConfig.
+func NewProvider(config config.WebhookConfig) *Provider {
+	return &Provider{
+		client: &http.Client{Timeout: config.Timeout.Duration},
+		config: config,
+	}
+}
+
+func init() {
+	provider.Register(config.ProviderWebhook, newFromConfig)
+}
+
+// newFromConfig is the provider.Factory of the webhook provider. It decodes
+// rawConfig on top of the default config.
+func newFromConfig(rawConfig json.RawMessage, _ client.Client) (provider.Interface, error) {
+	c := config.NewDefaultWebhookConfig()
+
+	err := provider.DecodeConfig(rawConfig, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProvider(c), nil
+}
+
+// Create implements provider.Interface. If the webhook responds with a
+// monitor, its ID is set on the model.
+func (p *Provider) Create(model *models.Monitor) error {
+	var created models.Monitor
+
+	err := p.do(http.MethodPost, "/monitors", model, &created)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create monitor %q via webhook", model.Name)
+	}
+
+	if created.ID != "" {
+		model.ID = created.ID
+	}
+
+	return nil
+}
+
+// Get implements provider.Interface.
+func (p *Provider) Get(name string) (*models.Monitor, error) {
+	var monitor models.Monitor
+
+	err := p.do(http.MethodGet, monitorPath(name), nil, &monitor)
+	if err == models.ErrMonitorNotFound {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get monitor %q via webhook", name)
+	}
+
+	return &monitor, nil
+}
+
+// Diff implements provider.Interface. The monitor returned by the webhook is
+// compared to the desired monitor, so the webhook has to respond with the
+// monitor as it was sent in the last create or update request to avoid
+// unnecessary updates. Only the annotations that configure the monitor are
+// compared, see configAnnotations.
+func (p *Provider) Diff(current, desired *models.Monitor) (models.FieldDiffs, error) {
+	var diffs models.FieldDiffs
+
+	diffs.Compare("type", current.Type, desired.Type)
+	diffs.Compare("url", current.URL, desired.URL)
+	diffs.Compare("namespace", current.Namespace, desired.Namespace)
+	diffs.Compare("annotations", configAnnotations(current.Annotations), configAnnotations(desired.Annotations))
+
+	return diffs, nil
+}
+
+// configAnnotations returns the annotations of the ingress-monitor.bonial.com
+// namespace and its subdomains, e.g. webhook.ingress-monitor.bonial.com,
+// without the state annotations. Other annotations, like the last applied
+// configuration of kubectl, and the state annotations change independently
+// of the monitor and would cause an update on every sync.
+func configAnnotations(annotations config.Annotations) config.Annotations {
+	domain := strings.TrimSuffix(config.AnnotationPrefix, "/")
+
+	filtered := make(config.Annotations, len(annotations))
+
+	for key, value := range annotations {
+		prefix, _, ok := strings.Cut(key, "/")
+		if !ok || (prefix != domain && !strings.HasSuffix(prefix, "."+domain)) {
+			continue
+		}
+
+		if !slices.Contains(config.StateAnnotations, key) {
+			filtered[key] = value
+		}
+	}
+
+	return filtered
+}
+
+// Update implements provider.Interface.
+func (p *Provider) Update(model *models.Monitor) error {
+	err := p.do(http.MethodPut, monitorPath(model.Name), model, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update monitor %q via webhook", model.Name)
+	}
+
+	return nil
+}
+
+// Delete implements provider.Interface.
+func (p *Provider) Delete(name string) error {
+	err := p.do(http.MethodDelete, monitorPath(name), nil, nil)
+	if err == models.ErrMonitorNotFound {
+		return err
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to delete monitor %q via webhook", name)
+	}
+
+	return nil
+}
+
+// List implements provider.Interface. The webhook has to respond with a JSON
+// array of monitors.
+func (p *Provider) List() ([]*models.Monitor, error) {
+	var monitors []*models.Monitor
+
+	err := p.do(http.MethodGet, "/monitors", nil, &monitors)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list monitors via webhook")
+	}
+
+	return monitors, nil
+}
+
+// GetIPSourceRanges implements provider.Interface. If the webhook responds
+// with 404, it is assumed that there are no source ranges.
+func (p *Provider) GetIPSourceRanges(model *models.Monitor) ([]string, error) {
+	var sourceRanges []string
+
+	err := p.do(http.MethodPost, "/source-ranges", model, &sourceRanges)
+	if err == models.ErrMonitorNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get source ranges for monitor %q via webhook", model.Name)
+	}
+
+	return sourceRanges, nil
+}
+
+func monitorPath(name string) string {
+	return "/monitors/" + url.PathEscape(name)
+}
+
+// do sends a request to the webhook. If in is non-nil, it is sent as JSON
+// request body. If out is non-nil, the JSON response body is decoded into
+// it. Returns models.ErrMonitorNotFound if the webhook responds with 404.
+func (p *Provider) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+
+	if in != nil {
+		buf, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(p.config.URL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if p.config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.BearerToken)
+	}
+
+	for name, value := range p.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return models.ErrMonitorNotFound
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return provider.ClassifyHTTPError(resp.StatusCode, resp.Header, &statusError{StatusCode: resp.StatusCode, Body: string(buf)})
+	}
+
+	if out == nil || len(buf) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(buf, out)
+}
@@ -0,0 +1,321 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type request struct {
+	Method  string
+	Path    string
+	Header  http.Header
+	Monitor *models.Monitor
+}
+
+func TestProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		call        func(*Provider) (interface{}, error)
+		expected    interface{}
+		expectedErr error
+		validate    func(*testing.T, []request)
+	}{
+		{
+			name: "create sends the full monitor",
+			call: func(p *Provider) (interface{}, error) {
+				return nil, p.Create(&models.Monitor{
+					Name: "my-monitor",
+					URL:  "https://my-monitor",
+					Annotations: config.Annotations{
+						"acme.com/team": "foo",
+					},
+				})
+			},
+			validate: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 1)
+				assert.Equal(t, http.MethodPost, requests[0].Method)
+				assert.Equal(t, "/api/monitors", requests[0].Path)
+				assert.Equal(t, "Bearer the-token", requests[0].Header.Get("Authorization"))
+				assert.Equal(t, "bar", requests[0].Header.Get("X-Foo"))
+				assert.Equal(t, &models.Monitor{
+					Name: "my-monitor",
+					URL:  "https://my-monitor",
+					Annotations: config.Annotations{
+						"acme.com/team": "foo",
+					},
+				}, requests[0].Monitor)
+			},
+		},
+		{
+			name: "create sets the monitor ID from the response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"id":"123","name":"my-monitor","url":"https://my-monitor"}`))
+			},
+			call: func(p *Provider) (interface{}, error) {
+				model := &models.Monitor{Name: "my-monitor", URL: "https://my-monitor"}
+
+				err := p.Create(model)
+
+				return model.ID, err
+			},
+			expected: "123",
+		},
+		{
+			name: "get returns monitor",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"id":"123","name":"my-monitor","url":"https://my-monitor"}`))
+			},
+			call: func(p *Provider) (interface{}, error) {
+				return p.Get("my-monitor")
+			},
+			expected: &models.Monitor{
+				ID:   "123",
+				Name: "my-monitor",
+				URL:  "https://my-monitor",
+			},
+			validate: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 1)
+				assert.Equal(t, http.MethodGet, requests[0].Method)
+				assert.Equal(t, "/api/monitors/my-monitor", requests[0].Path)
+			},
+		},
+		{
+			name: "list returns monitors",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`[{"id":"123","name":"my-monitor","url":"https://my-monitor"}]`))
+			},
+			call: func(p *Provider) (interface{}, error) {
+				return p.List()
+			},
+			expected: []*models.Monitor{
+				{ID: "123", Name: "my-monitor", URL: "https://my-monitor"},
+			},
+			validate: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 1)
+				assert.Equal(t, http.MethodGet, requests[0].Method)
+				assert.Equal(t, "/api/monitors", requests[0].Path)
+			},
+		},
+		{
+			name: "get returns models.ErrMonitorNotFound on 404",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			call: func(p *Provider) (interface{}, error) {
+				return p.Get("my-monitor")
+			},
+			expectedErr: models.ErrMonitorNotFound,
+		},
+		{
+			name: "diff ignores state and foreign annotations",
+			call: func(p *Provider) (interface{}, error) {
+				diffs, err := p.Diff(
+					&models.Monitor{ID: "123", Name: "my-monitor", URL: "https://my-monitor", Annotations: config.Annotations{
+						config.AnnotationEnabled:    "true",
+						config.AnnotationLastSync:   "2024-01-01T00:00:00Z",
+						"acme.com/team":             "foo",
+						"ingress-monitor.bonial.co": "foo",
+					}},
+					&models.Monitor{Name: "my-monitor", URL: "https://my-monitor", Annotations: config.Annotations{
+						config.AnnotationEnabled:     "true",
+						config.AnnotationLastSync:    "2024-01-02T00:00:00Z",
+						config.AnnotationMonitorName: "my-monitor",
+						"acme.com/team":              "bar",
+					}},
+				)
+
+				return diffs.Fields(), err
+			},
+			expected: []string{},
+		},
+		{
+			name: "diff compares url, namespace and annotations",
+			call: func(p *Provider) (interface{}, error) {
+				diffs, err := p.Diff(
+					&models.Monitor{ID: "123", Name: "my-monitor", URL: "https://my-monitor"},
+					&models.Monitor{Name: "my-monitor", URL: "https://my-monitor", Annotations: config.Annotations{"webhook.ingress-monitor.bonial.com/team": "foo"}},
+				)
+
+				return diffs.Fields(), err
+			},
+			expected: []string{"annotations"},
+			validate: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 0)
+			},
+		},
+		{
+			name: "update sends the full monitor",
+			call: func(p *Provider) (interface{}, error) {
+				return nil, p.Update(&models.Monitor{
+					ID:   "123",
+					Name: "my-monitor",
+					URL:  "https://my-monitor",
+				})
+			},
+			validate: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 1)
+				assert.Equal(t, http.MethodPut, requests[0].Method)
+				assert.Equal(t, "/api/monitors/my-monitor", requests[0].Path)
+				assert.Equal(t, "123", requests[0].Monitor.ID)
+			},
+		},
+		{
+			name: "delete returns models.ErrMonitorNotFound on 404",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			call: func(p *Provider) (interface{}, error) {
+				return nil, p.Delete("my-monitor")
+			},
+			expectedErr: models.ErrMonitorNotFound,
+		},
+		{
+			name: "returns error on unexpected status code",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("whoops"))
+			},
+			call: func(p *Provider) (interface{}, error) {
+				return nil, p.Delete("my-monitor")
+			},
+			expectedErr: errors.New(`failed to delete monitor "my-monitor" via webhook: unexpected status code 500: whoops`),
+		},
+		{
+			name: "get ip source ranges",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`["1.2.3.4/32"]`))
+			},
+			call: func(p *Provider) (interface{}, error) {
+				return p.GetIPSourceRanges(&models.Monitor{Name: "my-monitor"})
+			},
+			expected: []string{"1.2.3.4/32"},
+			validate: func(t *testing.T, requests []request) {
+				require.Len(t, requests, 1)
+				assert.Equal(t, http.MethodPost, requests[0].Method)
+				assert.Equal(t, "/api/source-ranges", requests[0].Path)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []request
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req := request{Method: r.Method, Path: r.URL.Path, Header: r.Header}
+
+				if r.Body != nil {
+					var monitor models.Monitor
+					if json.NewDecoder(r.Body).Decode(&monitor) == nil {
+						req.Monitor = &monitor
+					}
+				}
+
+				requests = append(requests, req)
+
+				if test.handler != nil {
+					test.handler(w, r)
+				}
+			}))
+			defer server.Close()
+
+			p := NewProvider(config.WebhookConfig{
+				URL:         server.URL + "/api/",
+				BearerToken: "the-token",
+				Headers:     map[string]string{"X-Foo": "bar"},
+				Timeout:     metav1.Duration{Duration: 5 * time.Second},
+			})
+
+			result, err := test.call(p)
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+
+				if test.expected != nil {
+					assert.Equal(t, test.expected, result)
+				}
+			}
+
+			if test.validate != nil {
+				test.validate(t, requests)
+			}
+		})
+	}
+}
+
+func TestProvider_DiffSettles(t *testing.T) {
+	var (
+		stored  []byte
+		methods []string
+	)
+
+	// The server responds with the monitor as it was sent in the last create
+	// or update request.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+
+		switch r.Method {
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write(stored)
+		case http.MethodPost, http.MethodPut:
+			stored, _ = io.ReadAll(r.Body)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(config.WebhookConfig{URL: server.URL, Timeout: metav1.Duration{Duration: 5 * time.Second}})
+
+	reconcile := func(annotations config.Annotations) {
+		desired := &models.Monitor{Name: "my-monitor", URL: "https://my-monitor", Annotations: annotations}
+
+		current, err := p.Get(desired.Name)
+		if errors.Is(err, models.ErrMonitorNotFound) {
+			require.NoError(t, p.Create(desired))
+			return
+		}
+
+		require.NoError(t, err)
+
+		diffs, err := p.Diff(current, desired)
+		require.NoError(t, err)
+
+		if len(diffs) > 0 {
+			require.NoError(t, p.Update(desired))
+		}
+	}
+
+	reconcile(config.Annotations{
+		config.AnnotationEnabled:                           "true",
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+	})
+
+	// The state annotations were recorded after the first sync and kubectl
+	// applied the resource again.
+	reconcile(config.Annotations{
+		config.AnnotationEnabled:                           "true",
+		config.AnnotationMonitorName:                       "my-monitor",
+		config.AnnotationLastSync:                          "2024-01-01T00:00:00Z",
+		"kubectl.kubernetes.io/last-applied-configuration": `{"metadata":{}}`,
+	})
+
+	assert.Equal(t, []string{http.MethodGet, http.MethodPost, http.MethodGet}, methods)
+}
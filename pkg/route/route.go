@@ -0,0 +1,118 @@
+// Package route builds monitor sources from OpenShift Routes. Routes are
+// handled as unstructured objects to avoid a dependency on the OpenShift API
+// types.
+package route
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Kind is the kind of OpenShift Routes.
+const Kind = "Route"
+
+// GVK is the GroupVersionKind of OpenShift Routes.
+var GVK = schema.GroupVersionKind{
+	Group:   "route.openshift.io",
+	Version: "v1",
+	Kind:    Kind,
+}
+
+// New creates an empty unstructured OpenShift Route.
+func New() *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(GVK)
+	return route
+}
+
+// NewList creates an empty unstructured list of OpenShift Routes.
+func NewList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GVK.GroupVersion().WithKind(Kind + "List"))
+	return list
+}
+
+// Validate checks if an OpenShift Route fulfills all criteria for monitoring
+// and returns an error on any violation. The Route must have a host, which
+// must not contain wildcards, and must not use a wildcard policy.
+func Validate(route *unstructured.Unstructured) error {
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	if host == "" {
+		return errors.New("route does not have a host")
+	}
+
+	if strings.Contains(host, "*") {
+		return errors.Errorf("route host %q contains wildcards", host)
+	}
+
+	policy, _, _ := unstructured.NestedString(route.Object, "spec", "wildcardPolicy")
+	if policy != "" && policy != "None" {
+		return errors.Errorf("route wildcard policy %q is not supported", policy)
+	}
+
+	return nil
+}
+
+// BuildMonitorURL builds the URL that should be monitored for the OpenShift
+// Route. HTTPS is used if the Route has a TLS termination (edge, reencrypt
+// or passthrough), HTTP otherwise. The force-https and force-http
+// annotations take precedence. The path defaults to spec.path.
+func BuildMonitorURL(route *unstructured.Unstructured) (string, error) {
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+
+	u, err := url.Parse(fmt.Sprintf("%s://%s", scheme(route), host))
+	if err != nil {
+		return "", err
+	}
+
+	if path, found := route.GetAnnotations()[config.AnnotationPathOverride]; found {
+		u.Path = path
+	} else if path, _, _ := unstructured.NestedString(route.Object, "spec", "path"); path != "/" {
+		u.Path = path
+	}
+
+	return u.String(), nil
+}
+
+func scheme(route *unstructured.Unstructured) string {
+	annotations := config.Annotations(route.GetAnnotations())
+
+	if annotations.BoolValue(config.AnnotationForceHTTPS) {
+		return "https"
+	}
+
+	if annotations.BoolValue(config.AnnotationForceHTTP) {
+		return "http"
+	}
+
+	termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+	if termination != "" {
+		return "https"
+	}
+
+	return "http"
+}
+
+// NewMonitorSource creates a MonitorSource from an OpenShift Route. The
+// route must have been validated before calling this function.
+func NewMonitorSource(route *unstructured.Unstructured) (models.MonitorSource, error) {
+	monitorURL, err := BuildMonitorURL(route)
+	if err != nil {
+		return models.MonitorSource{}, err
+	}
+
+	return models.MonitorSource{
+		Kind:        Kind,
+		Name:        route.GetName(),
+		Namespace:   route.GetNamespace(),
+		Annotations: route.GetAnnotations(),
+		URL:         monitorURL,
+	}, nil
+}
@@ -0,0 +1,165 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newRoute(annotations map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+	route := New()
+	route.SetName("foo")
+	route.SetNamespace("default")
+	route.SetAnnotations(annotations)
+	route.Object["spec"] = spec
+	return route
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    *unstructured.Unstructured
+		expected error
+	}{
+		{
+			name:  "valid route",
+			route: newRoute(nil, map[string]interface{}{"host": "foo.example.com"}),
+		},
+		{
+			name:  "valid route with wildcard policy None",
+			route: newRoute(nil, map[string]interface{}{"host": "foo.example.com", "wildcardPolicy": "None"}),
+		},
+		{
+			name:     "route without host",
+			route:    newRoute(nil, map[string]interface{}{"path": "/foo"}),
+			expected: errors.New("route does not have a host"),
+		},
+		{
+			name:     "wildcard hosts are not supported",
+			route:    newRoute(nil, map[string]interface{}{"host": "*.example.com"}),
+			expected: errors.New(`route host "*.example.com" contains wildcards`),
+		},
+		{
+			name:     "wildcard policies are not supported",
+			route:    newRoute(nil, map[string]interface{}{"host": "foo.example.com", "wildcardPolicy": "Subdomain"}),
+			expected: errors.New(`route wildcard policy "Subdomain" is not supported`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.route)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildMonitorURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    *unstructured.Unstructured
+		expected string
+	}{
+		{
+			name:     "route without TLS",
+			route:    newRoute(nil, map[string]interface{}{"host": "foo.example.com"}),
+			expected: "http://foo.example.com",
+		},
+		{
+			name: "edge termination",
+			route: newRoute(nil, map[string]interface{}{
+				"host": "foo.example.com",
+				"tls":  map[string]interface{}{"termination": "edge"},
+			}),
+			expected: "https://foo.example.com",
+		},
+		{
+			name: "reencrypt termination",
+			route: newRoute(nil, map[string]interface{}{
+				"host": "foo.example.com",
+				"tls":  map[string]interface{}{"termination": "reencrypt"},
+			}),
+			expected: "https://foo.example.com",
+		},
+		{
+			name: "passthrough termination",
+			route: newRoute(nil, map[string]interface{}{
+				"host": "foo.example.com",
+				"tls":  map[string]interface{}{"termination": "passthrough"},
+			}),
+			expected: "https://foo.example.com",
+		},
+		{
+			name:     "path of the route",
+			route:    newRoute(nil, map[string]interface{}{"host": "foo.example.com", "path": "/api"}),
+			expected: "http://foo.example.com/api",
+		},
+		{
+			name:     "root path is omitted",
+			route:    newRoute(nil, map[string]interface{}{"host": "foo.example.com", "path": "/"}),
+			expected: "http://foo.example.com",
+		},
+		{
+			name: "path override",
+			route: newRoute(
+				map[string]string{config.AnnotationPathOverride: "/health"},
+				map[string]interface{}{"host": "foo.example.com", "path": "/api"},
+			),
+			expected: "http://foo.example.com/health",
+		},
+		{
+			name: "force https",
+			route: newRoute(
+				map[string]string{config.AnnotationForceHTTPS: "true"},
+				map[string]interface{}{"host": "foo.example.com"},
+			),
+			expected: "https://foo.example.com",
+		},
+		{
+			name: "force http",
+			route: newRoute(
+				map[string]string{config.AnnotationForceHTTP: "true"},
+				map[string]interface{}{
+					"host": "foo.example.com",
+					"tls":  map[string]interface{}{"termination": "edge"},
+				},
+			),
+			expected: "http://foo.example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url, err := BuildMonitorURL(test.route)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, url)
+		})
+	}
+}
+
+func TestNewMonitorSource(t *testing.T) {
+	annotations := map[string]string{config.AnnotationEnabled: "true"}
+
+	source, err := NewMonitorSource(newRoute(annotations, map[string]interface{}{"host": "foo.example.com"}))
+	require.NoError(t, err)
+
+	expected := models.MonitorSource{
+		Kind:        "Route",
+		Name:        "foo",
+		Namespace:   "default",
+		Annotations: annotations,
+		URL:         "http://foo.example.com",
+	}
+
+	assert.Equal(t, expected, source)
+}
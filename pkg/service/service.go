@@ -0,0 +1,134 @@
+// Package service builds monitor sources from Services of type LoadBalancer,
+// which expose endpoints that bypass Ingresses and Gateways.
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultHTTPPort  = 80
+	defaultHTTPSPort = 443
+)
+
+// Validate checks if a Service fulfills all criteria for monitoring and
+// returns an error on any violation. The Service must be of type
+// LoadBalancer and its load balancer must have been provisioned, i.e. it
+// must have a hostname or IP. The port and scheme annotations must be
+// valid if present, and the port must be known either from the annotation
+// or from the Service spec.
+func Validate(svc *corev1.Service) error {
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return errors.Errorf("service is of type %q, only %q is supported", svc.Spec.Type, corev1.ServiceTypeLoadBalancer)
+	}
+
+	if loadBalancerHost(svc) == "" {
+		return errors.New("service load balancer does not have a hostname or IP yet")
+	}
+
+	annotations := config.Annotations(svc.Annotations)
+
+	if scheme, found := annotations[config.AnnotationScheme]; found && scheme != "http" && scheme != "https" {
+		return errors.Errorf("service scheme %q is invalid, must be \"http\" or \"https\"", scheme)
+	}
+
+	if port, found := annotations[config.AnnotationPort]; found {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return errors.Errorf("service port %q is invalid", port)
+		}
+	} else if len(svc.Spec.Ports) == 0 {
+		return errors.New("service does not have any ports")
+	}
+
+	return nil
+}
+
+// BuildMonitorURL builds the URL that should be monitored for the Service.
+// The host is the first hostname or IP of the load balancer. Default ports
+// of the scheme are omitted. Unvalidated Services may cause BuildMonitorURL
+// to panic.
+func BuildMonitorURL(svc *corev1.Service) (string, error) {
+	annotations := config.Annotations(svc.Annotations)
+
+	var port int
+	if _, found := annotations[config.AnnotationPort]; found {
+		port = annotations.IntValue(config.AnnotationPort)
+	} else {
+		port = int(svc.Spec.Ports[0].Port)
+	}
+
+	scheme := "http"
+	if port == defaultHTTPSPort {
+		scheme = "https"
+	}
+
+	scheme = annotations.StringValue(config.AnnotationScheme, scheme)
+
+	u, err := url.Parse(fmt.Sprintf("%s://%s", scheme, hostWithPort(loadBalancerHost(svc), scheme, port)))
+	if err != nil {
+		return "", err
+	}
+
+	if path, found := annotations[config.AnnotationPathOverride]; found {
+		u.Path = path
+	}
+
+	return u.String(), nil
+}
+
+func hostWithPort(host, scheme string, port int) string {
+	if (scheme == "http" && port == defaultHTTPPort) || (scheme == "https" && port == defaultHTTPSPort) {
+		if strings.Contains(host, ":") {
+			// IPv6 addresses have to be enclosed in brackets.
+			return fmt.Sprintf("[%s]", host)
+		}
+
+		return host
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// loadBalancerHost returns the hostname of the first load balancer ingress
+// point of svc that has a hostname or IP. If the ingress point has both,
+// the hostname is preferred.
+func loadBalancerHost(svc *corev1.Service) string {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+
+	return ""
+}
+
+// NewMonitorSource creates a MonitorSource from a Service resource. The
+// service must have been validated before calling this function.
+func NewMonitorSource(svc *corev1.Service) (models.MonitorSource, error) {
+	monitorURL, err := BuildMonitorURL(svc)
+	if err != nil {
+		return models.MonitorSource{}, err
+	}
+
+	return models.MonitorSource{
+		Kind:        "Service",
+		Name:        svc.Name,
+		Namespace:   svc.Namespace,
+		Annotations: svc.Annotations,
+		URL:         monitorURL,
+	}, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newService(annotations map[string]string, ports []int32, ingress ...corev1.LoadBalancerIngress) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress},
+		},
+	}
+
+	for _, port := range ports {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{Port: port})
+	}
+
+	return svc
+}
+
+func TestValidate(t *testing.T) {
+	lb := corev1.LoadBalancerIngress{Hostname: "lb.example.com"}
+
+	tests := []struct {
+		name     string
+		service  *corev1.Service
+		expected error
+	}{
+		{
+			name:    "valid service",
+			service: newService(nil, []int32{80}, lb),
+		},
+		{
+			name:    "valid service with port annotation and without ports",
+			service: newService(map[string]string{config.AnnotationPort: "8080"}, nil, lb),
+		},
+		{
+			name: "service of type ClusterIP is not supported",
+			service: func() *corev1.Service {
+				svc := newService(nil, []int32{80}, lb)
+				svc.Spec.Type = corev1.ServiceTypeClusterIP
+				return svc
+			}(),
+			expected: errors.New(`service is of type "ClusterIP", only "LoadBalancer" is supported`),
+		},
+		{
+			name:     "load balancer was not provisioned yet",
+			service:  newService(nil, []int32{80}),
+			expected: errors.New("service load balancer does not have a hostname or IP yet"),
+		},
+		{
+			name:     "invalid scheme",
+			service:  newService(map[string]string{config.AnnotationScheme: "tcp"}, []int32{80}, lb),
+			expected: errors.New(`service scheme "tcp" is invalid, must be "http" or "https"`),
+		},
+		{
+			name:     "invalid port",
+			service:  newService(map[string]string{config.AnnotationPort: "http"}, []int32{80}, lb),
+			expected: errors.New(`service port "http" is invalid`),
+		},
+		{
+			name:     "port out of range",
+			service:  newService(map[string]string{config.AnnotationPort: "70000"}, []int32{80}, lb),
+			expected: errors.New(`service port "70000" is invalid`),
+		},
+		{
+			name:     "service without ports",
+			service:  newService(nil, nil, lb),
+			expected: errors.New("service does not have any ports"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.service)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildMonitorURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  *corev1.Service
+		expected string
+	}{
+		{
+			name:     "http on default port",
+			service:  newService(nil, []int32{80}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expected: "http://lb.example.com",
+		},
+		{
+			name:     "https is assumed for port 443",
+			service:  newService(nil, []int32{443, 80}, corev1.LoadBalancerIngress{IP: "192.0.2.1"}),
+			expected: "https://192.0.2.1",
+		},
+		{
+			name:     "non-default ports are added",
+			service:  newService(nil, []int32{8080}, corev1.LoadBalancerIngress{IP: "192.0.2.1"}),
+			expected: "http://192.0.2.1:8080",
+		},
+		{
+			name: "hostname is preferred over ip",
+			service: newService(nil, []int32{80},
+				corev1.LoadBalancerIngress{IP: "192.0.2.1", Hostname: "lb.example.com"},
+				corev1.LoadBalancerIngress{IP: "192.0.2.2"},
+			),
+			expected: "http://lb.example.com",
+		},
+		{
+			name:     "ipv6 addresses are enclosed in brackets",
+			service:  newService(nil, []int32{443}, corev1.LoadBalancerIngress{IP: "2001:db8::1"}),
+			expected: "https://[2001:db8::1]",
+		},
+		{
+			name:     "ipv6 addresses with non-default port",
+			service:  newService(nil, []int32{8443}, corev1.LoadBalancerIngress{IP: "2001:db8::1"}),
+			expected: "http://[2001:db8::1]:8443",
+		},
+		{
+			name: "scheme, port and path from annotations",
+			service: newService(map[string]string{
+				config.AnnotationScheme:       "https",
+				config.AnnotationPort:         "8443",
+				config.AnnotationPathOverride: "/health",
+			}, []int32{80}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expected: "https://lb.example.com:8443/health",
+		},
+		{
+			name: "default port of scheme from annotation is omitted",
+			service: newService(map[string]string{
+				config.AnnotationScheme: "https",
+				config.AnnotationPort:   "443",
+			}, nil, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expected: "https://lb.example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url, err := BuildMonitorURL(test.service)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, url)
+		})
+	}
+}
+
+func TestNewMonitorSource(t *testing.T) {
+	annotations := map[string]string{config.AnnotationEnabled: "true"}
+
+	source, err := NewMonitorSource(newService(annotations, []int32{80}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}))
+	require.NoError(t, err)
+
+	expected := models.MonitorSource{
+		Kind:        "Service",
+		Name:        "foo",
+		Namespace:   "default",
+		Annotations: annotations,
+		URL:         "http://lb.example.com",
+	}
+
+	assert.Equal(t, expected, source)
+}
@@ -0,0 +1,169 @@
+// Package traefik builds monitor sources from Traefik IngressRoutes.
+// IngressRoutes are handled as unstructured objects to avoid a dependency on
+// the Traefik API types.
+package traefik
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Kind is the kind of Traefik IngressRoutes.
+const Kind = "IngressRoute"
+
+// GVK is the GroupVersionKind of Traefik IngressRoutes.
+var GVK = schema.GroupVersionKind{
+	Group:   "traefik.io",
+	Version: "v1alpha1",
+	Kind:    Kind,
+}
+
+var (
+	// hostMatcher matches the Host matcher of a Traefik rule and captures
+	// its arguments. HostRegexp and HostSNI are not matched.
+	hostMatcher = regexp.MustCompile(`\bHost\(([^)]*)\)`)
+
+	// pathMatcher matches the Path and PathPrefix matchers of a Traefik rule
+	// and captures their arguments.
+	pathMatcher = regexp.MustCompile(`\bPath(?:Prefix)?\(([^)]*)\)`)
+)
+
+// New creates an empty unstructured Traefik IngressRoute.
+func New() *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(GVK)
+	return route
+}
+
+// NewList creates an empty unstructured list of Traefik IngressRoutes.
+func NewList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GVK.GroupVersion().WithKind(Kind + "List"))
+	return list
+}
+
+// Validate checks if a Traefik IngressRoute fulfills all criteria for
+// monitoring and returns an error on any violation. At least one of the
+// IngressRoute's routes must have a match rule with a Host matcher.
+func Validate(route *unstructured.Unstructured) error {
+	host, _ := matchedHostAndPath(route)
+	if host == "" {
+		return errors.New("ingressroute does not have a Host match rule")
+	}
+
+	return nil
+}
+
+// BuildMonitorURL builds the URL that should be monitored for the Traefik
+// IngressRoute. The host and path are taken from the first route whose match
+// rule contains a Host matcher. HTTPS is used if the IngressRoute has a tls
+// section, HTTP otherwise. The force-https and force-http annotations take
+// precedence. Unvalidated IngressRoutes produce URLs without host.
+func BuildMonitorURL(route *unstructured.Unstructured) (string, error) {
+	host, path := matchedHostAndPath(route)
+
+	u, err := url.Parse(fmt.Sprintf("%s://%s", scheme(route), host))
+	if err != nil {
+		return "", err
+	}
+
+	if override, found := route.GetAnnotations()[config.AnnotationPathOverride]; found {
+		u.Path = override
+	} else if path != "/" {
+		u.Path = path
+	}
+
+	return u.String(), nil
+}
+
+// matchedHostAndPath returns the first host of the first route with a Host
+// matcher and the first Path or PathPrefix of the same route. Returns empty
+// strings if there is no such route.
+func matchedHostAndPath(route *unstructured.Unstructured) (host string, path string) {
+	routes, _, _ := unstructured.NestedSlice(route.Object, "spec", "routes")
+
+	for _, r := range routes {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		match, _ := rule["match"].(string)
+
+		hosts := matcherArgs(hostMatcher, match)
+		if len(hosts) == 0 {
+			continue
+		}
+
+		if paths := matcherArgs(pathMatcher, match); len(paths) > 0 {
+			path = paths[0]
+		}
+
+		return hosts[0], path
+	}
+
+	return "", ""
+}
+
+// matcherArgs returns the unquoted arguments of the first matcher in rule
+// that matches re. Traefik v2 allows multiple comma separated arguments.
+func matcherArgs(re *regexp.Regexp, rule string) []string {
+	m := re.FindStringSubmatch(rule)
+	if m == nil {
+		return nil
+	}
+
+	var args []string
+
+	for _, arg := range strings.Split(m[1], ",") {
+		arg = strings.Trim(strings.TrimSpace(arg), "`\"")
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+
+	return args
+}
+
+func scheme(route *unstructured.Unstructured) string {
+	annotations := config.Annotations(route.GetAnnotations())
+
+	if annotations.BoolValue(config.AnnotationForceHTTPS) {
+		return "https"
+	}
+
+	if annotations.BoolValue(config.AnnotationForceHTTP) {
+		return "http"
+	}
+
+	if _, found, _ := unstructured.NestedMap(route.Object, "spec", "tls"); found {
+		return "https"
+	}
+
+	return "http"
+}
+
+// NewMonitorSource creates a MonitorSource from a Traefik IngressRoute. The
+// route must have been validated before calling this function.
+func NewMonitorSource(route *unstructured.Unstructured) (models.MonitorSource, error) {
+	monitorURL, err := BuildMonitorURL(route)
+	if err != nil {
+		return models.MonitorSource{}, err
+	}
+
+	return models.MonitorSource{
+		Kind:        Kind,
+		Name:        route.GetName(),
+		Namespace:   route.GetNamespace(),
+		Annotations: route.GetAnnotations(),
+		URL:         monitorURL,
+	}, nil
+}
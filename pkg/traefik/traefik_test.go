@@ -0,0 +1,154 @@
+package traefik
+
+import (
+	"testing"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newIngressRoute(annotations map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+	route := New()
+	route.SetName("foo")
+	route.SetNamespace("default")
+	route.SetAnnotations(annotations)
+	route.Object["spec"] = spec
+	return route
+}
+
+func routes(matches ...string) []interface{} {
+	routes := make([]interface{}, len(matches))
+
+	for i, match := range matches {
+		routes[i] = map[string]interface{}{"match": match, "kind": "Rule"}
+	}
+
+	return routes
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    *unstructured.Unstructured
+		expected error
+	}{
+		{
+			name:  "valid ingressroute",
+			route: newIngressRoute(nil, map[string]interface{}{"routes": routes("Host(`foo.example.com`)")}),
+		},
+		{
+			name:     "ingressroute without routes",
+			route:    newIngressRoute(nil, map[string]interface{}{}),
+			expected: errors.New("ingressroute does not have a Host match rule"),
+		},
+		{
+			name:     "HostRegexp matchers are not supported",
+			route:    newIngressRoute(nil, map[string]interface{}{"routes": routes("HostRegexp(`.+\\.example\\.com`)")}),
+			expected: errors.New("ingressroute does not have a Host match rule"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.route)
+			if test.expected != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expected.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildMonitorURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    *unstructured.Unstructured
+		expected string
+	}{
+		{
+			name:     "ingressroute without TLS",
+			route:    newIngressRoute(nil, map[string]interface{}{"routes": routes("Host(`foo.example.com`)")}),
+			expected: "http://foo.example.com",
+		},
+		{
+			name: "ingressroute with TLS",
+			route: newIngressRoute(nil, map[string]interface{}{
+				"routes": routes("Host(`foo.example.com`)"),
+				"tls":    map[string]interface{}{},
+			}),
+			expected: "https://foo.example.com",
+		},
+		{
+			name: "first route with host and its path prefix",
+			route: newIngressRoute(nil, map[string]interface{}{
+				"routes": routes("PathPrefix(`/metrics`)", "Host(`foo.example.com`) && PathPrefix(`/api`)"),
+			}),
+			expected: "http://foo.example.com/api",
+		},
+		{
+			name: "first of multiple hosts",
+			route: newIngressRoute(nil, map[string]interface{}{
+				"routes": routes("Host(`foo.example.com`, `bar.example.com`) || Path(`/health`)"),
+			}),
+			expected: "http://foo.example.com/health",
+		},
+		{
+			name: "path override",
+			route: newIngressRoute(
+				map[string]string{config.AnnotationPathOverride: "/health"},
+				map[string]interface{}{"routes": routes("Host(`foo.example.com`) && PathPrefix(`/api`)")},
+			),
+			expected: "http://foo.example.com/health",
+		},
+		{
+			name: "force https",
+			route: newIngressRoute(
+				map[string]string{config.AnnotationForceHTTPS: "true"},
+				map[string]interface{}{"routes": routes("Host(`foo.example.com`)")},
+			),
+			expected: "https://foo.example.com",
+		},
+		{
+			name: "force http",
+			route: newIngressRoute(
+				map[string]string{config.AnnotationForceHTTP: "true"},
+				map[string]interface{}{
+					"routes": routes("Host(`foo.example.com`)"),
+					"tls":    map[string]interface{}{"secretName": "foo"},
+				},
+			),
+			expected: "http://foo.example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url, err := BuildMonitorURL(test.route)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, url)
+		})
+	}
+}
+
+func TestNewMonitorSource(t *testing.T) {
+	annotations := map[string]string{config.AnnotationEnabled: "true"}
+
+	source, err := NewMonitorSource(newIngressRoute(annotations, map[string]interface{}{"routes": routes("Host(`foo.example.com`)")}))
+	require.NoError(t, err)
+
+	expected := models.MonitorSource{
+		Kind:        "IngressRoute",
+		Name:        "foo",
+		Namespace:   "default",
+		Annotations: annotations,
+		URL:         "http://foo.example.com",
+	}
+
+	assert.Equal(t, expected, source)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/route"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func setupRouteController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
+	reconciler := controller.NewRouteReconciler(mgr.GetClient(), mgr.GetEventRecorder("route-monitor-controller"), svc, options)
+
+	b := builder.
+		ControllerManagedBy(mgr).
+		Named("route-monitor-controller").
+		For(route.New(), builder.WithPredicates(controller.IgnoreStateAnnotationChanges()))
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToRoutes)
+	}
+
+	return b.Complete(reconciler)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func setupServiceController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
+	reconciler := controller.NewServiceReconciler(mgr.GetClient(), mgr.GetEventRecorder("service-monitor-controller"), svc, options)
+
+	// Status updates are not filtered, as the monitor can only be created
+	// once the load balancer was provisioned.
+	b := builder.
+		ControllerManagedBy(mgr).
+		Named("service-monitor-controller").
+		For(&corev1.Service{}, builder.WithPredicates(controller.IgnoreStateAnnotationChanges()))
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToServices)
+	}
+
+	return b.Complete(reconciler)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/config"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/controller"
+	"github.com/bonial-oss/ingress-monitor-controller/pkg/monitor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+func setupTLSRouteController(mgr manager.Manager, svc monitor.Service, options *config.Options) error {
+	err := gatewayv1.Install(mgr.GetScheme())
+	if err != nil {
+		return errors.Wrapf(err, "failed to register gateway API scheme")
+	}
+
+	err = gatewayv1alpha2.Install(mgr.GetScheme())
+	if err != nil {
+		return errors.Wrapf(err, "failed to register gateway API v1alpha2 scheme")
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &gatewayv1alpha2.TLSRoute{}, controller.GatewayIndexField, controller.IndexParentGateways)
+	if err != nil {
+		return errors.Wrapf(err, "failed to index tlsroute parent gateways")
+	}
+
+	reconciler := controller.NewTLSRouteReconciler(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetEventRecorder("tlsroute-monitor-controller"), svc, options)
+
+	// See setupHTTPRouteController.
+	b := builder.
+		ControllerManagedBy(mgr).
+		Named("tlsroute-monitor-controller").
+		For(&gatewayv1alpha2.TLSRoute{}, builder.WithPredicates(controller.IgnoreStateAnnotationChanges())).
+		Watches(
+			&gatewayv1.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(reconciler.MapGatewayToTLSRoutes),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
+
+	if options.EnableMonitorPolicy {
+		b = watchPolicies(b, reconciler.MapPolicyToTLSRoutes)
+	}
+
+	err = b.Complete(reconciler)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}